@@ -0,0 +1,231 @@
+// Package agefile encrypts files to age (age-encryption.org/v1) recipients,
+// X25519 public keys or passphrases, so that key shares and reconstructed
+// keys can be kept with widely deployed tooling, and decrypted with age or
+// rage. Only encryption is implemented
+package agefile
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	ErrInvalidRecipient = errors.New("agefile: invalid recipient")
+	ErrNoRecipients     = errors.New("agefile: no recipients")
+	// age requires a passphrase recipient to be the only one
+	ErrMixedScrypt = errors.New("agefile: a passphrase must be the only recipient")
+)
+
+const (
+	intro     = "age-encryption.org/v1\n"
+	chunkSize = 64 << 10
+	// ScryptWorkFactor is log2 of the scrypt N parameter, as chosen by age
+	ScryptWorkFactor = 18
+)
+
+// stanza is a recipient stanza of the header
+type stanza struct {
+	typ  string
+	args []string
+	body []byte
+}
+
+// Recipient wraps the file key for one reader
+type Recipient interface {
+	wrap(fileKey []byte) (*stanza, error)
+}
+
+// X25519Recipient is an age1... public key
+type X25519Recipient struct {
+	key []byte
+}
+
+// ParseX25519Recipient decodes an age1... public key
+func ParseX25519Recipient(s string) (*X25519Recipient, error) {
+	hrp, key, err := bech32Decode(s)
+	if err != nil || hrp != "age" || len(key) != curve25519.PointSize {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRecipient, s)
+	}
+	return &X25519Recipient{key}, nil
+}
+
+func (r *X25519Recipient) wrap(fileKey []byte) (*stanza, error) {
+	e := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(e); err != nil {
+		return nil, err
+	}
+	share, err := curve25519.X25519(e, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := curve25519.X25519(e, r.key)
+	if err != nil {
+		return nil, err
+	}
+	salt := append(append([]byte{}, share...), r.key...)
+	body, err := aeadWrap(hkdfKey(shared, salt, "age-encryption.org/v1/X25519"), fileKey)
+	if err != nil {
+		return nil, err
+	}
+	return &stanza{"X25519", []string{b64(share)}, body}, nil
+}
+
+// ScryptRecipient is a passphrase
+type ScryptRecipient struct {
+	passphrase []byte
+	workFactor int
+}
+
+// NewScryptRecipient returns a passphrase recipient
+func NewScryptRecipient(passphrase string) (*ScryptRecipient, error) {
+	if passphrase == "" {
+		return nil, ErrInvalidRecipient
+	}
+	return &ScryptRecipient{[]byte(passphrase), ScryptWorkFactor}, nil
+}
+
+func (r *ScryptRecipient) wrap(fileKey []byte) (*stanza, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	k, err := scrypt.Key(r.passphrase, append([]byte("age-encryption.org/v1/scrypt"), salt...),
+		1<<r.workFactor, 8, 1, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	body, err := aeadWrap(k, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	return &stanza{"scrypt", []string{b64(salt), strconv.Itoa(r.workFactor)}, body}, nil
+}
+
+func b64(b []byte) string {
+	return base64.RawStdEncoding.EncodeToString(b)
+}
+
+func hkdfKey(secret, salt []byte, info string) []byte {
+	k := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(info)), k); err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// aeadWrap encrypts the file key with a zero nonce, the key being used once
+func aeadWrap(key, fileKey []byte) ([]byte, error) {
+	a, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	return a.Seal(nil, make([]byte, chacha20poly1305.NonceSize), fileKey, nil), nil
+}
+
+// Encrypt returns plaintext encrypted to recipients, in the binary age format
+func Encrypt(plaintext []byte, recipients ...Recipient) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+	for _, r := range recipients {
+		if _, ok := r.(*ScryptRecipient); ok && len(recipients) > 1 {
+			return nil, ErrMixedScrypt
+		}
+	}
+
+	fileKey := make([]byte, 16)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, err
+	}
+	hdr := bytes.NewBufferString(intro)
+	for _, r := range recipients {
+		s, err := r.wrap(fileKey)
+		if err != nil {
+			return nil, err
+		}
+		writeStanza(hdr, s)
+	}
+	hdr.WriteString("---")
+	mac := hmac.New(sha256.New, hkdfKey(fileKey, nil, "header"))
+	mac.Write(hdr.Bytes())
+	hdr.WriteString(" " + b64(mac.Sum(nil)) + "\n")
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	hdr.Write(nonce)
+	payload, err := seal(hkdfKey(fileKey, nonce, "payload"), plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return append(hdr.Bytes(), payload...), nil
+}
+
+// writeStanza writes s, with its body wrapped at 64 columns. A body whose
+// encoding fills its last line is followed by an empty line
+func writeStanza(w *bytes.Buffer, s *stanza) {
+	w.WriteString("-> " + s.typ)
+	for _, a := range s.args {
+		w.WriteString(" " + a)
+	}
+	w.WriteString("\n")
+	body := b64(s.body)
+	for len(body) >= 64 {
+		w.WriteString(body[:64] + "\n")
+		body = body[64:]
+	}
+	w.WriteString(body + "\n")
+}
+
+// seal encrypts plaintext with the STREAM construction: 64 KiB chunks, each
+// with the nonce counter || last chunk flag
+func seal(key, plaintext []byte) ([]byte, error) {
+	a, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, 0, len(plaintext)+(len(plaintext)/chunkSize+1)*a.Overhead())
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	for counter := uint64(0); ; counter++ {
+		n := len(plaintext)
+		if n > chunkSize {
+			n = chunkSize
+		}
+		binary.BigEndian.PutUint64(nonce[3:11], counter)
+		last := n == len(plaintext)
+		if last {
+			nonce[11] = 1
+		}
+		res = a.Seal(res, nonce, plaintext[:n], nil)
+		plaintext = plaintext[n:]
+		if last {
+			return res, nil
+		}
+	}
+}
+
+// Armor returns the ASCII armored form of an age file
+func Armor(b []byte) []byte {
+	res := bytes.NewBufferString("-----BEGIN AGE ENCRYPTED FILE-----\n")
+	s := base64.StdEncoding.EncodeToString(b)
+	for len(s) > 64 {
+		res.WriteString(s[:64] + "\n")
+		s = s[64:]
+	}
+	res.WriteString(s + "\n-----END AGE ENCRYPTED FILE-----\n")
+	return res.Bytes()
+}
@@ -0,0 +1,162 @@
+package agefile
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// decrypt decrypts b with age itself
+func decrypt(t *testing.T, b []byte, identities ...age.Identity) []byte {
+	t.Helper()
+	r, err := age.Decrypt(bytes.NewReader(b), identities...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// payloads are empty, one chunk, and one chunk and a byte
+func payloads(t *testing.T) [][]byte {
+	res := [][]byte{}
+	for _, n := range []int{0, chunkSize, chunkSize + 1} {
+		p := make([]byte, n)
+		if _, err := rand.Read(p); err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+func TestX25519Interop(t *testing.T) {
+	ids := []*age.X25519Identity{}
+	recipients := []Recipient{}
+	for i := 0; i < 2; i++ {
+		id, err := age.GenerateX25519Identity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := ParseX25519Recipient(id.Recipient().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids, recipients = append(ids, id), append(recipients, r)
+	}
+
+	for _, p := range payloads(t) {
+		b, err := Encrypt(p, recipients...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range ids {
+			if got := decrypt(t, b, id); !bytes.Equal(got, p) {
+				t.Fatalf("%d bytes: wrong plaintext", len(p))
+			}
+		}
+		other, err := age.GenerateX25519Identity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := age.Decrypt(bytes.NewReader(b), other); err == nil {
+			t.Fatalf("%d bytes: decrypted with another identity", len(p))
+		}
+	}
+}
+
+func TestScryptInterop(t *testing.T) {
+	r, err := NewScryptRecipient("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The lowest work factor age accepts keeps the test fast
+	r.workFactor = 10
+	id, err := age.NewScryptIdentity("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrong, err := age.NewScryptIdentity("wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range payloads(t) {
+		b, err := Encrypt(p, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := decrypt(t, b, id); !bytes.Equal(got, p) {
+			t.Fatalf("%d bytes: wrong plaintext", len(p))
+		}
+		if _, err := age.Decrypt(bytes.NewReader(b), wrong); err == nil {
+			t.Fatalf("%d bytes: decrypted with a wrong passphrase", len(p))
+		}
+	}
+}
+
+func TestArmorInterop(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseX25519Recipient(id.Recipient().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range payloads(t) {
+		b, err := Encrypt(p, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := decrypt(t, mustRead(t, armor.NewReader(bytes.NewReader(Armor(b)))), id); !bytes.Equal(got, p) {
+			t.Fatalf("%d bytes: wrong plaintext", len(p))
+		}
+	}
+}
+
+func mustRead(t *testing.T, r io.Reader) []byte {
+	t.Helper()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestRecipientErrors(t *testing.T) {
+	for _, s := range []string{"", "age1", "AGE-SECRET-KEY-1QQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQ"} {
+		if _, err := ParseX25519Recipient(s); !errors.Is(err, ErrInvalidRecipient) {
+			t.Errorf("%q: got %v, want %v", s, err, ErrInvalidRecipient)
+		}
+	}
+	if _, err := NewScryptRecipient(""); !errors.Is(err, ErrInvalidRecipient) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidRecipient)
+	}
+	if _, err := Encrypt(nil); !errors.Is(err, ErrNoRecipients) {
+		t.Fatalf("got %v, want %v", err, ErrNoRecipients)
+	}
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := ParseX25519Recipient(id.Recipient().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewScryptRecipient("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Encrypt(nil, x, s); !errors.Is(err, ErrMixedScrypt) {
+		t.Fatalf("got %v, want %v", err, ErrMixedScrypt)
+	}
+}
@@ -0,0 +1,75 @@
+package agefile
+
+import (
+	"errors"
+	"strings"
+)
+
+var errBech32 = errors.New("agefile: invalid bech32 string")
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32Decode decodes a BIP-173 bech32 string into its human readable part
+// and its data, converted to 8-bit bytes
+func bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, errBech32
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, errBech32
+	}
+	hrp := s[:sep]
+	values := make([]byte, 0, 2*len(hrp)+1+len(s)-sep-1)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	data := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		d := strings.IndexByte(bech32Charset, s[i])
+		if d < 0 {
+			return "", nil, errBech32
+		}
+		data = append(data, byte(d))
+	}
+	if bech32Polymod(append(values, data...)) != 1 {
+		return "", nil, errBech32
+	}
+	data = data[:len(data)-6]
+
+	// Regroup 5-bit values into bytes, rejecting non-zero padding
+	res := make([]byte, 0, len(data)*5/8)
+	acc, bits := uint32(0), uint(0)
+	for _, d := range data {
+		acc = acc<<5 | uint32(d)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			res = append(res, byte(acc>>bits))
+		}
+	}
+	if bits >= 5 || acc&(1<<bits-1) != 0 {
+		return "", nil, errBech32
+	}
+	return hrp, res, nil
+}
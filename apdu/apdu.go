@@ -0,0 +1,198 @@
+// Package apdu is the host side driver for hardware devices holding an
+// adjudicator key share. The device performs the mu^share operation, so the
+// share never needs to be in host memory after provisioning.
+//
+// Commands are ISO 7816-4 APDUs: CLA INS P1 P2 Lc DATA, with extended
+// length fields when DATA exceeds 255 bytes. Responses are DATA SW1 SW2,
+// with SW 0x9000 on success. Points are uncompressed, as in
+// the uncompressed encodings of this module.
+//
+//	INS  Command             Data                     Response
+//	0x01 GET_VERSION         -                        major, minor
+//	0x02 GET_SHARE_INFO      -                        share metadata (1)
+//	0x03 GET_PUBLIC_KEY      -                        g1^share
+//	0x10 LOAD_SHARE          versioned key share      -
+//	0x20 PARTIAL_ADJUDICATE  mu                       mu^share
+//
+// (1) The versioned key share encoding, without the trailing secret.
+//
+// LOAD_SHARE is only accepted once, or after a device reset. The device must
+// reject mu unless it is in the prime order subgroup, or a malicious host
+// could learn the share modulo small cofactor orders. Devices with a screen
+// should ask the operator to confirm PARTIAL_ADJUDICATE
+package apdu
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// CLA is the class byte of all commands
+const CLA = 0xe0
+
+// Instructions
+const (
+	InsGetVersion        = 0x01
+	InsGetShareInfo      = 0x02
+	InsGetPublicKey      = 0x03
+	InsLoadShare         = 0x10
+	InsPartialAdjudicate = 0x20
+)
+
+// Status words
+const (
+	SWOK              = 0x9000
+	SWDenied          = 0x6985 // Operator declined, or share already loaded
+	SWInvalidData     = 0x6a80 // Malformed data, or point not in the subgroup
+	SWNoShare         = 0x6a88 // No share loaded
+	SWInsNotSupported = 0x6d00
+	SWClaNotSupported = 0x6e00
+	SWWrongLength     = 0x6700
+	SWUnknown         = 0x6f00
+)
+
+// Maximum command data and expected response lengths of short and extended
+// APDUs
+const (
+	MaxDataLength         = 255
+	MaxExtendedDataLength = 65535
+	MaxLe                 = 256
+	MaxExtendedLe         = 65536
+)
+
+var (
+	ErrDataTooLong      = errors.New("apdu: command data too long")
+	ErrInvalidLe        = errors.New("apdu: invalid expected response length")
+	ErrResponseTooShort = errors.New("apdu: response too short")
+)
+
+// StatusError is returned when the device answers with a status word other
+// than SWOK
+type StatusError uint16
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("apdu: device returned status 0x%04x", uint16(e))
+}
+
+// Transport exchanges raw APDUs with a device (USB HID, BLE, smart card
+// reader, ...)
+type Transport interface {
+	Exchange(command []byte) ([]byte, error)
+}
+
+// Device is a hardware device holding a key share
+type Device struct {
+	t Transport
+}
+
+// NewDevice returns a driver for the device behind t
+func NewDevice(t Transport) *Device {
+	return &Device{t: t}
+}
+
+// Command encodes an APDU carrying data, without Le. Lc is always present:
+// with no data, the device reads it as Le = 256. The extended encoding is
+// used for data over MaxDataLength bytes
+func Command(ins, p1, p2 byte, data []byte) ([]byte, error) {
+	if len(data) > MaxExtendedDataLength {
+		return nil, ErrDataTooLong
+	}
+	cmd := make([]byte, 0, 7+len(data))
+	cmd = append(cmd, CLA, ins, p1, p2)
+	if len(data) > MaxDataLength {
+		cmd = append(cmd, 0, byte(len(data)>>8), byte(len(data)))
+	} else {
+		cmd = append(cmd, byte(len(data)))
+	}
+	return append(cmd, data...), nil
+}
+
+// CommandLe encodes an APDU expecting up to le bytes of response data, with
+// or without command data (ISO 7816-4 cases 2 and 4). The extended encoding
+// is used for data over MaxDataLength bytes or le over MaxLe
+func CommandLe(ins, p1, p2 byte, data []byte, le int) ([]byte, error) {
+	if len(data) > MaxExtendedDataLength {
+		return nil, ErrDataTooLong
+	}
+	if le < 1 || le > MaxExtendedLe {
+		return nil, ErrInvalidLe
+	}
+	cmd := make([]byte, 0, 9+len(data))
+	cmd = append(cmd, CLA, ins, p1, p2)
+	// Le is encoded modulo its maximum: 0 stands for 256, or 65536
+	if len(data) <= MaxDataLength && le <= MaxLe {
+		if len(data) > 0 {
+			cmd = append(cmd, byte(len(data)))
+			cmd = append(cmd, data...)
+		}
+		return append(cmd, byte(le)), nil
+	}
+	cmd = append(cmd, 0)
+	if len(data) > 0 {
+		cmd = append(cmd, byte(len(data)>>8), byte(len(data)))
+		cmd = append(cmd, data...)
+	}
+	return append(cmd, byte(le>>8), byte(le)), nil
+}
+
+// ParseResponse splits a response in its data and status word
+func ParseResponse(res []byte) ([]byte, uint16, error) {
+	if len(res) < 2 {
+		return nil, 0, ErrResponseTooShort
+	}
+	n := len(res) - 2
+	return res[:n], binary.BigEndian.Uint16(res[n:]), nil
+}
+
+func (d *Device) exchange(ins byte, data []byte) ([]byte, error) {
+	cmd, err := Command(ins, 0, 0, data)
+	if err != nil {
+		return nil, err
+	}
+	res, err := d.t.Exchange(cmd)
+	if err != nil {
+		return nil, err
+	}
+	data, sw, err := ParseResponse(res)
+	if err != nil {
+		return nil, err
+	}
+	if sw != SWOK {
+		return nil, StatusError(sw)
+	}
+	return data, nil
+}
+
+// Version returns the protocol version implemented by the device
+func (d *Device) Version() (major, minor byte, err error) {
+	res, err := d.exchange(InsGetVersion, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(res) != 2 {
+		return 0, 0, ErrResponseTooShort
+	}
+	return res[0], res[1], nil
+}
+
+// ShareInfo returns the metadata of the loaded share
+func (d *Device) ShareInfo() ([]byte, error) {
+	return d.exchange(InsGetShareInfo, nil)
+}
+
+// PublicKey returns g1^share for the loaded share
+func (d *Device) PublicKey() ([]byte, error) {
+	return d.exchange(InsGetPublicKey, nil)
+}
+
+// LoadShare provisions the device with a versioned key share encoding
+func (d *Device) LoadShare(share []byte) error {
+	_, err := d.exchange(InsLoadShare, share)
+	return err
+}
+
+// PartialAdjudicate returns mu^share, computed on the device
+func (d *Device) PartialAdjudicate(mu []byte) ([]byte, error) {
+	return d.exchange(InsPartialAdjudicate, mu)
+}
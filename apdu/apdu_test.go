@@ -0,0 +1,137 @@
+package apdu
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	data255 := bytes.Repeat([]byte{0xaa}, 255)
+	data256 := bytes.Repeat([]byte{0xaa}, 256)
+	for _, c := range []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"no data", nil, "e001020300"},
+		{"short", []byte{1, 2}, "e0010203020102"},
+		{"short max", data255, "e0010203ff" + hex.EncodeToString(data255)},
+		{"extended", data256, "e0010203000100" + hex.EncodeToString(data256)},
+	} {
+		cmd, err := Command(InsGetVersion, 2, 3, c.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(cmd); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.name, got, c.want)
+		}
+	}
+	if _, err := Command(InsLoadShare, 0, 0, make([]byte, MaxExtendedDataLength+1)); !errors.Is(err, ErrDataTooLong) {
+		t.Fatalf("got %v, want %v", err, ErrDataTooLong)
+	}
+}
+
+func TestCommandLe(t *testing.T) {
+	data256 := bytes.Repeat([]byte{0xaa}, 256)
+	for _, c := range []struct {
+		name string
+		data []byte
+		le   int
+		want string
+	}{
+		{"case 2 short", nil, 2, "e001000002"},
+		{"case 2 short, Le 256", nil, 256, "e001000000"},
+		{"case 2 extended", nil, 257, "e0010000000101"},
+		{"case 2 extended, Le 65536", nil, 65536, "e0010000000000"},
+		{"case 4 short", []byte{1, 2}, 256, "e001000002010200"},
+		{"case 4 extended by Le", []byte{1, 2}, 257, "e001000000000201020101"},
+		{"case 4 extended by Lc", data256, 1, "e0010000000100" + hex.EncodeToString(data256) + "0001"},
+	} {
+		cmd, err := CommandLe(InsGetVersion, 0, 0, c.data, c.le)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(cmd); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.name, got, c.want)
+		}
+	}
+	for _, le := range []int{0, -1, MaxExtendedLe + 1} {
+		if _, err := CommandLe(InsGetVersion, 0, 0, nil, le); !errors.Is(err, ErrInvalidLe) {
+			t.Errorf("Le %d: got %v, want %v", le, err, ErrInvalidLe)
+		}
+	}
+	if _, err := CommandLe(InsLoadShare, 0, 0, make([]byte, MaxExtendedDataLength+1), 1); !errors.Is(err, ErrDataTooLong) {
+		t.Fatalf("got %v, want %v", err, ErrDataTooLong)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	for _, res := range [][]byte{nil, {0x90}} {
+		if _, _, err := ParseResponse(res); !errors.Is(err, ErrResponseTooShort) {
+			t.Errorf("%x: got %v, want %v", res, err, ErrResponseTooShort)
+		}
+	}
+	data, sw, err := ParseResponse([]byte{1, 2, 0x6a, 0x80})
+	if err != nil || !bytes.Equal(data, []byte{1, 2}) || sw != SWInvalidData {
+		t.Fatalf("got %x %04x, %v", data, sw, err)
+	}
+}
+
+// transport answers every command with res, and records the last command
+type transport struct {
+	res  []byte
+	err  error
+	last []byte
+}
+
+func (t *transport) Exchange(cmd []byte) ([]byte, error) {
+	t.last = cmd
+	return t.res, t.err
+}
+
+func TestDevice(t *testing.T) {
+	tr := &transport{res: []byte{1, 2, 0x90, 0x00}}
+	d := NewDevice(tr)
+	major, minor, err := d.Version()
+	if err != nil || major != 1 || minor != 2 {
+		t.Fatalf("got %d.%d, %v", major, minor, err)
+	}
+	if !bytes.Equal(tr.last, []byte{CLA, InsGetVersion, 0, 0, 0}) {
+		t.Fatalf("sent %x", tr.last)
+	}
+
+	// A share too long for a short APDU goes out extended
+	if err := d.LoadShare(make([]byte, 300)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tr.last[4:7], []byte{0, 1, 44}) {
+		t.Fatalf("sent Lc %x", tr.last[4:7])
+	}
+}
+
+func TestDeviceMalformedResponses(t *testing.T) {
+	transportErr := errors.New("unplugged")
+	for _, c := range []struct {
+		name string
+		res  []byte
+		err  error
+	}{
+		{"empty", nil, ErrResponseTooShort},
+		{"one byte", []byte{0x90}, ErrResponseTooShort},
+		{"short version", []byte{1, 0x90, 0x00}, ErrResponseTooShort},
+		{"long version", []byte{1, 2, 3, 0x90, 0x00}, ErrResponseTooShort},
+		{"status", []byte{0x69, 0x85}, StatusError(SWDenied)},
+		{"status with data", []byte{1, 2, 0x6f, 0x00}, StatusError(SWUnknown)},
+		{"transport", nil, transportErr},
+	} {
+		tr := &transport{res: c.res}
+		if c.err == transportErr {
+			tr.err = transportErr
+		}
+		if _, _, err := NewDevice(tr).Version(); !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.err)
+		}
+	}
+}
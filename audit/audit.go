@@ -0,0 +1,177 @@
+// Package audit implements an append-only, tamper-evident audit log. Entries
+// are JSON lines, each committing to the previous one with a SHA-256 hash
+// chain:
+//
+//	hash = SHA-256(prev | seq | time | len(event) | event)
+//
+// with big-endian integers, the time in Unix nanoseconds and a 4 byte event
+// length. The first entry's prev is all zeros. Every n entries, and on Close,
+// the writer signs the current hash with an Ed25519 key. Modifying,
+// reordering or removing entries breaks the chain, and truncating the log
+// removes checkpoints that auditors have already seen: Verify returns the
+// last checkpoint so that it can be compared with earlier copies. Entries
+// after the last checkpoint are chained but not signed
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrBrokenChain       = errors.New("audit: broken hash chain")
+	ErrInvalidCheckpoint = errors.New("audit: invalid checkpoint signature")
+)
+
+// Entry is a log line
+type Entry struct {
+	Seq   uint64    `json:"seq"`
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	Prev  string    `json:"prev"`
+	Hash  string    `json:"hash"`
+	// Checkpoint is the Ed25519 signature of Hash, on checkpoint entries
+	Checkpoint string `json:"checkpoint,omitempty"`
+}
+
+// Head identifies a position in the log
+type Head struct {
+	Seq  uint64
+	Hash [sha256.Size]byte
+}
+
+// Log appends entries to a writer. It is safe for concurrent use
+type Log struct {
+	mu    sync.Mutex
+	w     io.Writer
+	key   ed25519.PrivateKey
+	every uint64
+	head  Head
+	// next is the sequence number of the next entry
+	next uint64
+}
+
+// NewLog returns a log writing to w, checkpointing every n entries with key.
+// A nil key disables checkpoints
+func NewLog(w io.Writer, key ed25519.PrivateKey, n int) *Log {
+	return &Log{w: w, key: key, every: uint64(n)}
+}
+
+// Resume returns a log appending to an existing log, whose last entry is at
+// head
+func Resume(w io.Writer, key ed25519.PrivateKey, n int, head Head) *Log {
+	return &Log{w: w, key: key, every: uint64(n), head: head, next: head.Seq + 1}
+}
+
+// Append appends an event
+func (l *Log) Append(event string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	checkpoint := l.key != nil && l.every > 0 && (l.next+1)%l.every == 0
+	return l.append(event, checkpoint)
+}
+
+// Write appends p as an event, without its trailing newline, so that a log
+// can back a log.Logger
+func (l *Log) Write(p []byte) (int, error) {
+	if err := l.Append(strings.TrimSuffix(string(p), "\n")); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close appends a signed checkpoint entry, if the log has a key
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.key == nil {
+		return nil
+	}
+	return l.append("checkpoint", true)
+}
+
+// Head returns the position of the last entry
+func (l *Log) Head() Head {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.head
+}
+
+func (l *Log) append(event string, checkpoint bool) error {
+	e := Entry{
+		Seq:   l.next,
+		Time:  time.Now().UTC(),
+		Event: event,
+		Prev:  hex.EncodeToString(l.head.Hash[:]),
+	}
+	h := entryHash(l.head.Hash, e.Seq, e.Time, e.Event)
+	e.Hash = hex.EncodeToString(h[:])
+	if checkpoint {
+		e.Checkpoint = hex.EncodeToString(ed25519.Sign(l.key, h[:]))
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	l.head = Head{Seq: e.Seq, Hash: h}
+	l.next++
+	return nil
+}
+
+func entryHash(prev [sha256.Size]byte, seq uint64, t time.Time, event string) [sha256.Size]byte {
+	b := make([]byte, 8+8+4, 8+8+4+len(event))
+	binary.BigEndian.PutUint64(b, seq)
+	binary.BigEndian.PutUint64(b[8:], uint64(t.UnixNano()))
+	binary.BigEndian.PutUint32(b[16:], uint32(len(event)))
+	h := sha256.New()
+	h.Write(prev[:])
+	h.Write(append(b, event...))
+	res := [sha256.Size]byte{}
+	h.Sum(res[:0])
+	return res
+}
+
+// Verify checks the hash chain and the checkpoint signatures of a log, and
+// returns the positions of its last entry and of its last checkpoint
+func Verify(r io.Reader, pub ed25519.PublicKey) (last, checkpoint Head, err error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	prev := Head{}
+	for n := uint64(0); s.Scan(); n++ {
+		e := Entry{}
+		d := json.NewDecoder(bytes.NewReader(s.Bytes()))
+		d.DisallowUnknownFields()
+		if err := d.Decode(&e); err != nil {
+			return last, checkpoint, err
+		}
+		h := entryHash(prev.Hash, e.Seq, e.Time, e.Event)
+		if e.Seq != n || e.Prev != hex.EncodeToString(prev.Hash[:]) ||
+			e.Hash != hex.EncodeToString(h[:]) {
+			return last, checkpoint, ErrBrokenChain
+		}
+		if e.Checkpoint != "" {
+			sig, err := hex.DecodeString(e.Checkpoint)
+			if err != nil || !ed25519.Verify(pub, h[:], sig) {
+				return last, checkpoint, ErrInvalidCheckpoint
+			}
+			checkpoint = Head{Seq: e.Seq, Hash: h}
+		}
+		prev = Head{Seq: e.Seq, Hash: h}
+		last = prev
+	}
+	return last, checkpoint, s.Err()
+}
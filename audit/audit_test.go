@@ -0,0 +1,245 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub, key
+}
+
+func lines(b []byte) []string {
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+// The entry layout and hash are what the package comment documents, so that
+// auditors can check logs without this package
+var entryLine = regexp.MustCompile(`^\{"seq":(\d+),"time":"([^"]+)","event":"([^"]*)","prev":"([0-9a-f]{64})","hash":"([0-9a-f]{64})"(,"checkpoint":"([0-9a-f]{128})")?\}$`)
+
+func TestFormat(t *testing.T) {
+	pub, key := newKey(t)
+	buf := &bytes.Buffer{}
+	l := NewLog(buf, key, 3)
+	for i := 0; i < 5; i++ {
+		if err := l.Append(fmt.Sprintf("event %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("}\n")) {
+		t.Fatal("missing final newline")
+	}
+
+	prev := make([]byte, sha256.Size)
+	for i, line := range lines(buf.Bytes()) {
+		m := entryLine.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("entry %d: %s", i, line)
+		}
+		if m[1] != fmt.Sprint(i) || m[4] != hex.EncodeToString(prev) {
+			t.Fatalf("entry %d: seq %s, prev %s", i, m[1], m[4])
+		}
+		ts, err := time.Parse(time.RFC3339Nano, m[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// SHA-256(prev | seq | time | len(event) | event)
+		b := make([]byte, sha256.Size+8+8+4)
+		copy(b, prev)
+		binary.BigEndian.PutUint64(b[32:], uint64(i))
+		binary.BigEndian.PutUint64(b[40:], uint64(ts.UnixNano()))
+		binary.BigEndian.PutUint32(b[48:], uint32(len(m[3])))
+		h := sha256.Sum256(append(b, m[3]...))
+		if m[5] != hex.EncodeToString(h[:]) {
+			t.Fatalf("entry %d: hash %s, want %x", i, m[5], h)
+		}
+
+		// Checkpoints every 3 entries, and on Close
+		want := i == 2 || i == 5
+		if (m[7] != "") != want {
+			t.Fatalf("entry %d: checkpoint %q", i, m[7])
+		}
+		if want {
+			sig, _ := hex.DecodeString(m[7])
+			if !ed25519.Verify(pub, h[:], sig) {
+				t.Fatalf("entry %d: invalid checkpoint", i)
+			}
+		}
+		prev = h[:]
+	}
+	if last := lines(buf.Bytes())[5]; !strings.Contains(last, `"event":"checkpoint"`) {
+		t.Fatalf("Close appended %s", last)
+	}
+}
+
+func TestNoKey(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewLog(buf, nil, 1)
+	for i := 0; i < 3; i++ {
+		if err := l.Append("event"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "checkpoint") || len(lines(buf.Bytes())) != 3 {
+		t.Fatalf("got %s", buf)
+	}
+	last, checkpoint, err := Verify(buf, nil)
+	if err != nil || last.Seq != 2 || checkpoint != (Head{}) {
+		t.Fatalf("got %v %v, %v", last, checkpoint, err)
+	}
+}
+
+// A log backs a log.Logger: each call is one entry, without its newline
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := log.New(NewLog(buf, nil, 0), "", 0)
+	logger.Printf("accepted share index=%d", 2)
+	logger.Print("done")
+	if got := lines(buf.Bytes()); len(got) != 2 || !strings.Contains(got[0], `"event":"accepted share index=2"`) ||
+		!strings.Contains(got[1], `"event":"done"`) {
+		t.Fatalf("got %s", buf)
+	}
+}
+
+// Resume appends to a log on disk, without rewriting it, and extends its chain
+func TestResume(t *testing.T) {
+	pub, key := newKey(t)
+	path := filepath.Join(t.TempDir(), "audit.log")
+	open := func() *os.File {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	f := open()
+	l := NewLog(f, key, 2)
+	for _, e := range []string{"a", "b", "c"} {
+		if err := l.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	head := l.Head()
+	f.Close()
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last, _, err := Verify(bytes.NewReader(before), pub); err != nil || last != head {
+		t.Fatalf("got %v, %v, want %v", last, err, head)
+	}
+
+	f = open()
+	l = Resume(f, key, 2, head)
+	for _, e := range []string{"d", "e"} {
+		if err := l.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(after, before) {
+		t.Fatal("resuming rewrote existing entries")
+	}
+	last, checkpoint, err := Verify(bytes.NewReader(after), pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Seq != 5 || checkpoint != last || l.Head() != last {
+		t.Fatalf("got last %v, checkpoint %v", last, checkpoint)
+	}
+}
+
+func TestConcurrentAppend(t *testing.T) {
+	pub, key := newKey(t)
+	buf := &bytes.Buffer{}
+	l := NewLog(buf, key, 4)
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if err := l.Append(fmt.Sprintf("goroutine %d event %d", g, i)); err != nil {
+					t.Error(err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	last, _, err := Verify(buf, pub)
+	if err != nil || last.Seq != 79 {
+		t.Fatalf("got %v, %v", last, err)
+	}
+}
+
+func TestVerifyTampering(t *testing.T) {
+	pub, key := newKey(t)
+	buf := &bytes.Buffer{}
+	l := NewLog(buf, key, 2)
+	for i := 0; i < 6; i++ {
+		if err := l.Append(fmt.Sprintf("event %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries := lines(buf.Bytes())
+	join := func(e ...string) string { return strings.Join(e, "\n") + "\n" }
+	otherPub, _ := newKey(t)
+
+	for _, c := range []struct {
+		name string
+		log  string
+		pub  ed25519.PublicKey
+		err  error
+	}{
+		{"modified", join(append([]string{entries[0], strings.Replace(entries[1], "event 1", "event X", 1)}, entries[2:]...)...), pub, ErrBrokenChain},
+		{"reordered", join(entries[1], entries[0]), pub, ErrBrokenChain},
+		{"removed", join(append([]string{entries[0]}, entries[2:]...)...), pub, ErrBrokenChain},
+		{"wrong key", join(entries...), otherPub, ErrInvalidCheckpoint},
+	} {
+		if _, _, err := Verify(strings.NewReader(c.log), c.pub); !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.err)
+		}
+	}
+
+	// Truncation keeps the chain, but loses checkpoints auditors have seen
+	_, full, err := Verify(strings.NewReader(join(entries...)), pub)
+	if err != nil || full.Seq != 5 {
+		t.Fatalf("got %v, %v", full, err)
+	}
+	last, truncated, err := Verify(strings.NewReader(join(entries[:4]...)), pub)
+	if err != nil || last.Seq != 3 || truncated.Seq != 3 || truncated == full {
+		t.Fatalf("got %v %v, %v", last, truncated, err)
+	}
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Export formats
+const (
+	FormatJSON = "json"
+	FormatCEF  = "cef"
+)
+
+// SchemaVersion is the version of the exported event schema. It changes
+// whenever exported fields change
+const SchemaVersion = 1
+
+var ErrUnknownFormat = errors.New("audit: unknown export format")
+
+// exported is the JSON export schema
+type exported struct {
+	Schema int `json:"schema"`
+	Entry
+}
+
+// Export reads a log from r and writes each entry to w, one per line, in the
+// given format. w is typically a log/syslog writer or a pipe to a message
+// queue producer. The log is not verified, see Verify
+func Export(w io.Writer, r io.Reader, format string) error {
+	if format != FormatJSON && format != FormatCEF {
+		return ErrUnknownFormat
+	}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		e := Entry{}
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return err
+		}
+		if err := WriteEntry(w, &e, format); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// WriteEntry writes e to w in the given format, followed by a newline
+func WriteEntry(w io.Writer, e *Entry, format string) error {
+	var line string
+	switch format {
+	case FormatJSON:
+		b, err := json.Marshal(exported{Schema: SchemaVersion, Entry: *e})
+		if err != nil {
+			return err
+		}
+		line = string(b)
+	case FormatCEF:
+		line = cef(e)
+	default:
+		return ErrUnknownFormat
+	}
+	_, err := io.WriteString(w, line+"\n")
+	return err
+}
+
+// cef formats e in ArcSight Common Event Format. Checkpoints have a higher
+// severity, as they are what auditors reconcile
+func cef(e *Entry) string {
+	severity := 3
+	if e.Checkpoint != "" {
+		severity = 5
+	}
+	header := strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	ext := strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+	return fmt.Sprintf("CEF:0|poupas|bls-vess|%d|audit|%s|%d|rt=%d cn1Label=seq cn1=%d cs1Label=hash cs1=%s cs2Label=prev cs2=%s cs3Label=checkpoint cs3=%s msg=%s",
+		SchemaVersion, header.Replace(e.Event), severity, e.Time.UnixNano()/1e6,
+		e.Seq, e.Hash, e.Prev, e.Checkpoint, ext.Replace(e.Event))
+}
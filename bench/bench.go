@@ -0,0 +1,236 @@
+// Package bench measures the cost of the scheme operations on BLS12-381,
+// to size hardware for signers and adjudicators
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"github.com/poupas/bls-vess/vess"
+)
+
+// Result is the outcome of a benchmark
+type Result struct {
+	Name string
+	testing.BenchmarkResult
+}
+
+// Throughput returns the number of operations per second
+func (r *Result) Throughput() float64 {
+	if r.NsPerOp() == 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(r.NsPerOp())
+}
+
+// Thresholds are the t-of-n committee sizes Combine is benchmarked with
+var Thresholds = [][2]int{{3, 5}, {7, 10}, {34, 50}}
+
+// BatchSizes are the numbers of messages batch and aggregate verification
+// are benchmarked with
+var BatchSizes = []int{1, 10, 100}
+
+// Run runs all benchmarks
+func Run() ([]Result, error) {
+	v, err := vess.New()
+	if err != nil {
+		return nil, err
+	}
+	sk, err := vess.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	adjSK, err := vess.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	pk := v.PublicKey(sk)
+	adj := v.AdjudicatorPublicKey(adjSK)
+	msg := []byte("Hello, World")
+	wrong := []byte("Goodbye, World")
+	sig, err := v.Sign(sk, adj, msg)
+	if err != nil {
+		return nil, err
+	}
+	raw := sig.Marshal()
+	encoded := v.EncodeVESig(sig)
+
+	results := []Result{
+		run("Sign", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := v.Sign(sk, adj, msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}),
+		run("Verify", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := v.Verify(pk, adj, msg, sig); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}),
+		run("Adjudicate", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				v.Adjudicate(adjSK, sig)
+			}
+		}),
+		run("VerifyAndAdjudicate", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := v.VerifyAndAdjudicate(adjSK, pk, msg, sig); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}),
+		// Uncompressed legacy encoding, and the compressed versioned one
+		run("MarshalVESig", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sig.Marshal()
+			}
+		}),
+		run("UnmarshalVESig", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := new(vess.VESig).Unmarshal(raw); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}),
+		run("EncodeVESig", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				v.EncodeVESig(sig)
+			}
+		}),
+		run("DecodeVESig", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := v.DecodeVESig(encoded); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}),
+	}
+
+	// Accepting and rejecting escrows should take the same time, in each
+	// operation
+	pkRaw, adjRaw := pk.Marshal(), adj.Marshal()
+	undecodable := make([]byte, len(raw))
+	for _, c := range []struct {
+		name     string
+		msg, sig []byte
+	}{
+		{"VerifyConstantWork", msg, raw},
+		{"VerifyConstantWork/rejected", wrong, raw},
+		{"VerifyConstantWork/undecodable", msg, undecodable},
+	} {
+		before := v.WorkTimings()
+		results = append(results, run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := v.VerifyConstantWork(pkRaw, adjRaw, c.msg, c.sig); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}))
+		results = append(results, workResults(c.name, before, v.WorkTimings())...)
+	}
+
+	for _, n := range BatchSizes {
+		msgs := make([][]byte, n)
+		pks := make([]*vess.PublicKey, n)
+		sigs := make([]*vess.VESig, n)
+		for i := range msgs {
+			msgs[i] = []byte(fmt.Sprintf("message %d", i))
+			sk, err := vess.GenerateKey()
+			if err != nil {
+				return nil, err
+			}
+			pks[i] = v.PublicKey(sk)
+			if sigs[i], err = v.Sign(sk, adj, msgs[i]); err != nil {
+				return nil, err
+			}
+		}
+		batch, err := v.SignBatch(context.Background(), sk, adj, msgs)
+		if err != nil {
+			return nil, err
+		}
+		agg := v.Aggregate(sigs...)
+		results = append(results,
+			run(fmt.Sprintf("VerifyBatch/%d", n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := v.VerifyBatch(context.Background(), pk, adj, msgs, batch); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}),
+			run(fmt.Sprintf("VerifyAggregate/%d", n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := v.VerifyAggregate(context.Background(), pks, adj, msgs, agg); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}),
+		)
+	}
+
+	for _, tn := range Thresholds {
+		t, n := tn[0], tn[1]
+		shares, err := v.SplitKey(adjSK, t, n)
+		if err != nil {
+			return nil, err
+		}
+		indices := make([]int, t)
+		partials := make([]*vess.Partial, t)
+		for i := range partials {
+			indices[i] = i + 1
+			partials[i] = v.PartialAdjudicate(shares[i], sig)
+		}
+		results = append(results, run(fmt.Sprintf("Combine/%d-of-%d", t, n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := v.Combine(sig, indices, partials); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}))
+	}
+
+	return results, nil
+}
+
+// workResults splits the time VerifyConstantWork took between two
+// timings into its operations
+func workResults(name string, before, after vess.WorkTimings) []Result {
+	n := int(after.Calls - before.Calls)
+	res := []Result{}
+	for _, op := range []struct {
+		name string
+		t    time.Duration
+	}{
+		{"decode", after.Decode - before.Decode},
+		{"subgroup", after.SubGroup - before.SubGroup},
+		{"hash", after.Hash - before.Hash},
+		{"pairing", after.Pairing - before.Pairing},
+	} {
+		res = append(res, Result{
+			Name:            name + "/" + op.name,
+			BenchmarkResult: testing.BenchmarkResult{N: n, T: op.t},
+		})
+	}
+	return res
+}
+
+func run(name string, f func(b *testing.B)) Result {
+	return Result{Name: name, BenchmarkResult: testing.Benchmark(f)}
+}
+
+// Print prints a table of results
+func Print(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Operation\tns/op\tops/s\tallocs/op\t")
+	for i := range results {
+		r := &results[i]
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%d\t\n", r.Name, r.NsPerOp(), r.Throughput(), r.AllocsPerOp())
+	}
+	return tw.Flush()
+}
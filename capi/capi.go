@@ -0,0 +1,243 @@
+// C API for the BLS12-381 scheme
+//
+// Build a shared or static library with:
+//   go build -buildmode=c-shared -o libvess.so ./capi
+//   go build -buildmode=c-archive -o libvess.a ./capi
+// and include vess.h, which is the stable interface. The header generated by
+// the go tool should not be used directly
+
+package main
+
+/*
+#include <stddef.h>
+#include <stdint.h>
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/poupas/bls-vess/vess"
+)
+
+// Keep in sync with vess.h
+const (
+	secretKeySize            = 32
+	publicKeySize            = 96
+	adjudicatorPublicKeySize = 288
+	vesigSize                = 384
+	partialSize              = 192
+
+	errOK       = 0
+	errInit     = -1
+	errDecode   = -2
+	errInvalid  = -3
+	errInternal = -4
+)
+
+var (
+	v       *vess.VESS
+	initErr error
+	once    sync.Once
+)
+
+func instance() *vess.VESS {
+	once.Do(func() {
+		v, initErr = vess.New()
+	})
+	if initErr != nil {
+		return nil
+	}
+	return v
+}
+
+// in returns a Go view of a C input buffer
+func in(p *C.uint8_t, n int) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(p)), n)
+}
+
+// out copies b to a C output buffer
+func out(p *C.uint8_t, b []byte) {
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(p)), len(b)), b)
+}
+
+//export vess_generate_key
+func vess_generate_key(skOut *C.uint8_t) C.int {
+	sk, err := vess.GenerateKey()
+	if err != nil {
+		return errInternal
+	}
+	out(skOut, sk.Marshal())
+	return errOK
+}
+
+//export vess_public_key
+func vess_public_key(sk *C.uint8_t, pkOut *C.uint8_t) C.int {
+	v := instance()
+	if v == nil {
+		return errInit
+	}
+	s, err := v.SecretKeyFromBytes(in(sk, secretKeySize))
+	if err != nil {
+		return errDecode
+	}
+	out(pkOut, v.PublicKey(s).Marshal())
+	return errOK
+}
+
+//export vess_adjudicator_public_key
+func vess_adjudicator_public_key(sk *C.uint8_t, apkOut *C.uint8_t) C.int {
+	v := instance()
+	if v == nil {
+		return errInit
+	}
+	s, err := v.SecretKeyFromBytes(in(sk, secretKeySize))
+	if err != nil {
+		return errDecode
+	}
+	out(apkOut, v.AdjudicatorPublicKey(s).Marshal())
+	return errOK
+}
+
+//export vess_sign
+func vess_sign(sk *C.uint8_t, apk *C.uint8_t, msg *C.uint8_t, msgLen C.size_t, vesigOut *C.uint8_t) C.int {
+	v := instance()
+	if v == nil {
+		return errInit
+	}
+	s, err := v.SecretKeyFromBytes(in(sk, secretKeySize))
+	if err != nil {
+		return errDecode
+	}
+	adj := vess.AdjudicatorPublicKey{}
+	if err := adj.Unmarshal(in(apk, adjudicatorPublicKeySize)); err != nil {
+		return errDecode
+	}
+	sig, err := v.Sign(s, &adj, C.GoBytes(unsafe.Pointer(msg), C.int(msgLen)))
+	if err != nil {
+		return errInternal
+	}
+	out(vesigOut, sig.Marshal())
+	return errOK
+}
+
+//export vess_verify
+func vess_verify(pk *C.uint8_t, apk *C.uint8_t, msg *C.uint8_t, msgLen C.size_t, vesig *C.uint8_t) C.int {
+	v := instance()
+	if v == nil {
+		return errInit
+	}
+	p := vess.PublicKey{}
+	if err := p.Unmarshal(in(pk, publicKeySize)); err != nil {
+		return errDecode
+	}
+	adj := vess.AdjudicatorPublicKey{}
+	if err := adj.Unmarshal(in(apk, adjudicatorPublicKeySize)); err != nil {
+		return errDecode
+	}
+	sig := vess.VESig{}
+	if err := sig.Unmarshal(in(vesig, vesigSize)); err != nil {
+		return errDecode
+	}
+	ok, err := v.Verify(&p, &adj, C.GoBytes(unsafe.Pointer(msg), C.int(msgLen)), &sig)
+	if err != nil {
+		return errInternal
+	}
+	if !ok {
+		return 0
+	}
+	return 1
+}
+
+//export vess_adjudicate
+func vess_adjudicate(adjSK *C.uint8_t, vesig *C.uint8_t, sigOut *C.uint8_t) C.int {
+	v := instance()
+	if v == nil {
+		return errInit
+	}
+	s, err := v.SecretKeyFromBytes(in(adjSK, secretKeySize))
+	if err != nil {
+		return errDecode
+	}
+	sig := vess.VESig{}
+	if err := sig.Unmarshal(in(vesig, vesigSize)); err != nil {
+		return errDecode
+	}
+	out(sigOut, v.Adjudicate(s, &sig).Marshal())
+	return errOK
+}
+
+//export vess_split_key
+func vess_split_key(adjSK *C.uint8_t, t C.int, n C.int, sharesOut *C.uint8_t) C.int {
+	v := instance()
+	if v == nil {
+		return errInit
+	}
+	s, err := v.SecretKeyFromBytes(in(adjSK, secretKeySize))
+	if err != nil {
+		return errDecode
+	}
+	shares, err := v.SplitKey(s, int(t), int(n))
+	if err != nil {
+		return errInvalid
+	}
+	buf := make([]byte, 0, len(shares)*secretKeySize)
+	for _, share := range shares {
+		buf = share.AppendBinary(buf)
+	}
+	out(sharesOut, buf)
+	return errOK
+}
+
+//export vess_partial_adjudicate
+func vess_partial_adjudicate(share *C.uint8_t, vesig *C.uint8_t, partialOut *C.uint8_t) C.int {
+	v := instance()
+	if v == nil {
+		return errInit
+	}
+	s, err := v.SecretKeyFromBytes(in(share, secretKeySize))
+	if err != nil {
+		return errDecode
+	}
+	sig := vess.VESig{}
+	if err := sig.Unmarshal(in(vesig, vesigSize)); err != nil {
+		return errDecode
+	}
+	out(partialOut, v.PartialAdjudicate(s, &sig).Marshal())
+	return errOK
+}
+
+//export vess_combine
+func vess_combine(vesig *C.uint8_t, indices *C.int, partials *C.uint8_t, count C.int, sigOut *C.uint8_t) C.int {
+	v := instance()
+	if v == nil {
+		return errInit
+	}
+	if count <= 0 {
+		return errInvalid
+	}
+	sig := vess.VESig{}
+	if err := sig.Unmarshal(in(vesig, vesigSize)); err != nil {
+		return errDecode
+	}
+	cidx := unsafe.Slice(indices, int(count))
+	buf := in(partials, int(count)*partialSize)
+	idx := make([]int, count)
+	pas := make([]*vess.Partial, count)
+	for i := range pas {
+		idx[i] = int(cidx[i])
+		pas[i] = &vess.Partial{}
+		if err := pas[i].Unmarshal(buf[i*partialSize : (i+1)*partialSize]); err != nil {
+			return errDecode
+		}
+	}
+	res, err := v.Combine(&sig, idx, pas)
+	if err != nil {
+		return errInvalid
+	}
+	out(sigOut, res.Marshal())
+	return errOK
+}
+
+func main() {}
@@ -0,0 +1,55 @@
+/*
+ * C API for the BLS12-381 Verifiably-Encrypted Signature Scheme
+ *
+ * All objects are passed as byte buffers of the sizes below. Functions return
+ * VESS_OK on success or a negative error code. vess_verify returns 1 for a
+ * valid signature and 0 for an invalid one.
+ */
+
+#ifndef VESS_H
+#define VESS_H
+
+#include <stddef.h>
+#include <stdint.h>
+
+#define VESS_SECRET_KEY_SIZE 32
+#define VESS_PUBLIC_KEY_SIZE 96
+#define VESS_ADJUDICATOR_PUBLIC_KEY_SIZE 288
+#define VESS_SIGNATURE_SIZE 192
+#define VESS_VESIG_SIZE 384
+#define VESS_PARTIAL_SIZE 192
+
+#define VESS_OK 0
+#define VESS_ERR_INIT -1
+#define VESS_ERR_DECODE -2
+#define VESS_ERR_INVALID -3
+#define VESS_ERR_INTERNAL -4
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+int vess_generate_key(uint8_t *sk_out);
+int vess_public_key(const uint8_t *sk, uint8_t *pk_out);
+int vess_adjudicator_public_key(const uint8_t *sk, uint8_t *apk_out);
+
+int vess_sign(const uint8_t *sk, const uint8_t *apk, const uint8_t *msg,
+              size_t msg_len, uint8_t *vesig_out);
+int vess_verify(const uint8_t *pk, const uint8_t *apk, const uint8_t *msg,
+                size_t msg_len, const uint8_t *vesig);
+int vess_adjudicate(const uint8_t *adj_sk, const uint8_t *vesig,
+                    uint8_t *sig_out);
+
+/* Threshold adjudication. shares_out holds n secret keys; share i has index
+ * i + 1. partials holds count partial adjudications, with matching indices */
+int vess_split_key(const uint8_t *adj_sk, int t, int n, uint8_t *shares_out);
+int vess_partial_adjudicate(const uint8_t *share, const uint8_t *vesig,
+                            uint8_t *partial_out);
+int vess_combine(const uint8_t *vesig, const int *indices,
+                 const uint8_t *partials, int count, uint8_t *sig_out);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif
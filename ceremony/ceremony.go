@@ -0,0 +1,160 @@
+// Package ceremony lets independent observers attest to the outputs of a key
+// ceremony. A Manifest lists the SHA-256 hash of every public artifact, such
+// as the committee roster, and the version of the tool that produced them.
+// Its encoding is byte-reproducible: artifacts are sorted by name and
+// neither a timestamp nor the toolchain is recorded, so observers who
+// recompute the hashes arrive at the same manifest digest. Artifacts must be
+// in canonical form themselves, see Committee.MarshalCanonical
+package ceremony
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// Tool names the producer of manifests
+const Tool = "bls-vess"
+
+var (
+	ErrInvalidManifest = errors.New("ceremony: invalid manifest")
+	ErrMismatch        = errors.New("ceremony: artifact hash mismatch")
+)
+
+// Artifact is the name, relative to the ceremony directory, and hash of an
+// output
+type Artifact struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest lists the artifacts of a ceremony
+type Manifest struct {
+	Tool      string     `json:"tool"`
+	Version   string     `json:"version"`
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// Version returns the module version of the running binary, "(devel)" for
+// builds outside of a tagged module
+func Version() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Version
+	}
+	return "(devel)"
+}
+
+// NewManifest hashes the named files in dir
+func NewManifest(dir string, names []string) (*Manifest, error) {
+	m := Manifest{
+		Tool:      Tool,
+		Version:   Version(),
+		Artifacts: make([]Artifact, len(names)),
+	}
+	for i, name := range names {
+		name = filepath.ToSlash(name)
+		if !isLocal(name) {
+			return nil, ErrInvalidManifest
+		}
+		sum, err := hashFile(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		m.Artifacts[i] = Artifact{name, sum}
+	}
+	sort.Slice(m.Artifacts, func(i, j int) bool { return m.Artifacts[i].Name < m.Artifacts[j].Name })
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func hashFile(dir, name string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// check rejects unsorted or duplicate artifacts, and names escaping the
+// ceremony directory
+func (m *Manifest) check() error {
+	if m.Tool != Tool || len(m.Artifacts) == 0 {
+		return ErrInvalidManifest
+	}
+	for i, a := range m.Artifacts {
+		if !isLocal(a.Name) || len(a.SHA256) != 2*sha256.Size {
+			return ErrInvalidManifest
+		}
+		if i > 0 && m.Artifacts[i-1].Name >= a.Name {
+			return ErrInvalidManifest
+		}
+	}
+	return nil
+}
+
+// isLocal reports whether name is a clean, relative slash-separated path
+// within the ceremony directory
+func isLocal(name string) bool {
+	return name != "" && path.Clean(name) == name && !path.IsAbs(name) &&
+		name != ".." && !strings.HasPrefix(name, "../")
+}
+
+// Marshal returns the canonical encoding of m: indented JSON, with a final
+// newline
+func (m *Manifest) Marshal() ([]byte, error) {
+	b, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Digest returns the hash of the canonical encoding of m, for observers to
+// attest to
+func (m *Manifest) Digest() ([32]byte, error) {
+	b, err := m.Marshal()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(b), nil
+}
+
+// Unmarshal decodes a manifest, which must be in canonical form
+func Unmarshal(b []byte) (*Manifest, error) {
+	m := Manifest{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, ErrInvalidManifest
+	}
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+	if c, err := m.Marshal(); err != nil || string(c) != string(b) {
+		return nil, ErrInvalidManifest
+	}
+	return &m, nil
+}
+
+// Verify recomputes the hash of every artifact in dir
+func (m *Manifest) Verify(dir string) error {
+	for _, a := range m.Artifacts {
+		sum, err := hashFile(dir, a.Name)
+		if err != nil {
+			return err
+		}
+		if sum != a.SHA256 {
+			return fmt.Errorf("%w: %s", ErrMismatch, a.Name)
+		}
+	}
+	return nil
+}
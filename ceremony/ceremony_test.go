@@ -0,0 +1,80 @@
+package ceremony
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeArtifacts(t *testing.T) string {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "shares"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{
+		"committee.json":  "{}\n",
+		"shares/1.pub":    "one",
+		"transcript.json": "[]\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestManifestReproducible(t *testing.T) {
+	dir := writeArtifacts(t)
+	m1, err := NewManifest(dir, []string{"transcript.json", "committee.json", "shares/1.pub"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := NewManifest(dir, []string{"shares/1.pub", "committee.json", "transcript.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b1, err := m1.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2, err := m2.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b1, b2) {
+		t.Fatal("artifact order changed the manifest")
+	}
+	if strings.Contains(string(b1), "\"go\"") {
+		t.Fatal("manifest records the toolchain")
+	}
+
+	m, err := Unmarshal(b1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Verify(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Unmarshal(bytes.TrimSuffix(b1, []byte("\n"))); !errors.Is(err, ErrInvalidManifest) {
+		t.Fatalf("non-canonical manifest: got %v, want %v", err, ErrInvalidManifest)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "committee.json"), []byte("{ }\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Verify(dir); !errors.Is(err, ErrMismatch) {
+		t.Fatalf("got %v, want %v", err, ErrMismatch)
+	}
+}
+
+func TestManifestRejectsNonLocalNames(t *testing.T) {
+	dir := writeArtifacts(t)
+	for _, name := range []string{"../committee.json", "/etc/passwd", "shares/../committee.json", ""} {
+		if _, err := NewManifest(dir, []string{name}); !errors.Is(err, ErrInvalidManifest) {
+			t.Fatalf("%q: got %v, want %v", name, err, ErrInvalidManifest)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package config loads the configuration of the bls-vess command from a JSON
+// file, with overrides from the environment:
+//
+//	VESS_CURVE       curve: bls12-381 (default), bn254 or bls12-377
+//	VESS_HASH_SUITE  hash-to-G2 suite of bls12-381: xmd-sha256 (default) or
+//	                 xof-shake256
+//	VESS_DST         domain separation tag, if not the suite default
+//	VESS_COMMITTEE   path of the committee roster, as encoded by
+//	                 Committee.MarshalJSON
+//	VESS_POLICY      path of the committee quorum rules, see package policy
+//	VESS_WALLET      path of the signer's escrow wallet, see package wallet
+//	VESS_PINS        path of the adjudicator pin store, see package pin
+//	VESS_LISTEN      host:port the adjudication service listens on
+//
+// JSON keys are the variable names, lowercased, without the VESS_ prefix.
+// Unknown keys are rejected, so a misspelt setting is not silently ignored
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/poupas/bls-vess/bls12377"
+	"github.com/poupas/bls-vess/bn254"
+	"github.com/poupas/bls-vess/pin"
+	"github.com/poupas/bls-vess/policy"
+	"github.com/poupas/bls-vess/vess"
+	"github.com/poupas/bls-vess/wallet"
+)
+
+// Curves
+const (
+	CurveBLS12381 = "bls12-381"
+	CurveBN254    = "bn254"
+	CurveBLS12377 = "bls12-377"
+)
+
+// Hash suites
+const (
+	HashXMDSHA256   = "xmd-sha256"
+	HashXOFSHAKE256 = "xof-shake256"
+)
+
+var (
+	ErrUnknownCurve     = errors.New("config: unknown curve")
+	ErrUnknownHashSuite = errors.New("config: unknown hash suite")
+	ErrInvalidListen    = errors.New("config: invalid listen address")
+	ErrPolicyCommittee  = errors.New("config: policy requires a committee")
+)
+
+type Config struct {
+	Curve     string `json:"curve"`
+	HashSuite string `json:"hash_suite"`
+	DST       string `json:"dst"`
+	Committee string `json:"committee"`
+	Policy    string `json:"policy"`
+	Wallet    string `json:"wallet"`
+	Pins      string `json:"pins"`
+	Listen    string `json:"listen"`
+}
+
+// Load reads the configuration file at path, if path is not empty, then
+// applies the environment overrides
+func Load(path string) (*Config, error) {
+	c := Config{}
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := decode(b, &c); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", path, err)
+		}
+	}
+	c.applyEnv(os.LookupEnv)
+	if c.Curve == "" {
+		c.Curve = CurveBLS12381
+	}
+	return &c, nil
+}
+
+// decode decodes a single JSON object, rejecting unknown keys and trailing
+// data
+func decode(b []byte, c *Config) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return err
+	}
+	if err := dec.Decode(&json.RawMessage{}); err != io.EOF {
+		return errors.New("trailing data after the configuration")
+	}
+	return nil
+}
+
+func (c *Config) applyEnv(lookup func(string) (string, bool)) {
+	for _, v := range []struct {
+		name  string
+		field *string
+	}{
+		{"VESS_CURVE", &c.Curve},
+		{"VESS_HASH_SUITE", &c.HashSuite},
+		{"VESS_DST", &c.DST},
+		{"VESS_COMMITTEE", &c.Committee},
+		{"VESS_POLICY", &c.Policy},
+		{"VESS_WALLET", &c.Wallet},
+		{"VESS_PINS", &c.Pins},
+		{"VESS_LISTEN", &c.Listen},
+	} {
+		if s, ok := lookup(v.name); ok {
+			*v.field = s
+		}
+	}
+}
+
+// Check validates the configuration: the scheme options are accepted, the
+// committee roster, if any, decodes and is well formed, the policy rules
+// parse and name its members, the wallet and pin store, if they exist,
+// decode, and the listen address is a host and port
+func (c *Config) Check() error {
+	members, threshold, err := c.checkCommittee()
+	if err != nil {
+		return err
+	}
+	if c.Policy != "" {
+		if members == nil {
+			return ErrPolicyCommittee
+		}
+		f, err := os.Open(c.Policy)
+		if err != nil {
+			return err
+		}
+		q, err := policy.Parse(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if err := q.Check(members, threshold); err != nil {
+			return err
+		}
+	}
+	if c.Wallet != "" {
+		if _, err := wallet.Open(c.Wallet); err != nil {
+			return err
+		}
+	}
+	if c.Pins != "" {
+		if _, err := pin.Open(c.Pins); err != nil {
+			return err
+		}
+	}
+	if c.Listen != "" {
+		_, port, err := net.SplitHostPort(c.Listen)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidListen, err)
+		}
+		if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+			return fmt.Errorf("%w: port %q", ErrInvalidListen, port)
+		}
+	}
+	return nil
+}
+
+// checkCommittee checks the scheme options and the committee roster, and
+// returns the member IDs and threshold of the committee, if any
+func (c *Config) checkCommittee() ([]string, int, error) {
+	var roster []byte
+	if c.Committee != "" {
+		b, err := os.ReadFile(c.Committee)
+		if err != nil {
+			return nil, 0, err
+		}
+		roster = b
+	}
+
+	switch c.Curve {
+	case CurveBLS12381:
+		v, err := c.VESS()
+		if err != nil {
+			return nil, 0, err
+		}
+		if roster != nil {
+			cm := vess.Committee{}
+			if err := json.Unmarshal(roster, &cm); err != nil {
+				return nil, 0, fmt.Errorf("config: committee: %w", err)
+			}
+			return memberIDs(cm.Members), cm.Threshold, v.CheckCommittee(&cm)
+		}
+	case CurveBN254:
+		if c.HashSuite != "" {
+			return nil, 0, ErrUnknownHashSuite
+		}
+		v, err := bn254.New(bn254Options(c)...)
+		if err != nil {
+			return nil, 0, err
+		}
+		if roster != nil {
+			cm := bn254.Committee{}
+			if err := json.Unmarshal(roster, &cm); err != nil {
+				return nil, 0, fmt.Errorf("config: committee: %w", err)
+			}
+			return memberIDs(cm.Members), cm.Threshold, v.CheckCommittee(&cm)
+		}
+	case CurveBLS12377:
+		if c.HashSuite != "" {
+			return nil, 0, ErrUnknownHashSuite
+		}
+		v, err := bls12377.New(bls12377Options(c)...)
+		if err != nil {
+			return nil, 0, err
+		}
+		if roster != nil {
+			cm := bls12377.Committee{}
+			if err := json.Unmarshal(roster, &cm); err != nil {
+				return nil, 0, fmt.Errorf("config: committee: %w", err)
+			}
+			return memberIDs(cm.Members), cm.Threshold, v.CheckCommittee(&cm)
+		}
+	default:
+		return nil, 0, ErrUnknownCurve
+	}
+	return nil, 0, nil
+}
+
+func memberIDs(members []vess.Member) []string {
+	ids := make([]string, len(members))
+	for i, m := range members {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+// VESS returns the BLS12-381 instance described by the configuration
+func (c *Config) VESS() (*vess.VESS, error) {
+	if c.Curve != CurveBLS12381 {
+		return nil, ErrUnknownCurve
+	}
+	opts := []vess.Option{}
+	switch c.HashSuite {
+	case "", HashXMDSHA256:
+	case HashXOFSHAKE256:
+		opts = append(opts, vess.WithHashSuite(vess.SuiteXOFSHAKE256))
+	default:
+		return nil, ErrUnknownHashSuite
+	}
+	if c.DST != "" {
+		opts = append(opts, vess.WithDST([]byte(c.DST)))
+	}
+	return vess.New(opts...)
+}
+
+func bn254Options(c *Config) []bn254.Option {
+	if c.DST == "" {
+		return nil
+	}
+	return []bn254.Option{bn254.WithDST([]byte(c.DST))}
+}
+
+func bls12377Options(c *Config) []bls12377.Option {
+	if c.DST == "" {
+		return nil
+	}
+	return []bls12377.Option{bls12377.WithDST([]byte(c.DST))}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, s string) string {
+	path := filepath.Join(t.TempDir(), "vess.json")
+	if err := os.WriteFile(path, []byte(s), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	path := writeConfig(t, `{"curve": "bn254", "hash_suit": "xof-shake256"}`)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "hash_suit") {
+		t.Fatalf("got %v, want an unknown field error", err)
+	}
+}
+
+func TestLoadRejectsTrailingData(t *testing.T) {
+	path := writeConfig(t, `{"curve": "bn254"} {"curve": "bls12-377"}`)
+	if _, err := Load(path); err == nil {
+		t.Fatal("trailing object accepted")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `{
+		"curve": "bls12-381",
+		"hash_suite": "xof-shake256",
+		"wallet": "/var/lib/vess/wallet.json",
+		"pins": "/var/lib/vess/pins.json",
+		"listen": "127.0.0.1:8443"
+	}`)
+	t.Setenv("VESS_LISTEN", "[::1]:9443")
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Config{
+		Curve:     CurveBLS12381,
+		HashSuite: HashXOFSHAKE256,
+		Wallet:    "/var/lib/vess/wallet.json",
+		Pins:      "/var/lib/vess/pins.json",
+		Listen:    "[::1]:9443",
+	}
+	if *c != want {
+		t.Fatalf("got %+v, want %+v", *c, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name string
+		c    Config
+		err  error
+	}{
+		{"defaults", Config{Curve: CurveBLS12381}, nil},
+		{"missing wallet", Config{Curve: CurveBLS12381, Wallet: filepath.Join(dir, "wallet.json")}, nil},
+		{"listen", Config{Curve: CurveBLS12381, Listen: ":8443"}, nil},
+		{"listen without port", Config{Curve: CurveBLS12381, Listen: "localhost"}, ErrInvalidListen},
+		{"listen port zero", Config{Curve: CurveBLS12381, Listen: "localhost:0"}, ErrInvalidListen},
+		{"listen port range", Config{Curve: CurveBLS12381, Listen: "localhost:65536"}, ErrInvalidListen},
+		{"policy without committee", Config{Curve: CurveBLS12381, Policy: filepath.Join(dir, "rules")}, ErrPolicyCommittee},
+		{"unknown curve", Config{Curve: "p256"}, ErrUnknownCurve},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.c.Check(); !errors.Is(err, tc.err) {
+				t.Fatalf("got %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Package convert moves points and scalars between herumi's and gnark's
+// BLS12-381 representations.
+//
+// Both libraries keep field elements as 64-bit limbs in Montgomery form,
+// with R = 2^384 for Fp and 2^256 for Fr, and herumi keeps points in
+// Jacobian coordinates, as gnark's G1Jac and G2Jac do. Points and scalars are
+// converted by copying limbs, with no allocation. Points are checked to be on
+// the curve and in the subgroup on the herumi side, as gnark's subgroup check
+// allocates.
+//
+// herumi must be initialised for BLS12-381 in ETH mode, as vess.New does,
+// before any of these are called
+package convert
+
+import (
+	"errors"
+	"unsafe"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+
+	"github.com/herumi/bls-eth-go-binary/bls"
+)
+
+var (
+	errInvalidLength = errors.New("invalid encoding length")
+	errInvalidPoint  = errors.New("invalid point")
+)
+
+// The limb copies below need herumi's Fp and Fr to be laid out as gnark's
+var (
+	_ [unsafe.Sizeof(bls.Fp{}) - unsafe.Sizeof(fp.Element{})]byte
+	_ [unsafe.Sizeof(fp.Element{}) - unsafe.Sizeof(bls.Fp{})]byte
+	_ [unsafe.Sizeof(bls.Fr{}) - unsafe.Sizeof(fr.Element{})]byte
+	_ [unsafe.Sizeof(fr.Element{}) - unsafe.Sizeof(bls.Fr{})]byte
+)
+
+func fpToGnark(x *bls.Fp) fp.Element {
+	return *(*fp.Element)(unsafe.Pointer(x))
+}
+
+func fpFromGnark(x *fp.Element) bls.Fp {
+	return *(*bls.Fp)(unsafe.Pointer(x))
+}
+
+func g1Valid(p *bls.G1) bool {
+	return p.IsValid() && p.IsValidOrder()
+}
+
+func g2Valid(p *bls.G2) bool {
+	return p.IsValid() && p.IsValidOrder()
+}
+
+// G1ToGnark sets dst to the herumi point src
+func G1ToGnark(dst *gnark.G1Affine, src *bls.G1) error {
+	if !g1Valid(src) {
+		return errInvalidPoint
+	}
+	jac := gnark.G1Jac{X: fpToGnark(&src.X), Y: fpToGnark(&src.Y), Z: fpToGnark(&src.Z)}
+	dst.FromJacobian(&jac)
+	return nil
+}
+
+// G1FromGnark sets dst to the gnark point src, or to zero if src is invalid
+func G1FromGnark(dst *bls.G1, src *gnark.G1Affine) error {
+	*dst = bls.G1{}
+	if !src.IsInfinity() {
+		one := fp.One()
+		dst.X, dst.Y, dst.Z = fpFromGnark(&src.X), fpFromGnark(&src.Y), fpFromGnark(&one)
+	}
+	if !g1Valid(dst) {
+		*dst = bls.G1{}
+		return errInvalidPoint
+	}
+	return nil
+}
+
+// G2ToGnark sets dst to the herumi point src
+func G2ToGnark(dst *gnark.G2Affine, src *bls.G2) error {
+	if !g2Valid(src) {
+		return errInvalidPoint
+	}
+	jac := gnark.G2Jac{}
+	jac.X.A0, jac.X.A1 = fpToGnark(&src.X.D[0]), fpToGnark(&src.X.D[1])
+	jac.Y.A0, jac.Y.A1 = fpToGnark(&src.Y.D[0]), fpToGnark(&src.Y.D[1])
+	jac.Z.A0, jac.Z.A1 = fpToGnark(&src.Z.D[0]), fpToGnark(&src.Z.D[1])
+	dst.FromJacobian(&jac)
+	return nil
+}
+
+// G2FromGnark sets dst to the gnark point src, or to zero if src is invalid
+func G2FromGnark(dst *bls.G2, src *gnark.G2Affine) error {
+	*dst = bls.G2{}
+	if !src.IsInfinity() {
+		one := fp.One()
+		dst.X.D[0], dst.X.D[1] = fpFromGnark(&src.X.A0), fpFromGnark(&src.X.A1)
+		dst.Y.D[0], dst.Y.D[1] = fpFromGnark(&src.Y.A0), fpFromGnark(&src.Y.A1)
+		dst.Z.D[0] = fpFromGnark(&one)
+	}
+	if !g2Valid(dst) {
+		*dst = bls.G2{}
+		return errInvalidPoint
+	}
+	return nil
+}
+
+// FrToGnark sets dst to the herumi scalar src
+func FrToGnark(dst *fr.Element, src *bls.Fr) {
+	*dst = *(*fr.Element)(unsafe.Pointer(src))
+}
+
+// FrFromGnark sets dst to the gnark scalar src. gnark scalars are always
+// reduced, so it never fails
+func FrFromGnark(dst *bls.Fr, src *fr.Element) error {
+	*dst = *(*bls.Fr)(unsafe.Pointer(src))
+	return nil
+}
+
+// Compressed forms. herumi's Serialize and gnark's Bytes produce the same
+// 48 byte (G1) and 96 byte (G2) encodings
+
+// G1CompressedToGnark sets dst from a compressed herumi encoding
+func G1CompressedToGnark(dst *gnark.G1Affine, b []byte) error {
+	if len(b) != gnark.SizeOfG1AffineCompressed {
+		return errInvalidLength
+	}
+	_, err := dst.SetBytes(b)
+	return err
+}
+
+// G1CompressedFromGnark sets dst from a compressed gnark encoding
+func G1CompressedFromGnark(dst *bls.G1, b *[gnark.SizeOfG1AffineCompressed]byte) error {
+	return dst.Deserialize(b[:])
+}
+
+// G2CompressedToGnark sets dst from a compressed herumi encoding
+func G2CompressedToGnark(dst *gnark.G2Affine, b []byte) error {
+	if len(b) != gnark.SizeOfG2AffineCompressed {
+		return errInvalidLength
+	}
+	_, err := dst.SetBytes(b)
+	return err
+}
+
+// G2CompressedFromGnark sets dst from a compressed gnark encoding
+func G2CompressedFromGnark(dst *bls.G2, b *[gnark.SizeOfG2AffineCompressed]byte) error {
+	return dst.Deserialize(b[:])
+}
@@ -0,0 +1,190 @@
+package convert_test
+
+import (
+	"testing"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/herumi/bls-eth-go-binary/bls"
+
+	"github.com/poupas/bls-vess/convert"
+	"github.com/poupas/bls-vess/vess"
+)
+
+// The expected values are decoded from herumi's own serialization
+
+func initHerumi(t *testing.T) {
+	if _, err := vess.New(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// herumiG1 returns a random point in Jacobian coordinates with Z != 1
+func herumiG1() *bls.G1 {
+	sk := bls.SecretKey{}
+	sk.SetByCSPRNG()
+	p := bls.CastFromPublicKey(sk.GetPublicKey())
+	bls.G1Add(p, p, p)
+	return p
+}
+
+func herumiG2() *bls.G2 {
+	sk := bls.SecretKey{}
+	sk.SetByCSPRNG()
+	p := bls.CastFromSign(sk.SignByte([]byte("convert")))
+	bls.G2Add(p, p, p)
+	return p
+}
+
+func TestG1(t *testing.T) {
+	initHerumi(t)
+	for i, src := range []*bls.G1{herumiG1(), herumiG1(), {}} {
+		want := gnark.G1Affine{}
+		if _, err := want.SetBytes(src.SerializeUncompressed()); err != nil {
+			t.Fatal(err)
+		}
+		got := gnark.G1Affine{}
+		if err := convert.G1ToGnark(&got, src); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(&want) {
+			t.Fatalf("point %d: G1ToGnark differs from herumi's encoding", i)
+		}
+		back := bls.G1{}
+		if err := convert.G1FromGnark(&back, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !back.IsEqual(src) {
+			t.Fatalf("point %d: G1FromGnark does not round-trip", i)
+		}
+	}
+}
+
+func TestG2(t *testing.T) {
+	initHerumi(t)
+	for i, src := range []*bls.G2{herumiG2(), herumiG2(), {}} {
+		want := gnark.G2Affine{}
+		if _, err := want.SetBytes(src.SerializeUncompressed()); err != nil {
+			t.Fatal(err)
+		}
+		got := gnark.G2Affine{}
+		if err := convert.G2ToGnark(&got, src); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(&want) {
+			t.Fatalf("point %d: G2ToGnark differs from herumi's encoding", i)
+		}
+		back := bls.G2{}
+		if err := convert.G2FromGnark(&back, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !back.IsEqual(src) {
+			t.Fatalf("point %d: G2FromGnark does not round-trip", i)
+		}
+	}
+}
+
+func TestInfinity(t *testing.T) {
+	initHerumi(t)
+	g1, g2 := gnark.G1Affine{}, gnark.G2Affine{}
+	if err := convert.G1ToGnark(&g1, &bls.G1{}); err != nil || !g1.IsInfinity() {
+		t.Fatalf("herumi G1 zero is not infinity: %v", err)
+	}
+	if err := convert.G2ToGnark(&g2, &bls.G2{}); err != nil || !g2.IsInfinity() {
+		t.Fatalf("herumi G2 zero is not infinity: %v", err)
+	}
+	h1, h2 := herumiG1(), herumiG2()
+	if err := convert.G1FromGnark(h1, &g1); err != nil || !h1.IsZero() {
+		t.Fatalf("gnark G1 infinity is not zero: %v", err)
+	}
+	if err := convert.G2FromGnark(h2, &g2); err != nil || !h2.IsZero() {
+		t.Fatalf("gnark G2 infinity is not zero: %v", err)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	initHerumi(t)
+	h1 := bls.G1{}
+	h1.X.SetInt64(1)
+	h1.Y.SetInt64(1)
+	h1.Z.SetInt64(1)
+	g1 := gnark.G1Affine{}
+	if err := convert.G1ToGnark(&g1, &h1); err == nil {
+		t.Fatal("point off the curve accepted")
+	}
+	g1.X.SetOne()
+	g1.Y.SetOne()
+	if err := convert.G1FromGnark(&h1, &g1); err == nil || !h1.IsZero() {
+		t.Fatal("point off the curve accepted")
+	}
+}
+
+func TestFr(t *testing.T) {
+	initHerumi(t)
+	zero, one := bls.Fr{}, bls.Fr{}
+	one.SetInt64(1)
+	random := bls.Fr{}
+	random.SetByCSPRNG()
+	for i, src := range []*bls.Fr{&zero, &one, &random} {
+		want := fr.Element{}
+		want.SetBytes(src.Serialize())
+		got := fr.Element{}
+		convert.FrToGnark(&got, src)
+		if !got.Equal(&want) {
+			t.Fatalf("scalar %d: FrToGnark differs from herumi's encoding", i)
+		}
+		back := bls.Fr{}
+		if err := convert.FrFromGnark(&back, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !back.IsEqual(src) {
+			t.Fatalf("scalar %d: FrFromGnark does not round-trip", i)
+		}
+	}
+}
+
+func TestCompressed(t *testing.T) {
+	initHerumi(t)
+	h1, h2 := herumiG1(), herumiG2()
+	g1, g2 := gnark.G1Affine{}, gnark.G2Affine{}
+	if err := convert.G1CompressedToGnark(&g1, h1.Serialize()); err != nil {
+		t.Fatal(err)
+	}
+	if err := convert.G2CompressedToGnark(&g2, h2.Serialize()); err != nil {
+		t.Fatal(err)
+	}
+	b1, b2 := g1.Bytes(), g2.Bytes()
+	back1, back2 := bls.G1{}, bls.G2{}
+	if err := convert.G1CompressedFromGnark(&back1, &b1); err != nil || !back1.IsEqual(h1) {
+		t.Fatalf("compressed G1 does not round-trip: %v", err)
+	}
+	if err := convert.G2CompressedFromGnark(&back2, &b2); err != nil || !back2.IsEqual(h2) {
+		t.Fatalf("compressed G2 does not round-trip: %v", err)
+	}
+	if err := convert.G1CompressedToGnark(&g1, h1.Serialize()[1:]); err == nil {
+		t.Fatal("short encoding accepted")
+	}
+}
+
+func TestAllocs(t *testing.T) {
+	initHerumi(t)
+	h1, h2 := herumiG1(), herumiG2()
+	g1, g2 := gnark.G1Affine{}, gnark.G2Affine{}
+	f, hf := fr.Element{}, bls.Fr{}
+	hf.SetByCSPRNG()
+	for _, c := range []struct {
+		name string
+		f    func()
+	}{
+		{"G1ToGnark", func() { convert.G1ToGnark(&g1, h1) }},
+		{"G1FromGnark", func() { convert.G1FromGnark(h1, &g1) }},
+		{"G2ToGnark", func() { convert.G2ToGnark(&g2, h2) }},
+		{"G2FromGnark", func() { convert.G2FromGnark(h2, &g2) }},
+		{"FrToGnark", func() { convert.FrToGnark(&f, &hf) }},
+		{"FrFromGnark", func() { convert.FrFromGnark(&hf, &f) }},
+	} {
+		if n := testing.AllocsPerRun(10, c.f); n != 0 {
+			t.Errorf("%s: %v allocations, want 0", c.name, n)
+		}
+	}
+}
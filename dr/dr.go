@@ -0,0 +1,208 @@
+// Package dr implements the disaster recovery drill of the bls-vess command:
+//
+//	bls-vess dr reconstruct -committee roster.json [-verify-only] [-out file]
+//	    [-to age1...]...
+//
+// Key share holders enter their versioned key share encodings, in hex, one
+// per line. Once the committee threshold is reached and the operator
+// confirms, the adjudicator key is reconstructed and checked against the
+// roster. It is then written to a new file as a 1-of-1 SLIP-0039 mnemonic,
+// encrypted with a passphrase. With -to, the file is further encrypted to
+// the given age recipients, ASCII armored. With -verify-only the key is discarded, which
+// proves that the shares are enough to recover it. Every step is written to
+// the audit log
+package dr
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/poupas/bls-vess/agefile"
+	"github.com/poupas/bls-vess/vess"
+)
+
+// Confirmation is the text the operator types to confirm reconstruction
+const Confirmation = "RECONSTRUCT"
+
+var (
+	ErrAborted         = errors.New("dr: aborted by the operator")
+	ErrNoCommittee     = errors.New("dr: -committee is required")
+	ErrNoOutput        = errors.New("dr: -out is required unless -verify-only is set")
+	ErrDuplicateShare  = errors.New("dr: share already entered")
+	ErrPassphrase      = errors.New("dr: passphrases do not match")
+	ErrEmptyPassphrase = errors.New("dr: empty passphrase")
+)
+
+// Reconstruct runs the drill with arguments args (flags only), reading from
+// in and prompting on out
+func Reconstruct(v *vess.VESS, args []string, in io.Reader, out io.Writer, audit *log.Logger) error {
+	fs := flag.NewFlagSet("dr reconstruct", flag.ContinueOnError)
+	fs.SetOutput(out)
+	rosterPath := fs.String("committee", "", "committee roster, as JSON")
+	outPath := fs.String("out", "", "new file for the encrypted key mnemonic")
+	verifyOnly := fs.Bool("verify-only", false, "check that the key can be reconstructed, without writing it")
+	recipients := recipientsFlag{}
+	fs.Var(&recipients, "to", "age recipient to encrypt the mnemonic file to (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *rosterPath == "" {
+		return ErrNoCommittee
+	}
+	if *outPath == "" && !*verifyOnly {
+		return ErrNoOutput
+	}
+
+	b, err := os.ReadFile(*rosterPath)
+	if err != nil {
+		return err
+	}
+	c := vess.Committee{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return err
+	}
+	if err := v.CheckCommittee(&c); err != nil {
+		return err
+	}
+	id := hex.EncodeToString(c.ID[:])
+	audit.Printf("start committee=%s epoch=%d threshold=%d verify-only=%t",
+		id, c.Epoch, c.Threshold, *verifyOnly)
+
+	r := bufio.NewReader(in)
+	shares := make([]*vess.KeyShare, 0, c.Threshold)
+	seen := make(map[int]bool, c.Threshold)
+	for len(shares) < c.Threshold {
+		line, err := prompt(r, out, fmt.Sprintf("Key share %d of %d (hex): ", len(shares)+1, c.Threshold))
+		if err != nil {
+			return err
+		}
+		ks, err := parseShare(v, &c, line)
+		if err == nil && seen[ks.Index] {
+			err = ErrDuplicateShare
+		}
+		if err != nil {
+			audit.Printf("rejected share: %v", err)
+			fmt.Fprintln(out, "Rejected:", err)
+			continue
+		}
+		seen[ks.Index] = true
+		shares = append(shares, ks)
+		m, _ := c.Member(ks.Index)
+		audit.Printf("accepted share index=%d member=%q", ks.Index, m.ID)
+	}
+
+	line, err := prompt(r, out, fmt.Sprintf("Type %s to reconstruct the adjudicator key: ", Confirmation))
+	if err != nil {
+		return err
+	}
+	if line != Confirmation {
+		audit.Printf("aborted by the operator")
+		return ErrAborted
+	}
+
+	if *verifyOnly {
+		if err := v.CheckReconstruction(&c, shares...); err != nil {
+			audit.Printf("verification failed: %v", err)
+			return err
+		}
+		audit.Printf("verified, key discarded")
+		fmt.Fprintln(out, "The adjudicator key can be reconstructed")
+		return nil
+	}
+
+	passphrase, err := prompt(r, out, "Passphrase for the key mnemonic: ")
+	if err != nil {
+		return err
+	}
+	again, err := prompt(r, out, "Repeat the passphrase: ")
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return ErrEmptyPassphrase
+	}
+	if passphrase != again {
+		return ErrPassphrase
+	}
+
+	sk, err := v.ReconstructKey(&c, shares...)
+	if err != nil {
+		audit.Printf("reconstruction failed: %v", err)
+		return err
+	}
+	mnemonics, err := vess.Mnemonics(sk, 1, 1, []byte(passphrase))
+	if err != nil {
+		return err
+	}
+	content := []byte(mnemonics[0] + "\n")
+	if len(recipients) > 0 {
+		ct, err := agefile.Encrypt(content, recipients...)
+		if err != nil {
+			return err
+		}
+		content = agefile.Armor(ct)
+	}
+
+	f, err := os.OpenFile(*outPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	audit.Printf("reconstructed key written to %s age-recipients=%d", *outPath, len(recipients))
+	fmt.Fprintln(out, "Encrypted key mnemonic written to", *outPath)
+	return nil
+}
+
+// recipientsFlag collects -to age recipients
+type recipientsFlag []agefile.Recipient
+
+func (r *recipientsFlag) String() string {
+	return fmt.Sprintf("%d recipients", len(*r))
+}
+
+func (r *recipientsFlag) Set(s string) error {
+	rcpt, err := agefile.ParseX25519Recipient(s)
+	if err != nil {
+		return err
+	}
+	*r = append(*r, rcpt)
+	return nil
+}
+
+func prompt(r *bufio.Reader, out io.Writer, msg string) (string, error) {
+	fmt.Fprint(out, msg)
+	line, err := r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func parseShare(v *vess.VESS, c *vess.Committee, line string) (*vess.KeyShare, error) {
+	b, err := hex.DecodeString(line)
+	if err != nil {
+		return nil, err
+	}
+	ks := vess.KeyShare{}
+	if err := ks.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	if err := v.CheckKeyShare(c, &ks); err != nil {
+		return nil, err
+	}
+	return &ks, nil
+}
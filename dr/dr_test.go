@@ -0,0 +1,169 @@
+package dr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/poupas/bls-vess/vess"
+)
+
+// drill is a 2-of-3 committee with its roster on disk
+type drill struct {
+	v      *vess.VESS
+	sk     *vess.SecretKey
+	shares []string
+	roster string
+	dir    string
+}
+
+func newDrill(t *testing.T) *drill {
+	v, err := vess.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := vess.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := [32]byte{1}
+	ks, vector, err := v.SplitKeyShares(sk, 2, 3, id, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := v.NewCommittee(id, 1, []string{"alice", "bob", "carol"}, vector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &drill{v: v, sk: sk, dir: t.TempDir()}
+	for _, s := range ks {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.shares = append(d.shares, hex.EncodeToString(b))
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.roster = filepath.Join(d.dir, "roster.json")
+	if err := os.WriteFile(d.roster, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// run runs the drill with lines as the operator's input
+func (d *drill) run(args []string, lines ...string) (string, string, error) {
+	in := strings.NewReader(strings.Join(lines, "\n") + "\n")
+	out, audit := &bytes.Buffer{}, &bytes.Buffer{}
+	err := Reconstruct(d.v, append([]string{"-committee", d.roster}, args...), in, out, log.New(audit, "", 0))
+	return out.String(), audit.String(), err
+}
+
+func TestReconstruct(t *testing.T) {
+	d := newDrill(t)
+	path := filepath.Join(d.dir, "key.txt")
+	out, audit, err := d.run([]string{"-out", path},
+		d.shares[2], "zz", d.shares[2], d.shares[0], Confirmation, "passphrase", "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out, "Rejected:") != 2 || strings.Count(audit, "rejected share") != 2 {
+		t.Fatalf("bad and duplicate shares not rejected:\n%s", out)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := d.v.SecretKeyFromMnemonics([]string{strings.TrimSpace(string(b))}, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sk.Equal(d.sk) {
+		t.Fatal("reconstructed key differs")
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0600 {
+		t.Fatalf("got %v, %v", fi.Mode(), err)
+	}
+
+	// Never overwrites an existing file
+	if _, _, err := d.run([]string{"-out", path},
+		d.shares[0], d.shares[1], Confirmation, "passphrase", "passphrase"); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("got %v, want %v", err, os.ErrExist)
+	}
+}
+
+func TestReconstructVerifyOnly(t *testing.T) {
+	d := newDrill(t)
+	out, audit, err := d.run([]string{"-verify-only"}, d.shares[1], d.shares[2], Confirmation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "can be reconstructed") || !strings.Contains(audit, "verified, key discarded") {
+		t.Fatalf("got:\n%s\n%s", out, audit)
+	}
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("verify-only wrote %d files", len(entries)-1)
+	}
+	if strings.Contains(out, "Passphrase") {
+		t.Fatal("verify-only asked for a passphrase")
+	}
+}
+
+func TestReconstructArmored(t *testing.T) {
+	d := newDrill(t)
+	path := filepath.Join(d.dir, "key.age")
+	_, _, err := d.run([]string{"-out", path, "-to", "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"},
+		d.shares[0], d.shares[1], Confirmation, "passphrase", "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(b, []byte("-----BEGIN AGE ENCRYPTED FILE-----\n")) {
+		t.Fatalf("got %q", b)
+	}
+}
+
+func TestReconstructErrors(t *testing.T) {
+	d := newDrill(t)
+	path := filepath.Join(d.dir, "key.txt")
+	for _, c := range []struct {
+		name  string
+		args  []string
+		lines []string
+		err   error
+	}{
+		{"no output", nil, nil, ErrNoOutput},
+		{"aborted", []string{"-out", path}, []string{d.shares[0], d.shares[1], "reconstruct"}, ErrAborted},
+		{"passphrase mismatch", []string{"-out", path}, []string{d.shares[0], d.shares[1], Confirmation, "a", "b"}, ErrPassphrase},
+		{"empty passphrase", []string{"-out", path}, []string{d.shares[0], d.shares[1], Confirmation, "", ""}, ErrEmptyPassphrase},
+		{"out of input", []string{"-out", path}, []string{d.shares[0]}, io.EOF},
+	} {
+		if _, _, err := d.run(c.args, c.lines...); !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.err)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("%s: wrote the key", c.name)
+		}
+	}
+	if err := Reconstruct(d.v, nil, strings.NewReader(""), io.Discard, log.New(io.Discard, "", 0)); !errors.Is(err, ErrNoCommittee) {
+		t.Fatalf("got %v, want %v", err, ErrNoCommittee)
+	}
+}
@@ -0,0 +1,197 @@
+// Package errcode maps library errors to stable codes, with their gRPC
+// status and HTTP equivalents, so that API clients can act on errors without
+// matching their text. Codes never change meaning: new ones are appended
+package errcode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+	"github.com/poupas/bls-vess/policy"
+)
+
+// Code is a stable error code
+type Code uint16
+
+const (
+	Unknown Code = iota
+	InvalidEncoding
+	InvalidPoint
+	SuiteMismatch
+	InvalidSignature
+	InvalidCommittee
+	InvalidPartial
+	NotEnoughPartials
+	InvalidRequest
+	Unauthorized
+	Expired
+	PolicyDenied
+	PolicyPending
+	ProtocolAbort
+	Timeout
+	Canceled
+)
+
+// gRPC status codes, as numbered by google.golang.org/grpc/codes
+const (
+	grpcCanceled           = 1
+	grpcUnknown            = 2
+	grpcInvalidArgument    = 3
+	grpcDeadlineExceeded   = 4
+	grpcPermissionDenied   = 7
+	grpcFailedPrecondition = 9
+	grpcAborted            = 10
+	grpcUnavailable        = 14
+)
+
+type info struct {
+	name      string
+	grpc      uint32
+	http      int
+	retryable bool
+}
+
+var codes = [...]info{
+	Unknown:           {"unknown", grpcUnknown, http.StatusInternalServerError, false},
+	InvalidEncoding:   {"invalid_encoding", grpcInvalidArgument, http.StatusBadRequest, false},
+	InvalidPoint:      {"invalid_point", grpcInvalidArgument, http.StatusBadRequest, false},
+	SuiteMismatch:     {"suite_mismatch", grpcInvalidArgument, http.StatusBadRequest, false},
+	InvalidSignature:  {"invalid_signature", grpcInvalidArgument, http.StatusUnprocessableEntity, false},
+	InvalidCommittee:  {"invalid_committee", grpcFailedPrecondition, http.StatusUnprocessableEntity, false},
+	InvalidPartial:    {"invalid_partial", grpcInvalidArgument, http.StatusUnprocessableEntity, false},
+	NotEnoughPartials: {"not_enough_partials", grpcUnavailable, http.StatusServiceUnavailable, true},
+	InvalidRequest:    {"invalid_request", grpcInvalidArgument, http.StatusUnprocessableEntity, false},
+	Unauthorized:      {"unauthorized", grpcPermissionDenied, http.StatusForbidden, false},
+	Expired:           {"expired", grpcFailedPrecondition, http.StatusGone, false},
+	PolicyDenied:      {"policy_denied", grpcPermissionDenied, http.StatusForbidden, false},
+	PolicyPending:     {"policy_pending", grpcFailedPrecondition, http.StatusConflict, true},
+	ProtocolAbort:     {"protocol_abort", grpcAborted, http.StatusConflict, true},
+	Timeout:           {"timeout", grpcDeadlineExceeded, http.StatusGatewayTimeout, true},
+	Canceled:          {"canceled", grpcCanceled, 499, true},
+}
+
+// mapping is checked in order with errors.Is
+var mapping = []struct {
+	err  error
+	code Code
+}{
+	{scheme.ErrInvalidLength, InvalidEncoding},
+	{scheme.ErrNonCanonical, InvalidEncoding},
+	{scheme.ErrInvalidString, InvalidEncoding},
+	{scheme.ErrInvalidFingerprint, InvalidEncoding},
+	{scheme.ErrInvalidSecretKey, InvalidEncoding},
+	{scheme.ErrUnknownFormat, InvalidEncoding},
+	{scheme.ErrUnsupportedVersion, InvalidEncoding},
+	{scheme.ErrKindMismatch, InvalidEncoding},
+	{scheme.ErrLenientUnsupported, InvalidEncoding},
+	{scheme.ErrNoAdjudicatorExtension, InvalidEncoding},
+	{scheme.ErrInvalidPoint, InvalidPoint},
+	{scheme.ErrIdentity, InvalidPoint},
+	{scheme.ErrKeyMismatch, InvalidPoint},
+	{scheme.ErrSuiteMismatch, SuiteMismatch},
+	{scheme.ErrInvalidSignature, InvalidSignature},
+	{scheme.ErrInvalidCommittee, InvalidCommittee},
+	{scheme.ErrInvalidThreshold, InvalidCommittee},
+	{scheme.ErrNotMember, InvalidCommittee},
+	{scheme.ErrInvalidKeyShare, InvalidCommittee},
+	{scheme.ErrShareMismatch, InvalidCommittee},
+	{scheme.ErrReconstruction, InvalidCommittee},
+	{scheme.ErrInvalidTransition, InvalidCommittee},
+	{policy.ErrUnknownMember, InvalidCommittee},
+	{scheme.ErrInvalidPartial, InvalidPartial},
+	{scheme.ErrNotEnoughPartials, NotEnoughPartials},
+	{scheme.ErrInvalidRequest, InvalidRequest},
+	{scheme.ErrInvalidReceipt, InvalidRequest},
+	{scheme.ErrInvalidConditions, InvalidRequest},
+	{scheme.ErrInvalidContext, InvalidRequest},
+	{scheme.ErrInvalidHashLock, InvalidRequest},
+	{scheme.ErrWrongPreimage, InvalidRequest},
+	{scheme.ErrInvalidSwap, InvalidRequest},
+	{scheme.ErrDuplicateMessage, InvalidRequest},
+	{scheme.ErrDuplicateKey, InvalidRequest},
+	{scheme.ErrEmptyAggregate, InvalidRequest},
+	{scheme.ErrInvalidParallelism, InvalidRequest},
+	{policy.ErrSyntax, InvalidRequest},
+	{scheme.ErrInvalidBundle, InvalidRequest},
+	{scheme.ErrUnknownRequester, Unauthorized},
+	{scheme.ErrNotSwapParty, Unauthorized},
+	{scheme.ErrInvalidSVID, Unauthorized},
+	{policy.ErrRequesterNotAllowed, Unauthorized},
+	{scheme.ErrHashLockExpired, Expired},
+	{scheme.ErrSwapExpired, Expired},
+	{scheme.ErrBundleExpired, Expired},
+	{policy.ErrOutsideWindow, Expired},
+	{policy.ErrVetoed, PolicyDenied},
+	{policy.ErrMissingRequired, PolicyDenied},
+	{policy.ErrTooFewOrgs, PolicyDenied},
+	{policy.ErrEvidenceNotAccepted, PolicyDenied},
+	{policy.ErrNoQuorum, PolicyPending},
+	{policy.ErrVetoPending, PolicyPending},
+	{scheme.ErrCommitmentMismatch, ProtocolAbort},
+	{scheme.ErrRoundIncomplete, ProtocolAbort},
+	{scheme.ErrUnexpectedMessage, ProtocolAbort},
+	{context.DeadlineExceeded, Timeout},
+	{context.Canceled, Canceled},
+}
+
+// Of returns the code of err, Unknown if it has none
+func Of(err error) Code {
+	for _, m := range mapping {
+		if errors.Is(err, m.err) {
+			return m.code
+		}
+	}
+	return Unknown
+}
+
+func (c Code) info() info {
+	if int(c) >= len(codes) {
+		return codes[Unknown]
+	}
+	return codes[c]
+}
+
+// String returns the snake case name of c
+func (c Code) String() string {
+	return c.info().name
+}
+
+// GRPC returns the gRPC status code of c
+func (c Code) GRPC() uint32 {
+	return c.info().grpc
+}
+
+// HTTPStatus returns the HTTP status code of c
+func (c Code) HTTPStatus() int {
+	return c.info().http
+}
+
+// Retryable reports whether the same request may succeed later
+func (c Code) Retryable() bool {
+	return c.info().retryable
+}
+
+// Body is the JSON error body of HTTP APIs
+type Body struct {
+	Code      Code   `json:"code"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+}
+
+// NewBody returns the error body for err
+func NewBody(err error) Body {
+	c := Of(err)
+	return Body{Code: c, Name: c.String(), Message: err.Error(), Retryable: c.Retryable()}
+}
+
+// WriteHTTP writes err to w, with its status code and JSON body
+func WriteHTTP(w http.ResponseWriter, err error) {
+	b := NewBody(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(b.Code.HTTPStatus())
+	json.NewEncoder(w).Encode(b)
+}
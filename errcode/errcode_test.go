@@ -0,0 +1,155 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Codes are part of the API: their numbers and names never change, and new
+// codes are appended. This table pins them
+func TestStableCodes(t *testing.T) {
+	for _, c := range []struct {
+		code      Code
+		n         uint16
+		name      string
+		grpc      uint32
+		http      int
+		retryable bool
+	}{
+		{Unknown, 0, "unknown", 2, http.StatusInternalServerError, false},
+		{InvalidEncoding, 1, "invalid_encoding", 3, http.StatusBadRequest, false},
+		{InvalidPoint, 2, "invalid_point", 3, http.StatusBadRequest, false},
+		{SuiteMismatch, 3, "suite_mismatch", 3, http.StatusBadRequest, false},
+		{InvalidSignature, 4, "invalid_signature", 3, http.StatusUnprocessableEntity, false},
+		{InvalidCommittee, 5, "invalid_committee", 9, http.StatusUnprocessableEntity, false},
+		{InvalidPartial, 6, "invalid_partial", 3, http.StatusUnprocessableEntity, false},
+		{NotEnoughPartials, 7, "not_enough_partials", 14, http.StatusServiceUnavailable, true},
+		{InvalidRequest, 8, "invalid_request", 3, http.StatusUnprocessableEntity, false},
+		{Unauthorized, 9, "unauthorized", 7, http.StatusForbidden, false},
+		{Expired, 10, "expired", 9, http.StatusGone, false},
+		{PolicyDenied, 11, "policy_denied", 7, http.StatusForbidden, false},
+		{PolicyPending, 12, "policy_pending", 9, http.StatusConflict, true},
+		{ProtocolAbort, 13, "protocol_abort", 10, http.StatusConflict, true},
+		{Timeout, 14, "timeout", 4, http.StatusGatewayTimeout, true},
+		{Canceled, 15, "canceled", 1, 499, true},
+	} {
+		if uint16(c.code) != c.n || c.code.String() != c.name || c.code.GRPC() != c.grpc ||
+			c.code.HTTPStatus() != c.http || c.code.Retryable() != c.retryable {
+			t.Errorf("%s changed: %d %s %d %d %t", c.name, c.code, c.code, c.code.GRPC(), c.code.HTTPStatus(), c.code.Retryable())
+		}
+	}
+	if len(codes) != 16 {
+		t.Fatalf("%d codes: add the new ones to this test", len(codes))
+	}
+	if Code(len(codes)).String() != "unknown" {
+		t.Fatal("out of range code")
+	}
+}
+
+func TestUniqueNames(t *testing.T) {
+	seen := map[string]Code{}
+	for i, c := range codes {
+		if c.name == "" {
+			t.Errorf("code %d has no name", i)
+		}
+		if prev, ok := seen[c.name]; ok {
+			t.Errorf("codes %d and %d are both %s", prev, i, c.name)
+		}
+		seen[c.name] = Code(i)
+	}
+}
+
+// Every error of the mapping has exactly one code: it is listed once, and no
+// earlier entry catches it first
+func TestUniqueMapping(t *testing.T) {
+	seen := map[error]bool{}
+	for _, m := range mapping {
+		if seen[m.err] {
+			t.Errorf("%v mapped twice", m.err)
+		}
+		seen[m.err] = true
+		if got := Of(m.err); got != m.code {
+			t.Errorf("%v: got %s, want %s", m.err, got, m.code)
+		}
+		if got := Of(fmt.Errorf("%w: details", m.err)); got != m.code {
+			t.Errorf("wrapped %v: got %s, want %s", m.err, got, m.code)
+		}
+	}
+	if Of(errors.New("other")) != Unknown {
+		t.Fatal("unmapped error has a code")
+	}
+}
+
+// exportedErrors returns the package-level Err variables declared in dir
+func exportedErrors(t *testing.T, dir string) []string {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, d := range f.Decls {
+				g, ok := d.(*ast.GenDecl)
+				if !ok || g.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range g.Specs {
+					for _, n := range spec.(*ast.ValueSpec).Names {
+						if strings.HasPrefix(n.Name, "Err") {
+							names = append(names, pkg.Name+"."+n.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mappedErrors returns the errors listed in mapping, by qualified name
+func mappedErrors(t *testing.T) map[string]bool {
+	f, err := parser.ParseFile(token.NewFileSet(), "errcode.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if s, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := s.X.(*ast.Ident); ok && strings.HasPrefix(s.Sel.Name, "Err") {
+				names[x.Name+"."+s.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// Every error the library exports has a code, so that clients never get
+// Unknown for a documented error
+func TestEveryErrorMapped(t *testing.T) {
+	mapped := mappedErrors(t)
+	for _, dir := range []string{"../internal/scheme", "../policy"} {
+		errs := exportedErrors(t, filepath.FromSlash(dir))
+		if len(errs) == 0 {
+			t.Fatalf("no errors found in %s", dir)
+		}
+		for _, name := range errs {
+			if !mapped[name] {
+				t.Errorf("%s has no error code", name)
+			}
+		}
+	}
+}
@@ -3,12 +3,14 @@ module github.com/poupas/bls-vess
 go 1.18
 
 require (
+	filippo.io/age v1.0.0
 	github.com/consensys/gnark-crypto v0.7.0
 	github.com/herumi/bls-eth-go-binary v0.0.0-20220509081320-2d8ab06de53c
+	golang.org/x/crypto v0.0.0-20220622213112-05595931fe9d
+	golang.org/x/text v0.14.0
 )
 
 require (
 	github.com/mmcloughlin/addchain v0.4.0 // indirect
-	golang.org/x/crypto v0.0.0-20220622213112-05595931fe9d // indirect
-	golang.org/x/sys v0.0.0-20220627191245-f75cf1eec38b // indirect
+	golang.org/x/sys v0.7.0 // indirect
 )
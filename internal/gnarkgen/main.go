@@ -0,0 +1,112 @@
+// Command gnarkgen writes the curve packages built on gnark alone, bn254 and
+// bls12377, from a single template. Only the curve parameters differ between
+// them; edit the templates, not the generated files, and run go generate
+package main
+
+//go:generate go run .
+
+import (
+	"bytes"
+	"embed"
+	"flag"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// curve is what sets a package apart from the others
+type curve struct {
+	// Package is the package name and directory
+	Package string
+	// Gnark is the gnark-crypto package, under ecc
+	Gnark string
+	// Name is the curve name, as Params reports it
+	Name string
+	// Doc ends the package comment
+	Doc string
+	// DST is the default domain separation tag
+	DST string
+	// Suite is the scheme.Ciphersuite of the default DST
+	Suite string
+	// Mask and Infinity are the flag bits of uncompressed encodings
+	Mask     string
+	Infinity string
+}
+
+var curves = []curve{
+	{
+		Package: "bn254",
+		Gnark:   "bn254",
+		Name:    "BN254",
+		Doc: `// Same construction as package vess, on BN254. Pairings on this curve are
+// available as EVM precompiles, which makes escrows cheap to verify on-chain.
+// As in vess, public keys live in G1 and signatures in G2`,
+		DST:      "BLS_SIG_BN254G2_XMD:SHA-256_SVDW_RO_POP_",
+		Suite:    "CiphersuiteBN254G2XMDSHA256",
+		Mask:     "0b11 << 6",
+		Infinity: "0b01 << 6",
+	},
+	{
+		Package: "bls12377",
+		Gnark:   "bls12-377",
+		Name:    "BLS12-377",
+		Doc: `// Same construction as package vess, on BLS12-377. The curve's scalar field
+// is the base field of BW6-761, so escrows can be verified efficiently inside
+// recursive SNARKs. As in vess, public keys live in G1 and signatures in G2`,
+		DST:      "BLS_SIG_BLS12377G2_XMD:SHA-256_SVDW_RO_POP_",
+		Suite:    "CiphersuiteBLS12377G2XMDSHA256",
+		Mask:     "0b111 << 5",
+		Infinity: "0b010 << 5",
+	},
+}
+
+// files maps each template to the name of the file it generates
+var files = map[string]func(c curve) string{
+	"curve.go.tmpl":      func(c curve) string { return c.Package + ".go" },
+	"compressed.go.tmpl": func(c curve) string { return "compressed.go" },
+	"debug.go.tmpl":      func(c curve) string { return "debug.go" },
+}
+
+const header = "// Code generated by internal/gnarkgen. DO NOT EDIT.\n\n"
+
+//go:embed *.tmpl
+var fs embed.FS
+
+var templates = template.Must(template.ParseFS(fs, "*.tmpl"))
+
+// render returns the contents of every file of c, by path relative to the
+// module root
+func render(c curve) (map[string][]byte, error) {
+	out := map[string][]byte{}
+	for tmpl, name := range files {
+		b := bytes.NewBufferString(header)
+		if err := templates.ExecuteTemplate(b, tmpl, c); err != nil {
+			return nil, err
+		}
+		src, err := format.Source(b.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		out[filepath.Join(c.Package, name(c))] = src
+	}
+	return out, nil
+}
+
+func main() {
+	root := flag.String("root", "../..", "module root")
+	flag.Parse()
+
+	for _, c := range curves {
+		out, err := render(c)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for path, src := range out {
+			if err := os.WriteFile(filepath.Join(*root, path), src, 0o644); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
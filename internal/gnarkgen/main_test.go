@@ -0,0 +1,28 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestUpToDate fails when a generated package was edited by hand, or a
+// template changed without running go generate
+func TestUpToDate(t *testing.T) {
+	for _, c := range curves {
+		out, err := render(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for path, want := range out {
+			got, err := os.ReadFile(filepath.Join("..", "..", path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s is out of date, run go generate ./internal/gnarkgen", path)
+			}
+		}
+	}
+}
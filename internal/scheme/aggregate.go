@@ -0,0 +1,137 @@
+package scheme
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+)
+
+var ErrEmptyAggregate = errors.New("aggregate is empty")
+
+// AggregateVESig is the sum of escrows, by any signers on distinct messages,
+// to one adjudicator. Escrows can be added and removed in any order, in
+// constant time, so services can keep a rolling aggregate
+type AggregateVESig[G2 any, P2 Point[G2]] struct {
+	omega G2
+	mu    G2
+	n     int
+}
+
+// Aggregate returns the aggregate of sigs
+func (s *Scheme[G1, G2, P1, P2]) Aggregate(sigs ...*VESig[G2, P2]) *AggregateVESig[G2, P2] {
+	agg := AggregateVESig[G2, P2]{}
+	for _, sig := range sigs {
+		s.AggregateAdd(&agg, sig)
+	}
+	return &agg
+}
+
+// Len returns the number of escrows in agg
+func (agg *AggregateVESig[G2, P2]) Len() int {
+	return agg.n
+}
+
+// AggregateAdd adds sig to agg
+func (s *Scheme[G1, G2, P1, P2]) AggregateAdd(agg *AggregateVESig[G2, P2], sig *VESig[G2, P2]) {
+	P2(&agg.omega).Add(&agg.omega, &sig.omega)
+	P2(&agg.mu).Add(&agg.mu, &sig.mu)
+	agg.n++
+}
+
+// AggregateRemove removes sig, which must have been added before, from agg.
+// Removing any other escrow leaves an aggregate that does not verify
+func (s *Scheme[G1, G2, P1, P2]) AggregateRemove(agg *AggregateVESig[G2, P2], sig *VESig[G2, P2]) error {
+	if agg.n == 0 {
+		return ErrEmptyAggregate
+	}
+	P2(&agg.omega).Sub(&agg.omega, &sig.omega)
+	P2(&agg.mu).Sub(&agg.mu, &sig.mu)
+	agg.n--
+	return nil
+}
+
+// VerifyAggregate checks agg, where msgs[i] was signed by pks[i]:
+// e(g1, omega)^-1 . prod e(pk_i, H(m_i)) . e(v, mu) == 1. Messages must be
+// distinct, which rules out rogue key attacks without proofs of possession.
+// If ctx is done before the Miller loops start, ctx.Err() is returned
+func (s *Scheme[G1, G2, P1, P2]) VerifyAggregate(ctx context.Context, pks []*PublicKey[G1, P1], adj *AdjudicatorPublicKey[G1, G2, P1, P2], msgs [][]byte, agg *AggregateVESig[G2, P2]) (bool, error) {
+	return s.verifyAggregate(ctx, pks, &adj.g1, msgs, agg)
+}
+
+func (s *Scheme[G1, G2, P1, P2]) verifyAggregate(ctx context.Context, pks []*PublicKey[G1, P1], adjG1 *G1, msgs [][]byte, agg *AggregateVESig[G2, P2]) (bool, error) {
+	if len(pks) == 0 || len(pks) != len(msgs) || len(pks) != agg.n {
+		return false, ErrInvalidLength
+	}
+
+	in := s.getPairingInput()
+	defer s.putPairingInput(in)
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	in.p = append(in.p, *ng1)
+	in.q = append(in.q, agg.omega)
+	for i, m := range msgs {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if !in.distinct(m) {
+			return false, ErrDuplicateMessage
+		}
+
+		h, err := s.Hash(m)
+		if err != nil {
+			return false, err
+		}
+		in.p = append(in.p, pks[i].p)
+		in.q = append(in.q, h)
+	}
+	in.p = append(in.p, *adjG1)
+	in.q = append(in.q, agg.mu)
+	return s.pairingCheck(ctx, in.p, in.q)
+}
+
+// AdjudicateAggregate verifies agg and recovers the aggregate signature of
+// all its escrows
+func (s *Scheme[G1, G2, P1, P2]) AdjudicateAggregate(ctx context.Context, adjSK *SecretKey, pks []*PublicKey[G1, P1], msgs [][]byte, agg *AggregateVESig[G2, P2]) (*Signature[G2, P2], error) {
+	adjG1 := new(G1)
+	P1(adjG1).ScalarMultiplication(&s.G1Gen, &adjSK.x)
+	ok, err := s.verifyAggregate(ctx, pks, adjG1, msgs, agg)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	return s.Adjudicate(adjSK, &VESig[G2, P2]{omega: agg.omega, mu: agg.mu}), nil
+}
+
+// EncodeAggregateVESig returns the versioned encoding of agg: its points and
+// a 4-byte count of escrows
+func (s *Scheme[G1, G2, P1, P2]) EncodeAggregateVESig(agg *AggregateVESig[G2, P2]) []byte {
+	dst := s.AppendG2Compressed(s.encode(KindAggregateVESig), &agg.omega)
+	dst = s.AppendG2Compressed(dst, &agg.mu)
+	n := len(dst)
+	dst = append(dst, make([]byte, 4)...)
+	binary.BigEndian.PutUint32(dst[n:], uint32(agg.n))
+	return dst
+}
+
+// DecodeAggregateVESig decodes a versioned aggregate
+func (s *Scheme[G1, G2, P1, P2]) DecodeAggregateVESig(b []byte) (*AggregateVESig[G2, P2], error) {
+	v, body, err := s.body(b, KindAggregateVESig)
+	if err != nil {
+		return nil, err
+	}
+	_, n2 := s.pointSizes(v)
+	agg := AggregateVESig[G2, P2]{}
+	if err := s.decodeG2V(&agg.omega, body[:n2], v); err != nil {
+		return nil, err
+	}
+	if err := s.decodeG2V(&agg.mu, body[n2:2*n2], v); err != nil {
+		return nil, err
+	}
+	// Overflows on 32-bit platforms
+	if agg.n = int(binary.BigEndian.Uint32(body[2*n2:])); agg.n < 0 {
+		return nil, ErrInvalidLength
+	}
+	return &agg, nil
+}
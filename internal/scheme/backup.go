@@ -0,0 +1,158 @@
+package scheme
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// backupShareSize is the size of an encoded backup share, header excluded
+const backupShareSize = keyShareMetadataSize + 4 + 4 + ScalarSize
+
+// BackupShare is a sub-share of a committee member's key share, held by a
+// recovery custodian. A single custodian learns nothing about the key share
+type BackupShare struct {
+	// Share describes the backed up key share. Share.Secret is nil
+	Share KeyShare
+	// Index is the x coordinate of the sub-share, starting at 1
+	Index     int
+	Threshold int
+	Secret    *SecretKey
+}
+
+// BackupKeyShare splits a key share into n sub-shares, any t of which
+// restore it (Shamir-of-Shamir). Each sub-share goes to a different custodian
+func (s *Scheme[G1, G2, P1, P2]) BackupKeyShare(ks *KeyShare, t, n int) ([]*BackupShare, error) {
+	if ks.Secret == nil {
+		return nil, ErrInvalidKeyShare
+	}
+	poly, err := s.polynomial(ks.Secret, t, n)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := *ks
+	meta.Secret = nil
+	backups := make([]*BackupShare, n)
+	for i := range backups {
+		backups[i] = &BackupShare{
+			Share:     meta,
+			Index:     i + 1,
+			Threshold: t,
+			Secret:    s.evaluate(poly, i+1),
+		}
+	}
+
+	return backups, nil
+}
+
+// RestoreKeyShare restores a key share from sub-shares. The restored share
+// should be checked against its committee, see CheckKeyShare
+func (s *Scheme[G1, G2, P1, P2]) RestoreKeyShare(backups []*BackupShare) (*KeyShare, error) {
+	if len(backups) == 0 {
+		return nil, ErrInvalidThreshold
+	}
+	first := backups[0]
+	if first.Share.Suite != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	if len(backups) < first.Threshold {
+		return nil, ErrInvalidThreshold
+	}
+
+	indices := make([]int, first.Threshold)
+	for i, b := range backups[:first.Threshold] {
+		if b.Share.Suite != first.Share.Suite ||
+			b.Share.Index != first.Share.Index ||
+			b.Share.Threshold != first.Share.Threshold ||
+			b.Share.CommitteeID != first.Share.CommitteeID ||
+			b.Share.VectorHash != first.Share.VectorHash ||
+			b.Share.Epoch != first.Share.Epoch ||
+			b.Threshold != first.Threshold {
+			return nil, ErrShareMismatch
+		}
+		if b.Secret == nil {
+			return nil, ErrInvalidKeyShare
+		}
+		indices[i] = b.Index
+	}
+	lambdas, err := s.lagrange(indices)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lagrange interpolation at 0 recovers the key share
+	ks := first.Share
+	ks.Secret = &SecretKey{}
+	term := new(big.Int)
+	for i, l := range lambdas {
+		term.Mul(l, &backups[i].Secret.x)
+		ks.Secret.x.Add(&ks.Secret.x, term)
+	}
+	ks.Secret.x.Mod(&ks.Secret.x, s.Order)
+	if ks.Secret.x.Sign() == 0 {
+		return nil, ErrInvalidKeyShare
+	}
+
+	return &ks, nil
+}
+
+// MarshalBinary returns the versioned encoding of the backup share
+func (bs *BackupShare) MarshalBinary() ([]byte, error) {
+	if bs.Secret == nil {
+		return nil, ErrInvalidKeyShare
+	}
+	b := make([]byte, 0, HeaderSize+backupShareSize)
+	b = Header{Version: CurrentVersion, Suite: bs.Share.Suite, Kind: KindBackupShare}.append(b)
+	b = bs.Share.appendMetadata(b)
+	n := len(b)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint32(b[n:], uint32(bs.Index))
+	binary.BigEndian.PutUint32(b[n+4:], uint32(bs.Threshold))
+	return bs.Secret.AppendBinary(b), nil
+}
+
+// UnmarshalBinary decodes a versioned backup share
+func (bs *BackupShare) UnmarshalBinary(b []byte) error {
+	b, err := checkHeader(b, KindBackupShare, backupShareSize)
+	if err != nil {
+		return err
+	}
+
+	res := BackupShare{Share: KeyShare{Suite: Ciphersuite(b[1])}, Secret: &SecretKey{}}
+	b = res.Share.parseMetadata(b[HeaderSize:])
+	res.Index = int(binary.BigEndian.Uint32(b))
+	res.Threshold = int(binary.BigEndian.Uint32(b[4:]))
+	res.Secret.x.SetBytes(b[8:])
+	if res.Share.Index < 1 || res.Share.Threshold < 1 ||
+		res.Index < 1 || res.Threshold < 1 || res.Secret.x.Sign() == 0 {
+		return ErrInvalidKeyShare
+	}
+
+	*bs = res
+	return nil
+}
+
+// Equal reports whether bs and o are the same sub-share of the same key share
+func (bs *BackupShare) Equal(o *BackupShare) bool {
+	return bs.Share.Equal(&o.Share) && bs.Index == o.Index &&
+		bs.Threshold == o.Threshold && equalSecrets(bs.Secret, o.Secret)
+}
+
+// Clone returns a copy of bs, secret included
+func (bs *BackupShare) Clone() *BackupShare {
+	c := *bs
+	c.Share = *bs.Share.Clone()
+	c.Secret = cloneSecret(bs.Secret)
+	return &c
+}
+
+// String describes the sub-share, never its secret
+func (bs BackupShare) String() string {
+	return fmt.Sprintf("BackupShare(%d of %d, of %v)", bs.Index, bs.Threshold, bs.Share)
+}
+
+// GoString is String, for %#v
+func (bs BackupShare) GoString() string {
+	return bs.String()
+}
@@ -0,0 +1,113 @@
+package scheme
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+)
+
+var ErrDuplicateMessage = errors.New("duplicate message in batch")
+
+// SignBatch escrows one aggregate signature on all of msgs, which must be
+// distinct, with a single (omega, mu):
+//
+//	omega = sum x.H(m_i) + r.v', mu = r.g2
+//
+// Storage and verification are constant in the number of messages. The
+// messages are opened together: adjudication yields the aggregate signature,
+// and no signature on a single message can be recovered from it.
+//
+// The batch calls hash every message, and stop with ctx.Err() if ctx is
+// done before they are all hashed
+func (s *Scheme[G1, G2, P1, P2]) SignBatch(ctx context.Context, sk *SecretKey, adj *AdjudicatorPublicKey[G1, G2, P1, P2], msgs [][]byte) (*VESig[G2, P2], error) {
+	h, err := s.hashBatch(ctx, msgs)
+	if err != nil {
+		return nil, err
+	}
+	r, err := rand.Int(rand.Reader, s.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := VESig[G2, P2]{}
+	P2(&sig.mu).ScalarMultiplication(&s.G2Gen, r)
+	sigma2 := new(G2)
+	P2(sigma2).ScalarMultiplication(&adj.g2, r)
+	P2(&sig.omega).ScalarMultiplication(h, &sk.x)
+	P2(&sig.omega).Add(&sig.omega, sigma2)
+	return &sig, nil
+}
+
+// hashBatch returns sum H(m_i)
+func (s *Scheme[G1, G2, P1, P2]) hashBatch(ctx context.Context, msgs [][]byte) (*G2, error) {
+	if len(msgs) == 0 {
+		return nil, ErrInvalidLength
+	}
+	in := s.getPairingInput()
+	defer s.putPairingInput(in)
+	sum := new(G2)
+	for _, m := range msgs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !in.distinct(m) {
+			return nil, ErrDuplicateMessage
+		}
+
+		h, err := s.Hash(m)
+		if err != nil {
+			return nil, err
+		}
+		P2(sum).Add(sum, &h)
+	}
+	return sum, nil
+}
+
+// VerifyBatch checks an escrow from SignBatch, with three pairings:
+// e(g1, omega)^-1 . e(pk, sum H(m_i)) . e(v, mu) == 1
+func (s *Scheme[G1, G2, P1, P2]) VerifyBatch(ctx context.Context, pk *PublicKey[G1, P1], adj *AdjudicatorPublicKey[G1, G2, P1, P2], msgs [][]byte, sig *VESig[G2, P2]) (bool, error) {
+	return s.verifyBatch(ctx, pk, &adj.g1, msgs, sig)
+}
+
+func (s *Scheme[G1, G2, P1, P2]) verifyBatch(ctx context.Context, pk *PublicKey[G1, P1], adjG1 *G1, msgs [][]byte, sig *VESig[G2, P2]) (bool, error) {
+	h, err := s.hashBatch(ctx, msgs)
+	if err != nil {
+		return false, err
+	}
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	return s.PairingCheck(
+		[]G1{*ng1, pk.p, *adjG1},
+		[]G2{sig.omega, *h, sig.mu},
+	)
+}
+
+// AdjudicateBatch verifies an escrow from SignBatch and recovers the
+// aggregate signature on msgs, see VerifyRecoveredBatch
+func (s *Scheme[G1, G2, P1, P2]) AdjudicateBatch(ctx context.Context, adjSK *SecretKey, pk *PublicKey[G1, P1], msgs [][]byte, sig *VESig[G2, P2]) (*Signature[G2, P2], error) {
+	adjG1 := new(G1)
+	P1(adjG1).ScalarMultiplication(&s.G1Gen, &adjSK.x)
+	ok, err := s.verifyBatch(ctx, pk, adjG1, msgs, sig)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	return s.Adjudicate(adjSK, sig), nil
+}
+
+// VerifyRecoveredBatch checks an aggregate signature by pk on msgs:
+// e(g1, sigma)^-1 . e(pk, sum H(m_i)) == 1
+func (s *Scheme[G1, G2, P1, P2]) VerifyRecoveredBatch(ctx context.Context, pk *PublicKey[G1, P1], msgs [][]byte, sig *Signature[G2, P2]) (bool, error) {
+	h, err := s.hashBatch(ctx, msgs)
+	if err != nil {
+		return false, err
+	}
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	return s.PairingCheck(
+		[]G1{*ng1, pk.p},
+		[]G2{sig.p, *h},
+	)
+}
@@ -0,0 +1,95 @@
+package scheme
+
+import "math/big"
+
+// BlindedMu is mu^b for a random b. The requester sends it to the adjudicator
+// in place of the VESig, which is then opened without the adjudicator
+// learning which escrow it was: mu^b is uniformly distributed in G2. It is
+// also the type of the adjudicator's response, (mu^b)^x'.
+//
+// Blinded adjudication turns the adjudicator key into a signing oracle for
+// whoever can submit requests: (H(m)^b)^x' unblinds to a BLS signature on
+// m under the adjudicator key. Only expose it to authorized requesters, and
+// never use the adjudicator key for anything else
+type BlindedMu[G2 any, P2 Point[G2]] struct {
+	p G2
+}
+
+// BlindingFactor is the inverse of b, kept by the requester to unblind the
+// response
+type BlindingFactor struct {
+	inv big.Int
+}
+
+// Marshal returns the uncompressed encoding of the blinded point
+func (bm *BlindedMu[G2, P2]) Marshal() []byte {
+	return P2(&bm.p).Marshal()
+}
+
+// Unmarshal decodes a blinded point. Points outside the prime order subgroup
+// are rejected: the response to such a point would leak the key modulo the
+// cofactor's small factors
+func (bm *BlindedMu[G2, P2]) Unmarshal(b []byte) error {
+	return unmarshalPoint[G2, P2](&bm.p, b)
+}
+
+// Blind blinds the mu component of sig with a fresh random factor
+func (s *Scheme[G1, G2, P1, P2]) Blind(sig *VESig[G2, P2]) (*BlindedMu[G2, P2], *BlindingFactor, error) {
+	b, err := GenerateKey(s.Order)
+	if err != nil {
+		return nil, nil, err
+	}
+	bm := BlindedMu[G2, P2]{}
+	P2(&bm.p).ScalarMultiplication(&sig.mu, &b.x)
+	bf := BlindingFactor{}
+	bf.inv.ModInverse(&b.x, s.Order)
+	return &bm, &bf, nil
+}
+
+// AdjudicateBlinded computes bm^x', the adjudicator side of blinded
+// adjudication
+func (s *Scheme[G1, G2, P1, P2]) AdjudicateBlinded(adjSK *SecretKey, bm *BlindedMu[G2, P2]) *BlindedMu[G2, P2] {
+	res := BlindedMu[G2, P2]{}
+	P2(&res.p).ScalarMultiplication(&bm.p, &adjSK.x)
+	return &res
+}
+
+// PartialAdjudicateBlinded is PartialAdjudicate for a blinded mu
+func (s *Scheme[G1, G2, P1, P2]) PartialAdjudicateBlinded(share *SecretKey, bm *BlindedMu[G2, P2]) *Partial[G2, P2] {
+	pa := Partial[G2, P2]{}
+	P2(&pa.p).ScalarMultiplication(&bm.p, &share.x)
+	return &pa
+}
+
+// CombineBlinded recovers the response bm^x' from t partial adjudications of
+// a blinded mu
+func (s *Scheme[G1, G2, P1, P2]) CombineBlinded(indices []int, partials []*Partial[G2, P2]) (*BlindedMu[G2, P2], error) {
+	mux, err := s.combine(indices, partials)
+	if err != nil {
+		return nil, err
+	}
+	return &BlindedMu[G2, P2]{p: *mux}, nil
+}
+
+// Unblind checks that resp is bm^x' for the adjudicator key adj, that is
+// e(g1, resp) == e(v', bm), and recovers the signature from sig
+func (s *Scheme[G1, G2, P1, P2]) Unblind(adj *AdjudicatorPublicKey[G1, G2, P1, P2], sig *VESig[G2, P2], bm, resp *BlindedMu[G2, P2], bf *BlindingFactor) (*Signature[G2, P2], error) {
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	ok, err := s.PairingCheck(
+		[]G1{*ng1, adj.g1},
+		[]G2{resp.p, bm.p},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	// mu^x' = (mu^(b.x'))^(1/b), sigma = omega / mu^x'
+	res := Signature[G2, P2]{}
+	P2(&res.p).ScalarMultiplication(&resp.p, &bf.inv)
+	P2(&res.p).Sub(&sig.omega, &res.p)
+	return &res, nil
+}
@@ -0,0 +1,171 @@
+package scheme
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidBundle = errors.New("invalid adjudicator bundle")
+	ErrBundleExpired = errors.New("adjudicator bundle outside its validity period")
+)
+
+const (
+	bundleTag     = "VESS-ADJUDICATOR-BUNDLE-V1"
+	bundleVersion = 1
+)
+
+// Bundle is the public description of an adjudicator, which signers fetch
+// and validate before escrowing. It is published as a JSON envelope, signed
+// with the publisher's Ed25519 key: a committee has no single holder of the
+// adjudicator key to sign with
+type Bundle[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	Adjudicator *AdjudicatorPublicKey[G1, G2, P1, P2]
+	// Optional committee holding the adjudicator key
+	Committee *Committee[G1, P1]
+	NotBefore time.Time
+	NotAfter  time.Time
+	// Where the adjudication policy is published
+	PolicyURL string
+}
+
+type bundleJSON struct {
+	Version     int             `json:"version"`
+	Ciphersuite string          `json:"ciphersuite"`
+	Suite       Ciphersuite     `json:"suite"`
+	Adjudicator string          `json:"adjudicator"`
+	Committee   json.RawMessage `json:"committee,omitempty"`
+	NotBefore   int64           `json:"not_before"`
+	NotAfter    int64           `json:"not_after"`
+	PolicyURL   string          `json:"policy_url,omitempty"`
+}
+
+type bundleEnvelope struct {
+	// The payload is kept as signed, not re-encoded
+	Bundle    json.RawMessage `json:"bundle"`
+	Publisher string          `json:"publisher"`
+	Signature string          `json:"signature"`
+}
+
+func bundleMessage(payload []byte) []byte {
+	return append([]byte(bundleTag), payload...)
+}
+
+// PublishBundle returns the signed JSON envelope of b
+func (s *Scheme[G1, G2, P1, P2]) PublishBundle(b *Bundle[G1, G2, P1, P2], publisher ed25519.PrivateKey) ([]byte, error) {
+	if err := s.checkBundle(b); err != nil {
+		return nil, err
+	}
+	j := bundleJSON{
+		Version:     bundleVersion,
+		Ciphersuite: s.Suite.String(),
+		Suite:       s.Suite,
+		Adjudicator: hex.EncodeToString(s.AppendAdjudicatorPublicKey(nil, b.Adjudicator)),
+		NotBefore:   b.NotBefore.Unix(),
+		NotAfter:    b.NotAfter.Unix(),
+		PolicyURL:   b.PolicyURL,
+	}
+	if b.Committee != nil {
+		c, err := b.Committee.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		j.Committee = c
+	}
+	payload, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(bundleEnvelope{
+		Bundle:    payload,
+		Publisher: hex.EncodeToString(publisher.Public().(ed25519.PublicKey)),
+		Signature: hex.EncodeToString(ed25519.Sign(publisher, bundleMessage(payload))),
+	})
+}
+
+// OpenBundle verifies a signed bundle and returns it, with the publisher key
+// it was signed with. The bundle must be valid at now. Callers should check
+// the publisher key, or pin the bundle
+func (s *Scheme[G1, G2, P1, P2]) OpenBundle(data []byte, now time.Time) (*Bundle[G1, G2, P1, P2], ed25519.PublicKey, error) {
+	env := bundleEnvelope{}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, nil, ErrInvalidBundle
+	}
+	pub, err := hex.DecodeString(env.Publisher)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return nil, nil, ErrInvalidBundle
+	}
+	sig, err := hex.DecodeString(env.Signature)
+	if err != nil || !ed25519.Verify(pub, bundleMessage(env.Bundle), sig) {
+		return nil, nil, ErrInvalidBundle
+	}
+
+	j := bundleJSON{}
+	if err := json.Unmarshal(env.Bundle, &j); err != nil || j.Version != bundleVersion {
+		return nil, nil, ErrInvalidBundle
+	}
+	if j.Suite != s.Suite {
+		return nil, nil, ErrSuiteMismatch
+	}
+	raw, err := hex.DecodeString(j.Adjudicator)
+	if err != nil {
+		return nil, nil, ErrInvalidBundle
+	}
+	b := Bundle[G1, G2, P1, P2]{
+		Adjudicator: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+		NotBefore:   time.Unix(j.NotBefore, 0),
+		NotAfter:    time.Unix(j.NotAfter, 0),
+		PolicyURL:   j.PolicyURL,
+	}
+	if err := b.Adjudicator.Unmarshal(raw); err != nil {
+		return nil, nil, err
+	}
+	if len(j.Committee) > 0 {
+		b.Committee = &Committee[G1, P1]{}
+		if err := b.Committee.UnmarshalJSON(j.Committee); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.checkBundle(&b); err != nil {
+		return nil, nil, err
+	}
+	if now.Before(b.NotBefore) || now.After(b.NotAfter) {
+		return nil, nil, ErrBundleExpired
+	}
+	return &b, pub, nil
+}
+
+// checkBundle checks that the adjudicator keys on G1 and G2 match, and match
+// the committee, if any
+func (s *Scheme[G1, G2, P1, P2]) checkBundle(b *Bundle[G1, G2, P1, P2]) error {
+	if b.Adjudicator == nil || !b.NotBefore.Before(b.NotAfter) {
+		return ErrInvalidBundle
+	}
+	if !inSubGroup[G1, P1](&b.Adjudicator.g1) || !inSubGroup[G2, P2](&b.Adjudicator.g2) {
+		return ErrInvalidPoint
+	}
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	ok, err := s.PairingCheck(
+		[]G1{*ng1, b.Adjudicator.g1},
+		[]G2{b.Adjudicator.g2, s.G2Gen},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidBundle
+	}
+	if b.Committee != nil {
+		if err := s.CheckCommittee(b.Committee); err != nil {
+			return err
+		}
+		if !b.Committee.AdjudicatorKey().Equal(&PublicKey[G1, P1]{b.Adjudicator.g1}) {
+			return ErrInvalidBundle
+		}
+	}
+	return nil
+}
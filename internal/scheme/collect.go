@@ -0,0 +1,71 @@
+package scheme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrNotEnoughPartials = errors.New("not enough valid partial adjudications")
+
+type devicePartial[G2 any, P2 Point[G2]] struct {
+	index int
+	pa    *Partial[G2, P2]
+	err   error
+}
+
+// CollectPartials queries devices, keyed by member index, for partial
+// adjudications of sig, until c.Threshold of them verify. Devices are queried
+// concurrently. If ctx is done first, the valid partials collected so far are
+// returned along with ctx.Err(). Device calls cannot be interrupted: they run
+// to completion in the background, and their results are discarded. If too
+// few partials verify, the members whose partials failed verification are
+// named in the error
+func (s *Scheme[G1, G2, P1, P2]) CollectPartials(ctx context.Context, c *Committee[G1, P1], sig *VESig[G2, P2], devices map[int]ShareDevice) ([]int, []*Partial[G2, P2], error) {
+	if err := s.CheckCommittee(c); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan devicePartial[G2, P2], len(devices))
+	for index, dev := range devices {
+		go func(index int, dev ShareDevice) {
+			pa, err := s.PartialAdjudicateDevice(dev, sig)
+			if err == nil {
+				var ok bool
+				ok, err = s.VerifyPartial(c, index, sig, pa)
+				if err == nil && !ok {
+					err = ErrInvalidPartial
+				}
+			}
+			ch <- devicePartial[G2, P2]{index, pa, err}
+		}(index, dev)
+	}
+
+	indices := []int{}
+	partials := []*Partial[G2, P2]{}
+	faulty := []int{}
+	for range devices {
+		select {
+		case <-ctx.Done():
+			return indices, partials, ctx.Err()
+		case res := <-ch:
+			if errors.Is(res.err, ErrInvalidPartial) {
+				faulty = append(faulty, res.index)
+			}
+			if res.err != nil {
+				continue
+			}
+			indices = append(indices, res.index)
+			partials = append(partials, res.pa)
+			if len(indices) == c.Threshold {
+				return indices, partials, nil
+			}
+		}
+	}
+	if len(faulty) > 0 {
+		sort.Ints(faulty)
+		return indices, partials, fmt.Errorf("%w: invalid partials from members %v", ErrNotEnoughPartials, faulty)
+	}
+	return indices, partials, ErrNotEnoughPartials
+}
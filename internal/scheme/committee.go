@@ -0,0 +1,280 @@
+package scheme
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+var (
+	ErrInvalidCommittee = errors.New("invalid committee")
+	ErrNotMember        = errors.New("not a committee member")
+	ErrInvalidPartial   = errors.New("invalid partial adjudication")
+)
+
+// Member is a committee member, holding the key share at Index
+type Member struct {
+	Index int    `json:"index"`
+	ID    string `json:"id"`
+}
+
+// Committee describes the holders of an adjudicator key split t-of-n, or of a
+// signer key, see PartialVESig
+type Committee[G1 any, P1 Point[G1]] struct {
+	ID        [32]byte
+	Threshold int
+	Epoch     uint64
+	Members   []Member
+	// Vector is the public verification vector. Vector[0] is the adjudicator
+	// public key on G1
+	Vector []*PublicKey[G1, P1]
+}
+
+// NewCommittee returns the committee for the output of SplitKeyShares.
+// memberIDs[i] identifies the holder of the share at index i+1
+func (s *Scheme[G1, G2, P1, P2]) NewCommittee(id [32]byte, epoch uint64, memberIDs []string, vector []*PublicKey[G1, P1]) (*Committee[G1, P1], error) {
+	c := Committee[G1, P1]{
+		ID:        id,
+		Threshold: len(vector),
+		Epoch:     epoch,
+		Members:   make([]Member, len(memberIDs)),
+		Vector:    append([]*PublicKey[G1, P1]{}, vector...),
+	}
+	for i, m := range memberIDs {
+		c.Members[i] = Member{Index: i + 1, ID: m}
+	}
+	if err := s.CheckCommittee(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// CheckCommittee checks that c is well formed: enough members with distinct
+// indices and identities, and a verification vector matching the threshold
+func (s *Scheme[G1, G2, P1, P2]) CheckCommittee(c *Committee[G1, P1]) error {
+	if c.Threshold < 1 || len(c.Vector) != c.Threshold ||
+		len(c.Members) < c.Threshold {
+		return ErrInvalidCommittee
+	}
+	indices := make(map[int]bool, len(c.Members))
+	ids := make(map[string]bool, len(c.Members))
+	for _, m := range c.Members {
+		if m.Index < 1 || m.ID == "" || indices[m.Index] || ids[m.ID] {
+			return ErrInvalidCommittee
+		}
+		indices[m.Index] = true
+		ids[m.ID] = true
+	}
+	for _, v := range c.Vector {
+		if v == nil || !inSubGroup[G1, P1](&v.p) {
+			return ErrInvalidCommittee
+		}
+	}
+	return nil
+}
+
+// Member returns the member holding the share at index
+func (c *Committee[G1, P1]) Member(index int) (Member, bool) {
+	for _, m := range c.Members {
+		if m.Index == index {
+			return m, true
+		}
+	}
+	return Member{}, false
+}
+
+// AdjudicatorKey returns the adjudicator public key on G1
+func (c *Committee[G1, P1]) AdjudicatorKey() *PublicKey[G1, P1] {
+	return c.Vector[0]
+}
+
+// CheckKeyShare checks that ks is a valid share of the committee
+func (s *Scheme[G1, G2, P1, P2]) CheckKeyShare(c *Committee[G1, P1], ks *KeyShare) error {
+	if ks.CommitteeID != c.ID || ks.Epoch != c.Epoch ||
+		ks.Threshold != c.Threshold {
+		return ErrShareMismatch
+	}
+	if _, ok := c.Member(ks.Index); !ok {
+		return ErrNotMember
+	}
+	return s.VerifyKeyShare(ks, c.Vector)
+}
+
+// MemberPublicKey returns g1^share for the share at index, computed from the
+// verification vector
+func (s *Scheme[G1, G2, P1, P2]) MemberPublicKey(c *Committee[G1, P1], index int) (*PublicKey[G1, P1], error) {
+	if _, ok := c.Member(index); !ok {
+		return nil, ErrNotMember
+	}
+
+	// Horner's rule, in the exponent
+	x := big.NewInt(int64(index))
+	pk := PublicKey[G1, P1]{}
+	for j := len(c.Vector) - 1; j >= 0; j-- {
+		P1(&pk.p).ScalarMultiplication(&pk.p, x)
+		P1(&pk.p).Add(&pk.p, &c.Vector[j].p)
+	}
+	return &pk, nil
+}
+
+// VerifyPartial checks that pa is mu^share for the share at index, that is
+// e(g1, pa) == e(g1^share, mu)
+func (s *Scheme[G1, G2, P1, P2]) VerifyPartial(c *Committee[G1, P1], index int, sig *VESig[G2, P2], pa *Partial[G2, P2]) (bool, error) {
+	pk, err := s.MemberPublicKey(c, index)
+	if err != nil {
+		return false, err
+	}
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	return s.PairingCheck(
+		[]G1{*ng1, pk.p},
+		[]G2{pa.p, sig.mu},
+	)
+}
+
+// CombineCommittee is Combine for partial adjudications from members of c.
+// Each partial is verified first, so a faulty member can be identified
+// instead of producing an invalid signature
+func (s *Scheme[G1, G2, P1, P2]) CombineCommittee(c *Committee[G1, P1], sig *VESig[G2, P2], indices []int, partials []*Partial[G2, P2]) (*Signature[G2, P2], error) {
+	if len(indices) < c.Threshold || len(indices) != len(partials) {
+		return nil, ErrInvalidThreshold
+	}
+	for i, index := range indices {
+		ok, err := s.VerifyPartial(c, index, sig, partials[i])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: member %d", ErrInvalidPartial, index)
+		}
+	}
+	return s.Combine(sig, indices[:c.Threshold], partials[:c.Threshold])
+}
+
+type committeeJSON struct {
+	ID        string   `json:"id"`
+	Threshold int      `json:"threshold"`
+	Epoch     uint64   `json:"epoch"`
+	Members   []Member `json:"members"`
+	Vector    []string `json:"vector"`
+}
+
+// sortedMembers returns the members of c by index
+func (c *Committee[G1, P1]) sortedMembers() []Member {
+	members := append([]Member{}, c.Members...)
+	sort.Slice(members, func(i, j int) bool { return members[i].Index < members[j].Index })
+	return members
+}
+
+// MarshalJSON encodes the committee as JSON, with hex encoded byte fields.
+// Fields are in a fixed order, members are sorted by index and the vector
+// is in coefficient order, so equal committees have equal encodings
+func (c *Committee[G1, P1]) MarshalJSON() ([]byte, error) {
+	j := committeeJSON{
+		ID:        hex.EncodeToString(c.ID[:]),
+		Threshold: c.Threshold,
+		Epoch:     c.Epoch,
+		Members:   c.sortedMembers(),
+		Vector:    make([]string, len(c.Vector)),
+	}
+	for i, v := range c.Vector {
+		j.Vector[i] = hex.EncodeToString(v.Marshal())
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a committee encoded by MarshalJSON. Use
+// CheckCommittee to validate it
+func (c *Committee[G1, P1]) UnmarshalJSON(b []byte) error {
+	j := committeeJSON{}
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	res := Committee[G1, P1]{
+		Threshold: j.Threshold,
+		Epoch:     j.Epoch,
+		Members:   j.Members,
+		Vector:    make([]*PublicKey[G1, P1], len(j.Vector)),
+	}
+	if err := decodeHex(res.ID[:], j.ID); err != nil {
+		return err
+	}
+	for i, v := range j.Vector {
+		raw, err := hex.DecodeString(v)
+		if err != nil {
+			return err
+		}
+		res.Vector[i] = &PublicKey[G1, P1]{}
+		if err := res.Vector[i].Unmarshal(raw); err != nil {
+			return err
+		}
+	}
+
+	*c = res
+	return nil
+}
+
+// MarshalCanonical returns the canonical encoding of c, as published by a
+// key ceremony: MarshalJSON indented with tabs, with a final newline
+func (c *Committee[G1, P1]) MarshalCanonical() ([]byte, error) {
+	b, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// UnmarshalCommittee decodes and checks a committee, which must be in
+// canonical form, see MarshalCanonical
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalCommittee(b []byte) (*Committee[G1, P1], error) {
+	c := Committee[G1, P1]{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, ErrInvalidCommittee
+	}
+	if err := s.CheckCommittee(&c); err != nil {
+		return nil, err
+	}
+	if cb, err := c.MarshalCanonical(); err != nil || string(cb) != string(b) {
+		return nil, ErrInvalidCommittee
+	}
+	return &c, nil
+}
+
+// Equal reports whether c and o describe the same committee, members listed
+// in the same order
+func (c *Committee[G1, P1]) Equal(o *Committee[G1, P1]) bool {
+	if c.ID != o.ID || c.Threshold != o.Threshold || c.Epoch != o.Epoch ||
+		len(c.Members) != len(o.Members) || len(c.Vector) != len(o.Vector) {
+		return false
+	}
+	for i := range c.Members {
+		if c.Members[i] != o.Members[i] {
+			return false
+		}
+	}
+	for i := range c.Vector {
+		if !c.Vector[i].Equal(o.Vector[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of c, sharing nothing with it
+func (c *Committee[G1, P1]) Clone() *Committee[G1, P1] {
+	cc := *c
+	cc.Members = append([]Member(nil), c.Members...)
+	cc.Vector = make([]*PublicKey[G1, P1], len(c.Vector))
+	for i, pk := range c.Vector {
+		cc.Vector[i] = pk.Clone()
+	}
+	return &cc
+}
+
+// String describes the committee
+func (c *Committee[G1, P1]) String() string {
+	return fmt.Sprintf("Committee(%x, epoch %d, %d of %d)", c.ID[:4], c.Epoch, c.Threshold, len(c.Members))
+}
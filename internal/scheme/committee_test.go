@@ -0,0 +1,51 @@
+package scheme
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCommitteeCanonical(t *testing.T) {
+	s := newTestScheme()
+	_, vector, err := s.SplitKeyShares(generateKey(t), 2, 3, [32]byte{1}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := s.NewCommittee([32]byte{1}, 1, []string{"a", "b", "c"}, vector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := c.MarshalCanonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.UnmarshalCommittee(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Fingerprint() != c.Fingerprint() {
+		t.Fatal("decoded committee differs")
+	}
+
+	// The order members are listed in is not part of the committee
+	shuffled := *c
+	shuffled.Members = []Member{c.Members[2], c.Members[0], c.Members[1]}
+	if sb, err := shuffled.MarshalCanonical(); err != nil || !bytes.Equal(sb, b) {
+		t.Fatalf("member order changed the encoding: %v", err)
+	}
+	if shuffled.Fingerprint() != c.Fingerprint() {
+		t.Fatal("member order changed the fingerprint")
+	}
+
+	compact, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, nc := range [][]byte{compact, b[:len(b)-1], append(append([]byte{}, b...), '\n')} {
+		if _, err := s.UnmarshalCommittee(nc); !errors.Is(err, ErrInvalidCommittee) {
+			t.Fatalf("non-canonical encoding: got %v, want %v", err, ErrInvalidCommittee)
+		}
+	}
+}
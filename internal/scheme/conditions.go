@@ -0,0 +1,103 @@
+package scheme
+
+import (
+	"crypto/sha256"
+	"errors"
+)
+
+var ErrInvalidConditions = errors.New("conditions not bound to the escrow")
+
+const conditionsTag = "VESS-ESCROW-CONDITIONS-V1"
+
+// ConditionsBinding is the signer's Schnorr signature binding a conditions
+// document to an escrow. The scheme treats the document as opaque bytes:
+// package policy defines and enforces it
+type ConditionsBinding[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	Signer           *PublicKey[G1, P1]
+	Adjudicator      *AdjudicatorPublicKey[G1, G2, P1, P2]
+	VESigDigest      [32]byte
+	ConditionsDigest [32]byte
+
+	sig schnorrSig[G1, P1]
+}
+
+// BindConditions binds conditions to sig, escrowed with adj. It does not
+// parse them, see policy.Bind
+func (s *Scheme[G1, G2, P1, P2]) BindConditions(sk *SecretKey, adj *AdjudicatorPublicKey[G1, G2, P1, P2], sig *VESig[G2, P2], conditions []byte) (*ConditionsBinding[G1, G2, P1, P2], error) {
+	b := ConditionsBinding[G1, G2, P1, P2]{
+		Signer:           s.PublicKey(sk),
+		Adjudicator:      adj,
+		VESigDigest:      sha256.Sum256(s.AppendVESig(nil, sig)),
+		ConditionsDigest: sha256.Sum256(conditions),
+	}
+	cs, err := s.schnorrSign(conditionsTag, sk, s.conditionsMessage(&b))
+	if err != nil {
+		return nil, err
+	}
+	b.sig = *cs
+	return &b, nil
+}
+
+// conditionsMessage is the signed part of the binding
+func (s *Scheme[G1, G2, P1, P2]) conditionsMessage(b *ConditionsBinding[G1, G2, P1, P2]) []byte {
+	m := []byte{byte(s.Suite)}
+	m = s.AppendPublicKey(m, b.Signer)
+	m = s.AppendAdjudicatorPublicKey(m, b.Adjudicator)
+	m = append(m, b.VESigDigest[:]...)
+	return append(m, b.ConditionsDigest[:]...)
+}
+
+// VerifyConditions checks that pk bound conditions to sig, escrowed with adj
+func (s *Scheme[G1, G2, P1, P2]) VerifyConditions(b *ConditionsBinding[G1, G2, P1, P2], pk *PublicKey[G1, P1], adj *AdjudicatorPublicKey[G1, G2, P1, P2], sig *VESig[G2, P2], conditions []byte) error {
+	if b.Signer == nil || b.Adjudicator == nil ||
+		!b.Signer.Equal(pk) || !b.Adjudicator.Equal(adj) ||
+		b.VESigDigest != sha256.Sum256(s.AppendVESig(nil, sig)) ||
+		b.ConditionsDigest != sha256.Sum256(conditions) {
+		return ErrInvalidConditions
+	}
+	if !s.schnorrVerify(conditionsTag, &b.Signer.p, s.conditionsMessage(b), &b.sig) {
+		return ErrInvalidConditions
+	}
+	return nil
+}
+
+// MarshalConditionsBinding encodes a binding
+func (s *Scheme[G1, G2, P1, P2]) MarshalConditionsBinding(b *ConditionsBinding[G1, G2, P1, P2]) []byte {
+	return s.appendSchnorr(s.conditionsMessage(b), &b.sig)
+}
+
+// UnmarshalConditionsBinding decodes a binding. Use VerifyConditions to
+// check it
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalConditionsBinding(b []byte) (*ConditionsBinding[G1, G2, P1, P2], error) {
+	n1 := len(P1(new(G1)).Marshal())
+	n2 := len(P2(new(G2)).Marshal())
+	if len(b) < 1+2*n1+n2+2*32 {
+		return nil, ErrInvalidLength
+	}
+	if Ciphersuite(b[0]) != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	b = b[1:]
+
+	cb := ConditionsBinding[G1, G2, P1, P2]{
+		Signer:      &PublicKey[G1, P1]{},
+		Adjudicator: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+	}
+	if err := cb.Signer.Unmarshal(b[:n1]); err != nil {
+		return nil, err
+	}
+	if err := cb.Adjudicator.Unmarshal(b[n1 : 2*n1+n2]); err != nil {
+		return nil, err
+	}
+	b = b[2*n1+n2:]
+	b = b[copy(cb.VESigDigest[:], b):]
+	b = b[copy(cb.ConditionsDigest[:], b):]
+	b, err := s.parseSchnorr(&cb.sig, b)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, ErrInvalidLength
+	}
+	return &cb, nil
+}
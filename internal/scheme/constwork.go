@@ -0,0 +1,132 @@
+package scheme
+
+import (
+	"bytes"
+	"sync/atomic"
+	"time"
+)
+
+// WorkTimings is the time VerifyConstantWork spent in each of its
+// operations, summed over Calls. Accepted and rejected submissions should
+// spend the same time in each
+type WorkTimings struct {
+	Calls    uint64
+	Decode   time.Duration
+	SubGroup time.Duration
+	Hash     time.Duration
+	Pairing  time.Duration
+}
+
+type workTimings struct {
+	calls    uint64
+	decode   int64
+	subGroup int64
+	hash     int64
+	pairing  int64
+}
+
+// WorkTimings returns the VerifyConstantWork timings
+func (s *Scheme[G1, G2, P1, P2]) WorkTimings() WorkTimings {
+	return WorkTimings{
+		Calls:    atomic.LoadUint64(&s.timings.calls),
+		Decode:   time.Duration(atomic.LoadInt64(&s.timings.decode)),
+		SubGroup: time.Duration(atomic.LoadInt64(&s.timings.subGroup)),
+		Hash:     time.Duration(atomic.LoadInt64(&s.timings.hash)),
+		Pairing:  time.Duration(atomic.LoadInt64(&s.timings.pairing)),
+	}
+}
+
+// lap adds the time since *start to d, and restarts *start
+func lap(d *int64, start *time.Time) {
+	now := time.Now()
+	atomic.AddInt64(d, int64(now.Sub(*start)))
+	*start = now
+}
+
+// VerifyConstantWork is Verify on the legacy encodings of pk, adj and sig,
+// performing the same work whatever the reason for rejection. Points are
+// decoded on the curve only, keys that are the identity are rejected as
+// Unmarshal rejects them, every subgroup check runs, the message is
+// always hashed and the pairing check always runs, on generators in place
+// of whatever failed to decode or hash. Failures are folded into a single
+// false once all work is done, so neither the time taken nor the result
+// tells a rejected submitter which check failed. The only error is
+// ErrLenientUnsupported, for curves without lenient decoders.
+// The underlying curve arithmetic is not constant time: this hides the
+// control flow, not data dependent timings within each operation
+func (s *Scheme[G1, G2, P1, P2]) VerifyConstantWork(pk, adj, msg, sig []byte) (bool, error) {
+	if s.DecodeG1Lenient == nil || s.DecodeG2Lenient == nil {
+		return false, ErrLenientUnsupported
+	}
+	start := time.Now()
+
+	// Encodings of the wrong length are replaced by zeros, which are
+	// decoded all the same
+	n1, n2 := s.pointSizes(Version1)
+	ok := len(pk) == n1 && len(adj) == n1+n2 && len(sig) == 2*n2
+	pk, adj, sig = fit(pk, n1), fit(adj, n1+n2), fit(sig, 2*n2)
+
+	var pkp, adjG1 G1
+	var adjG2, omega, mu G2
+	ok = decodeOrGen(&pkp, pk, s.DecodeG1Lenient, s.AppendG1, &s.G1Gen) && ok
+	ok = decodeOrGen(&adjG1, adj[:n1], s.DecodeG1Lenient, s.AppendG1, &s.G1Gen) && ok
+	ok = decodeOrGen(&adjG2, adj[n1:], s.DecodeG2Lenient, s.AppendG2, &s.G2Gen) && ok
+	ok = decodeOrGen(&omega, sig[:n2], s.DecodeG2Lenient, s.AppendG2, &s.G2Gen) && ok
+	ok = decodeOrGen(&mu, sig[n2:], s.DecodeG2Lenient, s.AppendG2, &s.G2Gen) && ok
+	ok = notIdentity[G1, P1](&pkp, &s.G1Gen) && ok
+	ok = notIdentity[G1, P1](&adjG1, &s.G1Gen) && ok
+	ok = notIdentity[G2, P2](&adjG2, &s.G2Gen) && ok
+	lap(&s.timings.decode, &start)
+
+	ok = inSubGroup[G1, P1](&pkp) && ok
+	ok = inSubGroup[G1, P1](&adjG1) && ok
+	ok = inSubGroup[G2, P2](&adjG2) && ok
+	ok = inSubGroup[G2, P2](&omega) && ok
+	ok = inSubGroup[G2, P2](&mu) && ok
+	lap(&s.timings.subGroup, &start)
+
+	h, err := s.Hash(msg)
+	if err != nil {
+		h = s.G2Gen
+	}
+	ok = err == nil && ok
+	lap(&s.timings.hash, &start)
+
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	valid, err := s.PairingCheck(
+		[]G1{*ng1, pkp, adjG1},
+		[]G2{omega, h, mu},
+	)
+	lap(&s.timings.pairing, &start)
+	atomic.AddUint64(&s.timings.calls, 1)
+
+	return ok && err == nil && valid, nil
+}
+
+// fit returns b if it is n bytes long, and n zeros otherwise
+func fit(b []byte, n int) []byte {
+	if len(b) != n {
+		return make([]byte, n)
+	}
+	return b
+}
+
+// decodeOrGen decodes the canonical encoding b into p, or sets p to gen and
+// returns false
+func decodeOrGen[T any](p *T, b []byte, decode func(*T, []byte) error, encode func([]byte, *T) []byte, gen *T) bool {
+	if err := decode(p, b); err != nil {
+		*p = *gen
+		return false
+	}
+	return bytes.Equal(encode(nil, p), b)
+}
+
+// notIdentity sets p to gen and returns false if p is the identity
+func notIdentity[T any, P Point[T]](p *T, gen *T) bool {
+	if P(p).IsInfinity() {
+		*p = *gen
+		return false
+	}
+	return true
+}
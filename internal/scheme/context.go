@@ -0,0 +1,45 @@
+package scheme
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidContext = errors.New("invalid context")
+
+const contextTag = "VESS-CTX-V1"
+
+// Context binds escrows to an application and network. Instances configured
+// with a context hash
+//
+//	"VESS-CTX-V1" | len(AppID) | AppID | ChainID | Version | msg
+//
+// in place of msg, with a one byte length and big-endian integers, so an
+// escrow made for another application, chain or version never verifies.
+// Recovered signatures are BLS signatures on the framed message
+type Context struct {
+	AppID   string
+	ChainID uint64
+	Version uint32
+}
+
+// Check checks that the application ID is set and fits its length prefix
+func (c *Context) Check() error {
+	if len(c.AppID) == 0 || len(c.AppID) > 255 {
+		return ErrInvalidContext
+	}
+	return nil
+}
+
+// Frame returns msg, prefixed with the context
+func (c *Context) Frame(msg []byte) []byte {
+	b := make([]byte, 0, len(contextTag)+1+len(c.AppID)+8+4+len(msg))
+	b = append(b, contextTag...)
+	b = append(b, byte(len(c.AppID)))
+	b = append(b, c.AppID...)
+	n := len(b)
+	b = b[:n+12]
+	binary.BigEndian.PutUint64(b[n:], c.ChainID)
+	binary.BigEndian.PutUint32(b[n+8:], c.Version)
+	return append(b, msg...)
+}
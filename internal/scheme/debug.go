@@ -0,0 +1,96 @@
+//go:build vessdebug
+
+package scheme
+
+import "crypto/sha256"
+
+// VerifyReport explains the outcome of VerifyDebug
+type VerifyReport struct {
+	Valid bool
+	// Err is set if the message could not be hashed
+	Err error
+	// Mismatch names the failing check, or the likely cause of failure
+	Mismatch string
+
+	PublicKeyInSubgroup   bool
+	AdjudicatorInSubgroup bool
+	OmegaInSubgroup       bool
+	MuInSubgroup          bool
+
+	// e(v, g2) == e(g1, v'): the adjudicator keys on G1 and G2 match
+	AdjudicatorConsistent bool
+	// e(g1, omega) == e(pk, h): omega is a plain signature, never masked
+	PlainSignature bool
+	// The escrow verifies with omega and mu swapped
+	Swapped bool
+
+	// DST in use, set by the curve packages
+	DST string
+	// SHA-256 of the message and of the uncompressed inputs, and the
+	// recomputed H(m), to compare with another implementation
+	MessageDigest     [32]byte
+	PublicKeyDigest   [32]byte
+	AdjudicatorDigest [32]byte
+	VESigDigest       [32]byte
+	HashToCurve       []byte
+}
+
+// VerifyDebug is Verify, reporting every intermediate check. It runs up to
+// four pairing checks where Verify runs one, and is only built with the
+// vessdebug tag, to keep it off production verification paths
+func (s *Scheme[G1, G2, P1, P2]) VerifyDebug(pk *PublicKey[G1, P1], adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, sig *VESig[G2, P2]) *VerifyReport {
+	r := VerifyReport{
+		PublicKeyInSubgroup: inSubGroup[G1, P1](&pk.p),
+		AdjudicatorInSubgroup: inSubGroup[G1, P1](&adj.g1) &&
+			inSubGroup[G2, P2](&adj.g2),
+		OmegaInSubgroup:   inSubGroup[G2, P2](&sig.omega),
+		MuInSubgroup:      inSubGroup[G2, P2](&sig.mu),
+		MessageDigest:     sha256.Sum256(msg),
+		PublicKeyDigest:   sha256.Sum256(s.AppendPublicKey(nil, pk)),
+		AdjudicatorDigest: sha256.Sum256(s.AppendAdjudicatorPublicKey(nil, adj)),
+		VESigDigest:       sha256.Sum256(s.AppendVESig(nil, sig)),
+	}
+
+	h, err := s.Hash(msg)
+	if err != nil {
+		r.Err = err
+		r.Mismatch = "hash to curve failed"
+		return &r
+	}
+	r.HashToCurve = s.AppendG2(nil, &h)
+
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	check := func(p []G1, q []G2) bool {
+		ok, err := s.PairingCheck(p, q)
+		if err != nil && r.Err == nil {
+			r.Err = err
+		}
+		return ok
+	}
+	r.Valid = check([]G1{*ng1, pk.p, adj.g1}, []G2{sig.omega, h, sig.mu})
+	r.AdjudicatorConsistent = check([]G1{*ng1, adj.g1}, []G2{adj.g2, s.G2Gen})
+	if r.Valid {
+		return &r
+	}
+	r.PlainSignature = check([]G1{*ng1, pk.p}, []G2{sig.omega, h})
+	r.Swapped = check([]G1{*ng1, pk.p, adj.g1}, []G2{sig.mu, h, sig.omega})
+
+	switch {
+	case !r.PublicKeyInSubgroup:
+		r.Mismatch = "public key not in the subgroup"
+	case !r.AdjudicatorInSubgroup:
+		r.Mismatch = "adjudicator key not in the subgroup"
+	case !r.OmegaInSubgroup || !r.MuInSubgroup:
+		r.Mismatch = "VESig point not in the subgroup"
+	case !r.AdjudicatorConsistent:
+		r.Mismatch = "adjudicator keys on G1 and G2 differ"
+	case r.PlainSignature:
+		r.Mismatch = "omega is a plain signature: the escrow is not masked"
+	case r.Swapped:
+		r.Mismatch = "omega and mu are swapped"
+	default:
+		r.Mismatch = "e(g1, omega) != e(pk, H(m)) . e(v, mu): wrong message, DST, signer or adjudicator key"
+	}
+	return &r
+}
@@ -0,0 +1,21 @@
+package scheme
+
+// ShareDevice holds a key share and computes mu^share without exposing it,
+// such as a hardware wallet driven through package apdu
+type ShareDevice interface {
+	PartialAdjudicate(mu []byte) ([]byte, error)
+}
+
+// PartialAdjudicateDevice is PartialAdjudicate, performed by dev. The
+// returned partial is decoded strictly, but not verified: use VerifyPartial
+func (s *Scheme[G1, G2, P1, P2]) PartialAdjudicateDevice(dev ShareDevice, sig *VESig[G2, P2]) (*Partial[G2, P2], error) {
+	res, err := dev.PartialAdjudicate(P2(&sig.mu).Marshal())
+	if err != nil {
+		return nil, err
+	}
+	pa := Partial[G2, P2]{}
+	if err := pa.Unmarshal(res); err != nil {
+		return nil, err
+	}
+	return &pa, nil
+}
@@ -0,0 +1,47 @@
+package scheme
+
+// AdjudicationPreview describes what an adjudication would release
+type AdjudicationPreview[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	// Signer and Message identify the signature that would be released
+	Signer  *PublicKey[G1, P1]
+	Message []byte
+	// Adjudicator is the key the escrow is encrypted to
+	Adjudicator *AdjudicatorPublicKey[G1, G2, P1, P2]
+	VESig       *VESig[G2, P2]
+	// Valid reports whether adjudication would release a valid signature
+	// on Message under Signer. Reason is set when it would not
+	Valid  bool
+	Reason error
+}
+
+// DryRunAdjudicate runs every check Adjudicate relies on, without touching
+// the adjudicator secret key: the points are validated and the escrow is
+// verified against the adjudicator public key. An escrow that passes is
+// guaranteed to adjudicate to a valid signature on msg under pk
+func (s *Scheme[G1, G2, P1, P2]) DryRunAdjudicate(adj *AdjudicatorPublicKey[G1, G2, P1, P2], pk *PublicKey[G1, P1], msg []byte, sig *VESig[G2, P2]) (*AdjudicationPreview[G1, G2, P1, P2], error) {
+	p := AdjudicationPreview[G1, G2, P1, P2]{
+		Signer:      pk,
+		Message:     append([]byte{}, msg...),
+		Adjudicator: adj,
+		VESig:       sig,
+	}
+
+	if !inSubGroup[G1, P1](&pk.p) || !inSubGroup[G1, P1](&adj.g1) ||
+		!inSubGroup[G2, P2](&adj.g2) || !inSubGroup[G2, P2](&sig.omega) ||
+		!inSubGroup[G2, P2](&sig.mu) {
+		p.Reason = ErrInvalidPoint
+		return &p, nil
+	}
+
+	ok, err := s.Verify(pk, adj, msg, sig)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		p.Reason = ErrInvalidSignature
+		return &p, nil
+	}
+
+	p.Valid = true
+	return &p, nil
+}
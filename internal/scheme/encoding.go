@@ -0,0 +1,160 @@
+package scheme
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ScalarSize is the size of a serialized secret key. The scalar fields of all
+// supported curves fit in 32 bytes
+const ScalarSize = 32
+
+var (
+	ErrInvalidLength    = errors.New("invalid encoding length")
+	ErrInvalidSecretKey = errors.New("invalid secret key")
+	ErrNonCanonical     = errors.New("non-canonical point encoding")
+)
+
+// unmarshalPoint decodes an uncompressed point, rejecting every encoding but
+// the one Marshal returns. gnark also accepts compressed encodings, trailing
+// bytes, coordinates not reduced modulo p and infinity with stray bits, which
+// would make encodings malleable
+func unmarshalPoint[T any, P Point[T]](p *T, b []byte) error {
+	if err := P(p).Unmarshal(b); err != nil {
+		return err
+	}
+	if !bytes.Equal(P(p).Marshal(), b) {
+		return ErrNonCanonical
+	}
+	return nil
+}
+
+// unmarshalKey is unmarshalPoint for keys and signatures, which must not be
+// the identity. p is left untouched on error
+func unmarshalKey[T any, P Point[T]](p *T, b []byte) error {
+	q := new(T)
+	if err := unmarshalPoint[T, P](q, b); err != nil {
+		return err
+	}
+	if P(q).IsInfinity() {
+		return ErrIdentity
+	}
+	*p = *q
+	return nil
+}
+
+// Marshal returns the big-endian encoding of the secret scalar
+func (sk *SecretKey) Marshal() []byte {
+	return sk.AppendBinary(make([]byte, 0, ScalarSize))
+}
+
+// AppendBinary appends the big-endian encoding of the secret scalar to dst
+func (sk *SecretKey) AppendBinary(dst []byte) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, ScalarSize)...)
+	sk.x.FillBytes(dst[n:])
+	return dst
+}
+
+// SecretKeyFromBytes decodes a secret key, rejecting zero and scalars not
+// reduced modulo the group order
+func (s *Scheme[G1, G2, P1, P2]) SecretKeyFromBytes(b []byte) (*SecretKey, error) {
+	if len(b) != ScalarSize {
+		return nil, ErrInvalidLength
+	}
+	sk := SecretKey{}
+	sk.x.SetBytes(b)
+	if sk.x.Sign() == 0 || sk.x.Cmp(s.Order) >= 0 {
+		return nil, ErrInvalidSecretKey
+	}
+	return &sk, nil
+}
+
+// Marshal returns the uncompressed encoding of the public key
+func (pk *PublicKey[G1, P1]) Marshal() []byte {
+	return P1(&pk.p).Marshal()
+}
+
+// Unmarshal decodes a public key. The point must be in G1, and not the
+// identity
+func (pk *PublicKey[G1, P1]) Unmarshal(b []byte) error {
+	return unmarshalKey[G1, P1](&pk.p, b)
+}
+
+// Marshal returns the uncompressed encodings of the G1 and G2 keys
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) Marshal() []byte {
+	return append(P1(&apk.g1).Marshal(), P2(&apk.g2).Marshal()...)
+}
+
+// Unmarshal decodes an adjudicator public key. Neither point may be the
+// identity
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) Unmarshal(b []byte) error {
+	n1 := len(P1(new(G1)).Marshal())
+	n2 := len(P2(new(G2)).Marshal())
+	if len(b) != n1+n2 {
+		return ErrInvalidLength
+	}
+	if err := unmarshalKey[G1, P1](&apk.g1, b[:n1]); err != nil {
+		return err
+	}
+	return unmarshalKey[G2, P2](&apk.g2, b[n1:])
+}
+
+// Marshal returns the uncompressed encoding of the signature
+func (s *Signature[G2, P2]) Marshal() []byte {
+	return P2(&s.p).Marshal()
+}
+
+// Unmarshal decodes a signature. The point must be in G2, and not the
+// identity
+func (s *Signature[G2, P2]) Unmarshal(b []byte) error {
+	return unmarshalKey[G2, P2](&s.p, b)
+}
+
+// Marshal returns the uncompressed encodings of omega and mu
+func (sig *VESig[G2, P2]) Marshal() []byte {
+	return append(P2(&sig.omega).Marshal(), P2(&sig.mu).Marshal()...)
+}
+
+// Unmarshal decodes a verifiably encrypted signature
+func (sig *VESig[G2, P2]) Unmarshal(b []byte) error {
+	n := len(P2(new(G2)).Marshal())
+	if len(b) != 2*n {
+		return ErrInvalidLength
+	}
+	if err := unmarshalPoint[G2, P2](&sig.omega, b[:n]); err != nil {
+		return err
+	}
+	return unmarshalPoint[G2, P2](&sig.mu, b[n:])
+}
+
+// The Append methods append the same encodings as Marshal to dst, reusing
+// its capacity. Unmarshal never retains its input, so decode buffers can be
+// reused as well
+
+// AppendPublicKey appends the encoding of pk to dst
+func (s *Scheme[G1, G2, P1, P2]) AppendPublicKey(dst []byte, pk *PublicKey[G1, P1]) []byte {
+	return s.AppendG1(dst, &pk.p)
+}
+
+// AppendAdjudicatorPublicKey appends the encoding of apk to dst
+func (s *Scheme[G1, G2, P1, P2]) AppendAdjudicatorPublicKey(dst []byte, apk *AdjudicatorPublicKey[G1, G2, P1, P2]) []byte {
+	dst = s.AppendG1(dst, &apk.g1)
+	return s.AppendG2(dst, &apk.g2)
+}
+
+// AppendSignature appends the encoding of sig to dst
+func (s *Scheme[G1, G2, P1, P2]) AppendSignature(dst []byte, sig *Signature[G2, P2]) []byte {
+	return s.AppendG2(dst, &sig.p)
+}
+
+// AppendVESig appends the encoding of sig to dst
+func (s *Scheme[G1, G2, P1, P2]) AppendVESig(dst []byte, sig *VESig[G2, P2]) []byte {
+	dst = s.AppendG2(dst, &sig.omega)
+	return s.AppendG2(dst, &sig.mu)
+}
+
+// AppendPartial appends the encoding of pa to dst
+func (s *Scheme[G1, G2, P1, P2]) AppendPartial(dst []byte, pa *Partial[G2, P2]) []byte {
+	return s.AppendG2(dst, &pa.p)
+}
@@ -0,0 +1,86 @@
+package scheme
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidFingerprint = errors.New("invalid fingerprint")
+
+// Fingerprint identifies a key or a committee in audit logs, policy rules and
+// CLI output: the first 16 bytes of the SHA-256 hash of a type tag and the
+// object's encoding. Keys of different types never share a fingerprint
+type Fingerprint [16]byte
+
+// String returns the fingerprint and a 2 byte checksum in hex, in groups of
+// four digits
+func (f Fingerprint) String() string {
+	sum := sha256.Sum256(f[:])
+	s := hex.EncodeToString(append(f[:], sum[:2]...))
+	groups := make([]string, 0, len(s)/4)
+	for i := 0; i < len(s); i += 4 {
+		groups = append(groups, s[i:i+4])
+	}
+	return strings.Join(groups, "-")
+}
+
+// ParseFingerprint decodes a fingerprint formatted by String, validating the
+// checksum to catch typos
+func ParseFingerprint(s string) (Fingerprint, error) {
+	f := Fingerprint{}
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != len(f)+2 {
+		return f, ErrInvalidFingerprint
+	}
+	copy(f[:], b)
+	sum := sha256.Sum256(f[:])
+	if !bytes.Equal(b[len(f):], sum[:2]) {
+		return f, ErrInvalidFingerprint
+	}
+	return f, nil
+}
+
+func fingerprint(tag string, parts ...[]byte) Fingerprint {
+	h := sha256.New()
+	h.Write([]byte(tag))
+	for _, p := range parts {
+		h.Write(p)
+	}
+	f := Fingerprint{}
+	copy(f[:], h.Sum(nil))
+	return f
+}
+
+// Fingerprint returns the fingerprint of pk
+func (pk *PublicKey[G1, P1]) Fingerprint() Fingerprint {
+	return fingerprint("VESS-FP-PK", pk.Marshal())
+}
+
+// Fingerprint returns the fingerprint of apk
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) Fingerprint() Fingerprint {
+	return fingerprint("VESS-FP-APK", apk.Marshal())
+}
+
+// Fingerprint returns the fingerprint of c, covering its ID, epoch,
+// threshold, members, by index, and verification vector
+func (c *Committee[G1, P1]) Fingerprint() Fingerprint {
+	b := make([]byte, 32+8+4)
+	copy(b, c.ID[:])
+	binary.BigEndian.PutUint64(b[32:], c.Epoch)
+	binary.BigEndian.PutUint32(b[40:], uint32(c.Threshold))
+	for _, m := range c.sortedMembers() {
+		n := len(b)
+		b = append(b, make([]byte, 8)...)
+		binary.BigEndian.PutUint32(b[n:], uint32(m.Index))
+		binary.BigEndian.PutUint32(b[n+4:], uint32(len(m.ID)))
+		b = append(b, m.ID...)
+	}
+	for _, v := range c.Vector {
+		b = append(b, v.Marshal()...)
+	}
+	return fingerprint("VESS-FP-COMMITTEE", b)
+}
@@ -0,0 +1,139 @@
+package scheme
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	ErrInvalidHashLock = errors.New("invalid hashlock")
+	ErrWrongPreimage   = errors.New("preimage does not open the hashlock")
+	ErrHashLockExpired = errors.New("hashlock expired")
+)
+
+const hashLockTag = "VESS-HASHLOCK-V1"
+
+// HashLock makes an escrow HTLC-like: the adjudicator opens it only for the
+// SHA-256 preimage of Digest, revealed before Expiry. Publishing the preimage
+// along with the opened signature exchanges one for the other atomically,
+// and after Expiry the escrow is never opened, which refunds the signer.
+// The signer binds the lock to the escrow with a Schnorr signature
+type HashLock[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	Signer      *PublicKey[G1, P1]
+	Adjudicator *AdjudicatorPublicKey[G1, G2, P1, P2]
+	VESigDigest [32]byte
+	Digest      [32]byte
+	// Unix time
+	Expiry uint64
+
+	sig schnorrSig[G1, P1]
+}
+
+// NewHashLock locks sig, escrowed with adj, to the preimage of digest until
+// expiry
+func (s *Scheme[G1, G2, P1, P2]) NewHashLock(sk *SecretKey, adj *AdjudicatorPublicKey[G1, G2, P1, P2], sig *VESig[G2, P2], digest [32]byte, expiry uint64) (*HashLock[G1, G2, P1, P2], error) {
+	l := HashLock[G1, G2, P1, P2]{
+		Signer:      s.PublicKey(sk),
+		Adjudicator: adj,
+		VESigDigest: sha256.Sum256(s.AppendVESig(nil, sig)),
+		Digest:      digest,
+		Expiry:      expiry,
+	}
+	ls, err := s.schnorrSign(hashLockTag, sk, s.hashLockMessage(&l))
+	if err != nil {
+		return nil, err
+	}
+	l.sig = *ls
+	return &l, nil
+}
+
+// hashLockMessage is the signed part of the lock
+func (s *Scheme[G1, G2, P1, P2]) hashLockMessage(l *HashLock[G1, G2, P1, P2]) []byte {
+	b := []byte{byte(s.Suite)}
+	b = s.AppendPublicKey(b, l.Signer)
+	b = s.AppendAdjudicatorPublicKey(b, l.Adjudicator)
+	b = append(b, l.VESigDigest[:]...)
+	b = append(b, l.Digest[:]...)
+	n := len(b)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[n:], l.Expiry)
+	return b
+}
+
+// VerifyHashLock checks that pk locked sig, escrowed with adj, with l
+func (s *Scheme[G1, G2, P1, P2]) VerifyHashLock(l *HashLock[G1, G2, P1, P2], pk *PublicKey[G1, P1], adj *AdjudicatorPublicKey[G1, G2, P1, P2], sig *VESig[G2, P2]) error {
+	if l.Signer == nil || l.Adjudicator == nil ||
+		!l.Signer.Equal(pk) || !l.Adjudicator.Equal(adj) ||
+		l.VESigDigest != sha256.Sum256(s.AppendVESig(nil, sig)) {
+		return ErrInvalidHashLock
+	}
+	if !s.schnorrVerify(hashLockTag, &l.Signer.p, s.hashLockMessage(l), &l.sig) {
+		return ErrInvalidHashLock
+	}
+	return nil
+}
+
+// Opens reports whether preimage opens l
+func (l *HashLock[G1, G2, P1, P2]) Opens(preimage []byte) bool {
+	d := sha256.Sum256(preimage)
+	return subtle.ConstantTimeCompare(d[:], l.Digest[:]) == 1
+}
+
+// AdjudicateHashLock verifies sig and its lock, and adjudicates it if
+// preimage opens the lock and now, in Unix time, is not past its expiry. The
+// adjudicator must then publish preimage to the signer
+func (s *Scheme[G1, G2, P1, P2]) AdjudicateHashLock(adjSK *SecretKey, pk *PublicKey[G1, P1], msg []byte, sig *VESig[G2, P2], l *HashLock[G1, G2, P1, P2], preimage []byte, now uint64) (*Signature[G2, P2], error) {
+	if err := s.VerifyHashLock(l, pk, s.AdjudicatorPublicKey(adjSK), sig); err != nil {
+		return nil, err
+	}
+	if now > l.Expiry {
+		return nil, ErrHashLockExpired
+	}
+	if !l.Opens(preimage) {
+		return nil, ErrWrongPreimage
+	}
+	return s.VerifyAndAdjudicate(adjSK, pk, msg, sig)
+}
+
+// MarshalHashLock encodes a lock
+func (s *Scheme[G1, G2, P1, P2]) MarshalHashLock(l *HashLock[G1, G2, P1, P2]) []byte {
+	return s.appendSchnorr(s.hashLockMessage(l), &l.sig)
+}
+
+// UnmarshalHashLock decodes a lock. Use VerifyHashLock to check it
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalHashLock(b []byte) (*HashLock[G1, G2, P1, P2], error) {
+	n1 := len(P1(new(G1)).Marshal())
+	n2 := len(P2(new(G2)).Marshal())
+	if len(b) < 1+2*n1+n2+2*32+8 {
+		return nil, ErrInvalidLength
+	}
+	if Ciphersuite(b[0]) != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	b = b[1:]
+
+	l := HashLock[G1, G2, P1, P2]{
+		Signer:      &PublicKey[G1, P1]{},
+		Adjudicator: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+	}
+	if err := l.Signer.Unmarshal(b[:n1]); err != nil {
+		return nil, err
+	}
+	if err := l.Adjudicator.Unmarshal(b[n1 : 2*n1+n2]); err != nil {
+		return nil, err
+	}
+	b = b[2*n1+n2:]
+	b = b[copy(l.VESigDigest[:], b):]
+	b = b[copy(l.Digest[:], b):]
+	l.Expiry = binary.BigEndian.Uint64(b)
+	b, err := s.parseSchnorr(&l.sig, b[8:])
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, ErrInvalidLength
+	}
+	return &l, nil
+}
@@ -0,0 +1,93 @@
+package scheme
+
+import (
+	"crypto/sha512"
+	"errors"
+	"math/big"
+)
+
+var ErrDuplicateKey = errors.New("duplicate public key")
+
+const keyAggTag = "VESS-KEYAGG-V1"
+
+// keyAggCoefficients returns a_i = H(tag || L || pk_i) mod order, where L is
+// the encoding of all keys in order. The coefficients depend on every key,
+// so no cosigner can choose its key to cancel the others (rogue key attack)
+func (s *Scheme[G1, G2, P1, P2]) keyAggCoefficients(pks []*PublicKey[G1, P1]) ([]*big.Int, error) {
+	if len(pks) == 0 {
+		return nil, ErrInvalidLength
+	}
+	l := []byte{}
+	seen := make(map[string]bool, len(pks))
+	for _, pk := range pks {
+		if !inSubGroup[G1, P1](&pk.p) {
+			return nil, ErrInvalidPoint
+		}
+		n := len(l)
+		l = s.AppendPublicKey(l, pk)
+		if seen[string(l[n:])] {
+			return nil, ErrDuplicateKey
+		}
+		seen[string(l[n:])] = true
+	}
+
+	res := make([]*big.Int, len(pks))
+	for i, pk := range pks {
+		h := sha512.New()
+		h.Write([]byte(keyAggTag))
+		h.Write(l)
+		h.Write(s.AppendPublicKey(nil, pk))
+		a := new(big.Int).SetBytes(h.Sum(nil))
+		res[i] = a.Mod(a, s.Order)
+	}
+	return res, nil
+}
+
+// AggregatePublicKeys returns the joint key sum a_i.pk_i of cosigners pks.
+// Joint escrows from CombineJoint verify under it as under any signer key
+func (s *Scheme[G1, G2, P1, P2]) AggregatePublicKeys(pks []*PublicKey[G1, P1]) (*PublicKey[G1, P1], error) {
+	a, err := s.keyAggCoefficients(pks)
+	if err != nil {
+		return nil, err
+	}
+	res := PublicKey[G1, P1]{}
+	t := new(G1)
+	for i, pk := range pks {
+		P1(t).ScalarMultiplication(&pk.p, a[i])
+		P1(&res.p).Add(&res.p, t)
+	}
+	return &res, nil
+}
+
+// CombineJoint combines escrows of msg by cosigners pks, all to adj, in the
+// order of pks, into one escrow under AggregatePublicKeys(pks):
+//
+//	omega = sum a_i.omega_i, mu = sum a_i.mu_i
+//
+// Each escrow is verified first
+func (s *Scheme[G1, G2, P1, P2]) CombineJoint(pks []*PublicKey[G1, P1], adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, sigs []*VESig[G2, P2]) (*VESig[G2, P2], error) {
+	if len(sigs) != len(pks) {
+		return nil, ErrInvalidLength
+	}
+	a, err := s.keyAggCoefficients(pks)
+	if err != nil {
+		return nil, err
+	}
+
+	res := VESig[G2, P2]{}
+	t := new(G2)
+	for i, sig := range sigs {
+		ok, err := s.Verify(pks[i], adj, msg, sig)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrInvalidSignature
+		}
+		P2(t).ScalarMultiplication(&sig.omega, a[i])
+		P2(&res.omega).Add(&res.omega, t)
+		P2(t).ScalarMultiplication(&sig.mu, a[i])
+		P2(&res.mu).Add(&res.mu, t)
+	}
+	return &res, nil
+}
@@ -0,0 +1,305 @@
+package scheme
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	ErrInvalidKeyShare = errors.New("invalid key share")
+	ErrShareMismatch   = errors.New("key shares belong to different committees")
+)
+
+// Sizes of encoded key shares, header excluded
+const (
+	keyShareMetadataSize = 4 + 4 + 32 + 32 + 8
+	keyShareSize         = keyShareMetadataSize + ScalarSize
+)
+
+// KeyShare is an adjudicator key share, tagged with the committee it belongs
+// to. Shares are only meant to be combined with shares from the same
+// committee and epoch, see CheckKeyShares
+type KeyShare struct {
+	Suite Ciphersuite
+	// Index is the x coordinate of the share, starting at 1
+	Index     int
+	Threshold int
+	// CommitteeID is chosen by the dealer
+	CommitteeID [32]byte
+	// VectorHash is the SHA-256 hash of the public verification vector
+	VectorHash [32]byte
+	Epoch      uint64
+	Secret     *SecretKey
+}
+
+// SplitKeyShares is SplitKey, but returns shares tagged with their committee,
+// along with the public verification vector: the commitments g1^a_j to the
+// polynomial coefficients (Feldman VSS). vector[0] is the adjudicator public
+// key on G1
+func (s *Scheme[G1, G2, P1, P2]) SplitKeyShares(sk *SecretKey, t, n int, committeeID [32]byte, epoch uint64) ([]*KeyShare, []*PublicKey[G1, P1], error) {
+	poly, err := s.polynomial(sk, t, n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vector := make([]*PublicKey[G1, P1], t)
+	for j, c := range poly {
+		vector[j] = &PublicKey[G1, P1]{}
+		P1(&vector[j].p).ScalarMultiplication(&s.G1Gen, c)
+	}
+	vh := s.VectorHash(vector)
+
+	shares := make([]*KeyShare, n)
+	for i := range shares {
+		shares[i] = &KeyShare{
+			Suite:       s.Suite,
+			Index:       i + 1,
+			Threshold:   t,
+			CommitteeID: committeeID,
+			VectorHash:  vh,
+			Epoch:       epoch,
+			Secret:      s.evaluate(poly, i+1),
+		}
+	}
+
+	return shares, vector, nil
+}
+
+// VectorHash returns the hash of a public verification vector
+func (s *Scheme[G1, G2, P1, P2]) VectorHash(vector []*PublicKey[G1, P1]) [32]byte {
+	h := sha256.New()
+	var buf []byte
+	for _, c := range vector {
+		buf = s.AppendG1(buf[:0], &c.p)
+		h.Write(buf)
+	}
+	res := [32]byte{}
+	h.Sum(res[:0])
+	return res
+}
+
+// VerifyKeyShare checks ks against the public verification vector, that is
+// g1^share == prod vector[j]^(index^j)
+func (s *Scheme[G1, G2, P1, P2]) VerifyKeyShare(ks *KeyShare, vector []*PublicKey[G1, P1]) error {
+	if ks.Suite != s.Suite {
+		return ErrSuiteMismatch
+	}
+	if ks.Index < 1 || ks.Threshold != len(vector) || ks.Secret == nil ||
+		ks.Secret.x.Sign() == 0 || ks.Secret.x.Cmp(s.Order) >= 0 {
+		return ErrInvalidKeyShare
+	}
+	if s.VectorHash(vector) != ks.VectorHash {
+		return ErrShareMismatch
+	}
+
+	// Horner's rule, in the exponent
+	x := big.NewInt(int64(ks.Index))
+	acc := new(G1)
+	for j := len(vector) - 1; j >= 0; j-- {
+		P1(acc).ScalarMultiplication(acc, x)
+		P1(acc).Add(acc, &vector[j].p)
+	}
+
+	expected := new(G1)
+	P1(expected).ScalarMultiplication(&s.G1Gen, &ks.Secret.x)
+	if !P1(acc).Equal(expected) {
+		return ErrInvalidKeyShare
+	}
+	return nil
+}
+
+// CheckKeyShares checks that shares belong to the same committee and epoch,
+// have distinct indices, and that there are enough of them to adjudicate
+func (s *Scheme[G1, G2, P1, P2]) CheckKeyShares(shares ...*KeyShare) error {
+	if len(shares) == 0 {
+		return ErrInvalidThreshold
+	}
+	first := shares[0]
+	if first.Suite != s.Suite {
+		return ErrSuiteMismatch
+	}
+	seen := make(map[int]bool, len(shares))
+	for _, ks := range shares {
+		if ks.Suite != first.Suite || ks.Threshold != first.Threshold ||
+			ks.CommitteeID != first.CommitteeID ||
+			ks.VectorHash != first.VectorHash || ks.Epoch != first.Epoch {
+			return ErrShareMismatch
+		}
+		if ks.Index < 1 || seen[ks.Index] {
+			return ErrInvalidThreshold
+		}
+		seen[ks.Index] = true
+	}
+	if len(shares) < first.Threshold {
+		return ErrInvalidThreshold
+	}
+	return nil
+}
+
+// MarshalBinary returns the versioned encoding of the key share
+func (ks *KeyShare) MarshalBinary() ([]byte, error) {
+	if ks.Secret == nil {
+		return nil, ErrInvalidKeyShare
+	}
+	b := make([]byte, 0, HeaderSize+keyShareSize)
+	b = Header{Version: CurrentVersion, Suite: ks.Suite, Kind: KindKeyShare}.append(b)
+	b = ks.appendMetadata(b)
+	return ks.Secret.AppendBinary(b), nil
+}
+
+// UnmarshalBinary decodes a versioned key share. The share is not checked
+// against the curve order, see VerifyKeyShare
+func (ks *KeyShare) UnmarshalBinary(b []byte) error {
+	b, err := checkHeader(b, KindKeyShare, keyShareSize)
+	if err != nil {
+		return err
+	}
+
+	res := KeyShare{Suite: Ciphersuite(b[1]), Secret: &SecretKey{}}
+	b = res.parseMetadata(b[HeaderSize:])
+	res.Secret.x.SetBytes(b)
+	if res.Index < 1 || res.Threshold < 1 || res.Secret.x.Sign() == 0 {
+		return ErrInvalidKeyShare
+	}
+
+	*ks = res
+	return nil
+}
+
+// checkHeader checks the header of a versioned object of the given kind and
+// size, header excluded
+func checkHeader(b []byte, kind Kind, size int) ([]byte, error) {
+	if len(b) != HeaderSize+size {
+		return nil, ErrInvalidLength
+	}
+	if b[0] != Version1 && b[0] != Version2 {
+		return nil, ErrUnsupportedVersion
+	}
+	if Kind(b[2]) != kind {
+		return nil, ErrKindMismatch
+	}
+	return b, nil
+}
+
+// appendMetadata appends everything but the suite and the secret
+func (ks *KeyShare) appendMetadata(b []byte) []byte {
+	n := len(b)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint32(b[n:], uint32(ks.Index))
+	binary.BigEndian.PutUint32(b[n+4:], uint32(ks.Threshold))
+	b = append(b, ks.CommitteeID[:]...)
+	b = append(b, ks.VectorHash[:]...)
+	n = len(b)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[n:], ks.Epoch)
+	return b
+}
+
+// parseMetadata decodes the fields encoded by appendMetadata and returns the
+// rest of b
+func (ks *KeyShare) parseMetadata(b []byte) []byte {
+	ks.Index = int(binary.BigEndian.Uint32(b))
+	ks.Threshold = int(binary.BigEndian.Uint32(b[4:]))
+	b = b[8:]
+	b = b[copy(ks.CommitteeID[:], b):]
+	b = b[copy(ks.VectorHash[:], b):]
+	ks.Epoch = binary.BigEndian.Uint64(b)
+	return b[8:]
+}
+
+type keyShareJSON struct {
+	Suite       Ciphersuite `json:"ciphersuite"`
+	Index       int         `json:"index"`
+	Threshold   int         `json:"threshold"`
+	CommitteeID string      `json:"committee_id"`
+	VectorHash  string      `json:"vector_hash"`
+	Epoch       uint64      `json:"epoch"`
+	Secret      string      `json:"secret"`
+}
+
+// MarshalJSON encodes the key share as JSON, with hex encoded byte fields
+func (ks *KeyShare) MarshalJSON() ([]byte, error) {
+	if ks.Secret == nil {
+		return nil, ErrInvalidKeyShare
+	}
+	return json.Marshal(keyShareJSON{
+		Suite:       ks.Suite,
+		Index:       ks.Index,
+		Threshold:   ks.Threshold,
+		CommitteeID: hex.EncodeToString(ks.CommitteeID[:]),
+		VectorHash:  hex.EncodeToString(ks.VectorHash[:]),
+		Epoch:       ks.Epoch,
+		Secret:      hex.EncodeToString(ks.Secret.Marshal()),
+	})
+}
+
+// UnmarshalJSON decodes a key share encoded by MarshalJSON
+func (ks *KeyShare) UnmarshalJSON(b []byte) error {
+	j := keyShareJSON{}
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	res := KeyShare{
+		Suite:     j.Suite,
+		Index:     j.Index,
+		Threshold: j.Threshold,
+		Epoch:     j.Epoch,
+		Secret:    &SecretKey{},
+	}
+	if err := decodeHex(res.CommitteeID[:], j.CommitteeID); err != nil {
+		return err
+	}
+	if err := decodeHex(res.VectorHash[:], j.VectorHash); err != nil {
+		return err
+	}
+	secret := [ScalarSize]byte{}
+	if err := decodeHex(secret[:], j.Secret); err != nil {
+		return err
+	}
+	res.Secret.x.SetBytes(secret[:])
+	if res.Index < 1 || res.Threshold < 1 || res.Secret.x.Sign() == 0 {
+		return ErrInvalidKeyShare
+	}
+
+	*ks = res
+	return nil
+}
+
+// decodeHex decodes s into dst, which it must fill exactly
+func decodeHex(dst []byte, s string) error {
+	if hex.DecodedLen(len(s)) != len(dst) {
+		return ErrInvalidLength
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// Equal reports whether ks and o are the same share of the same committee.
+// Secrets are compared in constant time
+func (ks *KeyShare) Equal(o *KeyShare) bool {
+	return ks.Suite == o.Suite && ks.Index == o.Index && ks.Threshold == o.Threshold &&
+		ks.CommitteeID == o.CommitteeID && ks.VectorHash == o.VectorHash &&
+		ks.Epoch == o.Epoch && equalSecrets(ks.Secret, o.Secret)
+}
+
+// Clone returns a copy of ks, secret included
+func (ks *KeyShare) Clone() *KeyShare {
+	c := *ks
+	c.Secret = cloneSecret(ks.Secret)
+	return &c
+}
+
+// String describes the share, never its secret
+func (ks KeyShare) String() string {
+	return fmt.Sprintf("KeyShare(%d of %d, committee %x, epoch %d)", ks.Index, ks.Threshold, ks.CommitteeID[:4], ks.Epoch)
+}
+
+// GoString is String, for %#v
+func (ks KeyShare) GoString() string {
+	return ks.String()
+}
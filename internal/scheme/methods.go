@@ -0,0 +1,129 @@
+package scheme
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// Equal reports whether sk and o are the same key, in constant time
+func (sk *SecretKey) Equal(o *SecretKey) bool {
+	a := [ScalarSize]byte{}
+	b := [ScalarSize]byte{}
+	sk.x.FillBytes(a[:])
+	o.x.FillBytes(b[:])
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// Clone returns a copy of sk
+func (sk *SecretKey) Clone() *SecretKey {
+	c := SecretKey{}
+	c.x.Set(&sk.x)
+	return &c
+}
+
+// String never prints secret material. It has a value receiver, so that
+// formatting a SecretKey value does not print its fields either
+func (sk SecretKey) String() string {
+	return "SecretKey(redacted)"
+}
+
+// GoString never prints secret material, for %#v
+func (sk SecretKey) GoString() string {
+	return sk.String()
+}
+
+// equalSecrets is SecretKey.Equal, allowing either key to be nil
+func equalSecrets(a, b *SecretKey) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b)
+}
+
+// cloneSecret is SecretKey.Clone, allowing sk to be nil
+func cloneSecret(sk *SecretKey) *SecretKey {
+	if sk == nil {
+		return nil
+	}
+	return sk.Clone()
+}
+
+// Equal reports whether pk and o are the same key
+func (pk *PublicKey[G1, P1]) Equal(o *PublicKey[G1, P1]) bool {
+	return P1(&pk.p).Equal(&o.p)
+}
+
+// Clone returns a copy of pk
+func (pk *PublicKey[G1, P1]) Clone() *PublicKey[G1, P1] {
+	c := *pk
+	return &c
+}
+
+// String returns the hex encoding of pk
+func (pk *PublicKey[G1, P1]) String() string {
+	return hex.EncodeToString(pk.Marshal())
+}
+
+// Equal reports whether apk and o are the same key
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) Equal(o *AdjudicatorPublicKey[G1, G2, P1, P2]) bool {
+	return P1(&apk.g1).Equal(&o.g1) && P2(&apk.g2).Equal(&o.g2)
+}
+
+// Clone returns a copy of apk
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) Clone() *AdjudicatorPublicKey[G1, G2, P1, P2] {
+	c := *apk
+	return &c
+}
+
+// String returns the hex encoding of apk
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) String() string {
+	return hex.EncodeToString(apk.Marshal())
+}
+
+// Equal reports whether s and o are the same signature
+func (s *Signature[G2, P2]) Equal(o *Signature[G2, P2]) bool {
+	return P2(&s.p).Equal(&o.p)
+}
+
+// Clone returns a copy of s
+func (s *Signature[G2, P2]) Clone() *Signature[G2, P2] {
+	c := *s
+	return &c
+}
+
+// String returns the hex encoding of s
+func (s *Signature[G2, P2]) String() string {
+	return hex.EncodeToString(s.Marshal())
+}
+
+// Equal reports whether sig and o are the same signature
+func (sig *VESig[G2, P2]) Equal(o *VESig[G2, P2]) bool {
+	return P2(&sig.omega).Equal(&o.omega) && P2(&sig.mu).Equal(&o.mu)
+}
+
+// Clone returns a copy of sig
+func (sig *VESig[G2, P2]) Clone() *VESig[G2, P2] {
+	c := *sig
+	return &c
+}
+
+// String returns the hex encoding of sig
+func (sig *VESig[G2, P2]) String() string {
+	return hex.EncodeToString(sig.Marshal())
+}
+
+// Equal reports whether pa and o are the same partial adjudication
+func (pa *Partial[G2, P2]) Equal(o *Partial[G2, P2]) bool {
+	return P2(&pa.p).Equal(&o.p)
+}
+
+// Clone returns a copy of pa
+func (pa *Partial[G2, P2]) Clone() *Partial[G2, P2] {
+	c := *pa
+	return &c
+}
+
+// String returns the hex encoding of pa
+func (pa *Partial[G2, P2]) String() string {
+	return hex.EncodeToString(pa.Marshal())
+}
@@ -0,0 +1,100 @@
+package scheme
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// leaks reports whether s contains sk in any form fmt could print it in
+func leaks(s string, sk *SecretKey) bool {
+	forms := []string{sk.x.String(), sk.x.Text(16), hex.EncodeToString(sk.Marshal())}
+	for _, w := range sk.x.Bits() {
+		forms = append(forms, strconv.FormatUint(uint64(w), 10), strconv.FormatUint(uint64(w), 16))
+	}
+	for _, f := range forms {
+		if strings.Contains(s, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFormatRedactsSecrets(t *testing.T) {
+	s := newTestScheme()
+	sk := generateKey(t)
+	shares, _, err := s.SplitKeyShares(sk, 2, 3, [32]byte{1}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backups, err := s.BackupKeyShare(shares[0], 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]interface{}{
+		"sk": *sk, "&sk": sk,
+		"ks": *shares[0], "&ks": shares[0],
+		"bs": *backups[0], "&bs": backups[0],
+		"[]*ks": shares,
+	}
+	secrets := []*SecretKey{sk, shares[0].Secret, backups[0].Secret}
+	for name, v := range values {
+		for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+			out := fmt.Sprintf(verb, v)
+			for _, secret := range secrets {
+				if leaks(out, secret) {
+					t.Errorf("%s %s leaks a secret: %s", verb, name, out)
+				}
+			}
+		}
+	}
+}
+
+func TestShareEqualClone(t *testing.T) {
+	s := newTestScheme()
+	shares, vector, err := s.SplitKeyShares(generateKey(t), 2, 3, [32]byte{1}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := shares[0].Clone()
+	if !ks.Equal(shares[0]) || ks.Equal(shares[1]) {
+		t.Fatal("KeyShare.Equal")
+	}
+	ks.Secret.x.SetInt64(1)
+	if ks.Equal(shares[0]) {
+		t.Fatal("KeyShare.Clone shares the secret")
+	}
+
+	backups, err := s.BackupKeyShare(shares[0], 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs := backups[0].Clone()
+	if !bs.Equal(backups[0]) || bs.Equal(backups[1]) {
+		t.Fatal("BackupShare.Equal")
+	}
+	bs.Share.Epoch++
+	if bs.Equal(backups[0]) {
+		t.Fatal("BackupShare.Clone shares the key share")
+	}
+
+	c, err := s.NewCommittee([32]byte{1}, 1, []string{"a", "b", "c"}, vector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc := c.Clone()
+	if !cc.Equal(c) {
+		t.Fatal("Committee.Equal")
+	}
+	cc.Members[0].ID = "d"
+	cc.Vector[0] = cc.Vector[1]
+	if cc.Equal(c) || c.Members[0].ID != "a" || !c.Vector[0].Equal(vector[0]) {
+		t.Fatal("Committee.Clone shares members or keys")
+	}
+	if got := c.String(); !strings.HasPrefix(got, "Committee(01000000, epoch 1, 2 of 3") {
+		t.Fatalf("got %s", got)
+	}
+}
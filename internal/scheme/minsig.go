@@ -0,0 +1,52 @@
+package scheme
+
+// MinSigPublicKey is a public key on G2, as used by the minimal-signature-size
+// convention, which signs in G1. This module signs in G2: these keys are only
+// meant for operators migrating key material to or from min-sig deployments
+type MinSigPublicKey[G2 any, P2 Point[G2]] struct {
+	p G2
+}
+
+// Marshal returns the uncompressed encoding of the public key
+func (pk *MinSigPublicKey[G2, P2]) Marshal() []byte {
+	return P2(&pk.p).Marshal()
+}
+
+// Unmarshal decodes a public key. The point must be in G2
+func (pk *MinSigPublicKey[G2, P2]) Unmarshal(b []byte) error {
+	return unmarshalPoint[G2, P2](&pk.p, b)
+}
+
+// Point returns the public key point
+func (pk *MinSigPublicKey[G2, P2]) Point() G2 {
+	return pk.p
+}
+
+// Equal reports whether pk and o are the same key
+func (pk *MinSigPublicKey[G2, P2]) Equal(o *MinSigPublicKey[G2, P2]) bool {
+	return P2(&pk.p).Equal(&o.p)
+}
+
+// PublicKeyG1 returns the min-pk public key for sk. It is PublicKey
+func (s *Scheme[G1, G2, P1, P2]) PublicKeyG1(sk *SecretKey) *PublicKey[G1, P1] {
+	return s.PublicKey(sk)
+}
+
+// PublicKeyG2 returns the min-sig public key for sk
+func (s *Scheme[G1, G2, P1, P2]) PublicKeyG2(sk *SecretKey) *MinSigPublicKey[G2, P2] {
+	pk := MinSigPublicKey[G2, P2]{}
+	P2(&pk.p).ScalarMultiplication(&s.G2Gen, &sk.x)
+	return &pk
+}
+
+// SameKey reports whether pk and pk2 derive from the same secret, that is
+// e(pk, g2) == e(g1, pk2), so that a migrated key can be checked without its
+// secret
+func (s *Scheme[G1, G2, P1, P2]) SameKey(pk *PublicKey[G1, P1], pk2 *MinSigPublicKey[G2, P2]) (bool, error) {
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	return s.PairingCheck(
+		[]G1{pk.p, *ng1},
+		[]G2{s.G2Gen, pk2.p},
+	)
+}
@@ -0,0 +1,95 @@
+package scheme
+
+import "crypto/rand"
+
+// NestedVESig is a signature escrowed to two adjudicators in turn, as in an
+// appeal: the first instance can only peel its own layer, yielding a VESig
+// to the appellate adjudicator, and neither can open it alone
+//
+//	omega = sigma + r1.v1' + r2.v2', mu1 = r1.g2, mu2 = r2.g2
+type NestedVESig[G2 any, P2 Point[G2]] struct {
+	omega G2
+	mu1   G2
+	mu2   G2
+}
+
+// SignNested escrows a signature on msg to first, then appellate
+func (s *Scheme[G1, G2, P1, P2]) SignNested(sk *SecretKey, first, appellate *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte) (*NestedVESig[G2, P2], error) {
+	// The appellate layer is a regular VESig, wrapped in the first instance
+	// layer
+	inner, err := s.Sign(sk, appellate, msg)
+	if err != nil {
+		return nil, err
+	}
+	r, err := rand.Int(rand.Reader, s.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := NestedVESig[G2, P2]{mu2: inner.mu}
+	P2(&sig.mu1).ScalarMultiplication(&s.G2Gen, r)
+	P2(&sig.omega).ScalarMultiplication(&first.g2, r)
+	P2(&sig.omega).Add(&sig.omega, &inner.omega)
+	return &sig, nil
+}
+
+// VerifyNested checks e(g1, omega)^-1 . e(pk, h) . e(v1, mu1) . e(v2, mu2) == 1
+func (s *Scheme[G1, G2, P1, P2]) VerifyNested(pk *PublicKey[G1, P1], first, appellate *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, sig *NestedVESig[G2, P2]) (bool, error) {
+	return s.verifyNested(pk, &first.g1, &appellate.g1, msg, sig)
+}
+
+func (s *Scheme[G1, G2, P1, P2]) verifyNested(pk *PublicKey[G1, P1], firstG1, appellateG1 *G1, msg []byte, sig *NestedVESig[G2, P2]) (bool, error) {
+	h, err := s.Hash(msg)
+	if err != nil {
+		return false, err
+	}
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	return s.PairingCheck(
+		[]G1{*ng1, pk.p, *firstG1, *appellateG1},
+		[]G2{sig.omega, h, sig.mu1, sig.mu2},
+	)
+}
+
+// AdjudicateFirst is the first instance adjudication of sig: it is verified,
+// and the first layer removed. The result is a VESig to appellate, which
+// the appellate adjudicator opens with VerifyAndAdjudicate. The first
+// instance never sees the plain signature
+func (s *Scheme[G1, G2, P1, P2]) AdjudicateFirst(firstSK *SecretKey, pk *PublicKey[G1, P1], appellate *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, sig *NestedVESig[G2, P2]) (*VESig[G2, P2], error) {
+	firstG1 := new(G1)
+	P1(firstG1).ScalarMultiplication(&s.G1Gen, &firstSK.x)
+	ok, err := s.verifyNested(pk, firstG1, &appellate.g1, msg, sig)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	res := VESig[G2, P2]{mu: sig.mu2}
+	P2(&res.omega).ScalarMultiplication(&sig.mu1, &firstSK.x)
+	P2(&res.omega).Sub(&sig.omega, &res.omega)
+	return &res, nil
+}
+
+// Marshal returns the uncompressed encodings of omega, mu1 and mu2
+func (sig *NestedVESig[G2, P2]) Marshal() []byte {
+	b := P2(&sig.omega).Marshal()
+	b = append(b, P2(&sig.mu1).Marshal()...)
+	return append(b, P2(&sig.mu2).Marshal()...)
+}
+
+// Unmarshal decodes a nested verifiably encrypted signature
+func (sig *NestedVESig[G2, P2]) Unmarshal(b []byte) error {
+	n := len(P2(new(G2)).Marshal())
+	if len(b) != 3*n {
+		return ErrInvalidLength
+	}
+	if err := unmarshalPoint[G2, P2](&sig.omega, b[:n]); err != nil {
+		return err
+	}
+	if err := unmarshalPoint[G2, P2](&sig.mu1, b[n:2*n]); err != nil {
+		return err
+	}
+	return unmarshalPoint[G2, P2](&sig.mu2, b[2*n:])
+}
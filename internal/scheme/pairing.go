@@ -0,0 +1,108 @@
+package scheme
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrInvalidParallelism = errors.New("invalid parallelism")
+
+// minPairsPerLoop is the smallest number of pairs a parallel pairing check
+// gives a goroutine. Below it, the goroutine costs more than it saves
+const minPairsPerLoop = 8
+
+// Target is a pairing target group element, such as gnark's GT
+type Target[T any] interface {
+	*T
+	SetOne() *T
+	Equal(*T) bool
+}
+
+// Parallelism is the number of goroutines running the Miller loops of large
+// pairing checks. The curve packages' WithParallelism options set it. The
+// zero value means 1
+type Parallelism int
+
+// Set sets the parallelism to n, which must be positive
+func (p *Parallelism) Set(n int) error {
+	if n < 1 {
+		return ErrInvalidParallelism
+	}
+	*p = Parallelism(n)
+	return nil
+}
+
+// ParallelPairingCheck returns a pairing check that splits the Miller loops
+// of large checks across up to n goroutines, and merges their results in a
+// single final exponentiation. This lets one large aggregate verification
+// use every core. Loops not started when ctx is done are skipped, and the
+// check returns ctx.Err(). With n below 2 it returns nil, and the scheme
+// runs PairingCheck instead
+func ParallelPairingCheck[G1, G2, T any, PT Target[T]](n Parallelism, millerLoop func([]G1, []G2) (T, error), finalExp func(*T, ...*T) T) func(context.Context, []G1, []G2) (bool, error) {
+	if n < 2 {
+		return nil
+	}
+	return func(ctx context.Context, p []G1, q []G2) (bool, error) {
+		if len(p) == 0 || len(p) != len(q) {
+			return false, ErrInvalidLength
+		}
+		loops := len(p) / minPairsPerLoop
+		if loops > int(n) {
+			loops = int(n)
+		}
+		if loops < 1 {
+			loops = 1
+		}
+		size := (len(p) + loops - 1) / loops
+		loops = (len(p) + size - 1) / size
+
+		f := make([]T, loops)
+		errs := make([]error, loops)
+		wg := sync.WaitGroup{}
+		for i := range f {
+			lo, hi := i*size, (i+1)*size
+			if hi > len(p) {
+				hi = len(p)
+			}
+			wg.Add(1)
+			go func(i, lo, hi int) {
+				defer wg.Done()
+				if errs[i] = ctx.Err(); errs[i] != nil {
+					return
+				}
+				f[i], errs[i] = millerLoop(p[lo:hi], q[lo:hi])
+			}(i, lo, hi)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return false, err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		rest := make([]*T, 0, loops-1)
+		for i := 1; i < loops; i++ {
+			rest = append(rest, &f[i])
+		}
+		res := finalExp(&f[0], rest...)
+		one := new(T)
+		PT(one).SetOne()
+		return PT(&res).Equal(one), nil
+	}
+}
+
+// pairingCheck runs ParallelPairingCheck if the curve has one, and
+// PairingCheck otherwise
+func (s *Scheme[G1, G2, P1, P2]) pairingCheck(ctx context.Context, p []G1, q []G2) (bool, error) {
+	if s.ParallelPairingCheck != nil {
+		return s.ParallelPairingCheck(ctx, p, q)
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return s.PairingCheck(p, q)
+}
@@ -0,0 +1,65 @@
+package scheme
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// pairs returns n pairs whose product of pairings is 1 when n is even:
+// e(a, g2) . e(-a, g2) ...
+func pairs(n int) ([]testG1, []testG2) {
+	_, _, g1, g2 := bn254.Generators()
+	p, q := make([]testG1, n), make([]testG2, n)
+	for i := range p {
+		p[i].ScalarMultiplication(&g1, big.NewInt(int64(i/2+1)))
+		if i%2 == 1 {
+			p[i].Neg(&p[i])
+		}
+		q[i] = g2
+	}
+	return p, q
+}
+
+func TestParallelPairingCheck(t *testing.T) {
+	if ParallelPairingCheck[testG1, testG2, bn254.GT](1, bn254.MillerLoop, bn254.FinalExponentiation) != nil {
+		t.Fatal("parallel check for one goroutine")
+	}
+	check := ParallelPairingCheck[testG1, testG2, bn254.GT](4, bn254.MillerLoop, bn254.FinalExponentiation)
+	ctx := context.Background()
+	for _, n := range []int{1, 2, 7, 8, 9, 16, 33, 40} {
+		p, q := pairs(n)
+		want, err := bn254.PairingCheck(p, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := check(ctx, p, q); err != nil || got != want {
+			t.Errorf("%d pairs: got %t, %v, want %t", n, got, err, want)
+		}
+	}
+	if _, err := check(ctx, nil, nil); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidLength)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	p, q := pairs(40)
+	if _, err := check(cancelled, p, q); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestParallelism(t *testing.T) {
+	var n Parallelism
+	for _, bad := range []int{0, -1} {
+		if err := n.Set(bad); !errors.Is(err, ErrInvalidParallelism) {
+			t.Errorf("%d: got %v, want %v", bad, err, ErrInvalidParallelism)
+		}
+	}
+	if err := n.Set(3); err != nil || n != 3 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+}
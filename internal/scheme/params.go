@@ -0,0 +1,21 @@
+package scheme
+
+import "math/big"
+
+// Params describes a curve instance, so that serializers and schemas do not
+// hardcode sizes. Sizes are in bytes
+type Params struct {
+	Curve string
+	// Order of G1 and G2
+	Order *big.Int
+	// Modulus of the base field
+	FieldModulus *big.Int
+
+	G1Compressed   int
+	G1Uncompressed int
+	G2Compressed   int
+	G2Uncompressed int
+	ScalarSize     int
+
+	DST string
+}
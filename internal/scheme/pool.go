@@ -0,0 +1,43 @@
+package scheme
+
+// maxPooledPairs bounds the inputs kept in the pool, so one huge batch does
+// not pin its buffers for the lifetime of the process
+const maxPooledPairs = 1 << 16
+
+// pairingInput holds the points of a multi-pairing check and the messages
+// seen so far. Batch and aggregate verification need one per call, with a
+// pair per message, so they are pooled
+type pairingInput[G1, G2 any] struct {
+	p    []G1
+	q    []G2
+	seen map[string]bool
+}
+
+// getPairingInput returns an empty pairing input from the pool
+func (s *Scheme[G1, G2, P1, P2]) getPairingInput() *pairingInput[G1, G2] {
+	if in, ok := s.pairings.Get().(*pairingInput[G1, G2]); ok {
+		return in
+	}
+	return &pairingInput[G1, G2]{seen: map[string]bool{}}
+}
+
+// putPairingInput resets in and returns it to the pool
+func (s *Scheme[G1, G2, P1, P2]) putPairingInput(in *pairingInput[G1, G2]) {
+	if cap(in.p) > maxPooledPairs || len(in.seen) > maxPooledPairs {
+		return
+	}
+	in.p, in.q = in.p[:0], in.q[:0]
+	for m := range in.seen {
+		delete(in.seen, m)
+	}
+	s.pairings.Put(in)
+}
+
+// distinct records m, and reports whether it was not seen before
+func (in *pairingInput[G1, G2]) distinct(m []byte) bool {
+	if in.seen[string(m)] {
+		return false
+	}
+	in.seen[string(m)] = true
+	return true
+}
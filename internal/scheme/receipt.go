@@ -0,0 +1,122 @@
+package scheme
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidReceipt = errors.New("invalid escrow receipt")
+
+const receiptTag = "VESS-ESCROW-RECEIPT-V1"
+
+// Receipt is a signer's statement that it placed a signature on a message in
+// escrow with an adjudicator. It is signed with a Schnorr signature in G1, so
+// it reveals nothing about the escrowed BLS signature
+type Receipt[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	Signer      *PublicKey[G1, P1]
+	Adjudicator *AdjudicatorPublicKey[G1, G2, P1, P2]
+	// SHA-256 of the message and of the uncompressed VESig encoding
+	MessageHash [32]byte
+	VESigDigest [32]byte
+	// Unix time
+	Timestamp uint64
+
+	sig schnorrSig[G1, P1]
+}
+
+// NewReceipt returns a receipt for the escrow of sig on msg, signed with the
+// signer secret key
+func (s *Scheme[G1, G2, P1, P2]) NewReceipt(sk *SecretKey, adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, sig *VESig[G2, P2], timestamp uint64) (*Receipt[G1, G2, P1, P2], error) {
+	r := Receipt[G1, G2, P1, P2]{
+		Signer:      s.PublicKey(sk),
+		Adjudicator: adj,
+		MessageHash: sha256.Sum256(msg),
+		VESigDigest: sha256.Sum256(s.AppendVESig(nil, sig)),
+		Timestamp:   timestamp,
+	}
+	rs, err := s.schnorrSign(receiptTag, sk, s.receiptMessage(&r))
+	if err != nil {
+		return nil, err
+	}
+	r.sig = *rs
+	return &r, nil
+}
+
+// receiptMessage is the signed part of the receipt
+func (s *Scheme[G1, G2, P1, P2]) receiptMessage(r *Receipt[G1, G2, P1, P2]) []byte {
+	b := []byte{byte(s.Suite)}
+	b = s.AppendPublicKey(b, r.Signer)
+	b = s.AppendAdjudicatorPublicKey(b, r.Adjudicator)
+	b = append(b, r.MessageHash[:]...)
+	b = append(b, r.VESigDigest[:]...)
+	n := len(b)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[n:], r.Timestamp)
+	return b
+}
+
+// VerifyReceipt checks the signature of the receipt
+func (s *Scheme[G1, G2, P1, P2]) VerifyReceipt(r *Receipt[G1, G2, P1, P2]) error {
+	if r.Signer == nil || r.Adjudicator == nil ||
+		!inSubGroup[G1, P1](&r.Signer.p) {
+		return ErrInvalidReceipt
+	}
+	if !s.schnorrVerify(receiptTag, &r.Signer.p, s.receiptMessage(r), &r.sig) {
+		return ErrInvalidReceipt
+	}
+	return nil
+}
+
+// ReceiptMatches reports whether r is a receipt for the escrow of sig on msg
+// with adj. The receipt signature is checked separately, by VerifyReceipt
+func (s *Scheme[G1, G2, P1, P2]) ReceiptMatches(r *Receipt[G1, G2, P1, P2], adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, sig *VESig[G2, P2]) bool {
+	return r.Adjudicator.Equal(adj) &&
+		r.MessageHash == sha256.Sum256(msg) &&
+		r.VESigDigest == sha256.Sum256(s.AppendVESig(nil, sig))
+}
+
+// MarshalReceipt encodes a receipt
+func (s *Scheme[G1, G2, P1, P2]) MarshalReceipt(r *Receipt[G1, G2, P1, P2]) []byte {
+	return s.appendSchnorr(s.receiptMessage(r), &r.sig)
+}
+
+// UnmarshalReceipt decodes and verifies a receipt
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalReceipt(b []byte) (*Receipt[G1, G2, P1, P2], error) {
+	n1 := len(P1(new(G1)).Marshal())
+	n2 := len(P2(new(G2)).Marshal())
+	if len(b) < 1+2*n1+n2+32+32+8 {
+		return nil, ErrInvalidLength
+	}
+	if Ciphersuite(b[0]) != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	b = b[1:]
+
+	r := Receipt[G1, G2, P1, P2]{
+		Signer:      &PublicKey[G1, P1]{},
+		Adjudicator: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+	}
+	if err := r.Signer.Unmarshal(b[:n1]); err != nil {
+		return nil, err
+	}
+	if err := r.Adjudicator.Unmarshal(b[n1 : 2*n1+n2]); err != nil {
+		return nil, err
+	}
+	b = b[2*n1+n2:]
+	b = b[copy(r.MessageHash[:], b):]
+	b = b[copy(r.VESigDigest[:], b):]
+	r.Timestamp = binary.BigEndian.Uint64(b)
+	b, err := s.parseSchnorr(&r.sig, b[8:])
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, ErrInvalidLength
+	}
+
+	if err := s.VerifyReceipt(&r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
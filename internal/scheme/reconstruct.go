@@ -0,0 +1,58 @@
+package scheme
+
+import (
+	"errors"
+	"math/big"
+)
+
+var ErrReconstruction = errors.New("reconstructed key does not match the committee")
+
+// ReconstructKey recovers the adjudicator secret key from c.Threshold shares
+// of the committee, for disaster recovery. Each share is checked against the
+// verification vector, and the result against the adjudicator public key
+func (s *Scheme[G1, G2, P1, P2]) ReconstructKey(c *Committee[G1, P1], shares ...*KeyShare) (*SecretKey, error) {
+	if err := s.CheckCommittee(c); err != nil {
+		return nil, err
+	}
+	if len(shares) < c.Threshold {
+		return nil, ErrInvalidThreshold
+	}
+	shares = shares[:c.Threshold]
+
+	indices := make([]int, len(shares))
+	for i, ks := range shares {
+		if err := s.CheckKeyShare(c, ks); err != nil {
+			return nil, err
+		}
+		indices[i] = ks.Index
+	}
+	lambdas, err := s.lagrange(indices)
+	if err != nil {
+		return nil, err
+	}
+
+	sk := SecretKey{}
+	term := new(big.Int)
+	for i, ks := range shares {
+		term.Mul(&ks.Secret.x, lambdas[i])
+		sk.x.Add(&sk.x, term)
+		sk.x.Mod(&sk.x, s.Order)
+	}
+
+	pk := s.PublicKey(&sk)
+	if !pk.Equal(c.AdjudicatorKey()) {
+		return nil, ErrReconstruction
+	}
+	return &sk, nil
+}
+
+// CheckReconstruction is ReconstructKey, without returning the key. It proves
+// to auditors that the shares are enough to recover the adjudicator key
+func (s *Scheme[G1, G2, P1, P2]) CheckReconstruction(c *Committee[G1, P1], shares ...*KeyShare) error {
+	sk, err := s.ReconstructKey(c, shares...)
+	if err != nil {
+		return err
+	}
+	sk.x.SetInt64(0)
+	return nil
+}
@@ -0,0 +1,142 @@
+package scheme
+
+import "crypto/rand"
+
+// ReEscrow transforms sig, encrypted to the adjudicator owning oldSK, into a
+// VESig on the same signature encrypted to newAdj:
+//
+//	omega' = omega + (r'.v'_new - x'_old.mu), mu' = r'.g2
+//
+// The plain signature is never computed. sig is verified first: without that
+// check, the old adjudicator would compute x'_old.mu for an arbitrary mu
+func (s *Scheme[G1, G2, P1, P2]) ReEscrow(oldSK *SecretKey, pk *PublicKey[G1, P1], msg []byte, sig *VESig[G2, P2], newAdj *AdjudicatorPublicKey[G1, G2, P1, P2]) (*VESig[G2, P2], error) {
+	oldG1 := new(G1)
+	P1(oldG1).ScalarMultiplication(&s.G1Gen, &oldSK.x)
+	ok, err := s.verify(pk, oldG1, msg, sig)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	mux := new(G2)
+	P2(mux).ScalarMultiplication(&sig.mu, &oldSK.x)
+	return s.reEscrow(sig, mux, newAdj)
+}
+
+// ReEscrowCombine is ReEscrow for a committee, from t partial adjudications
+// of sig. As with Combine, whoever combines the partials could also open sig:
+// it is up to the caller to verify sig and the partials first
+func (s *Scheme[G1, G2, P1, P2]) ReEscrowCombine(sig *VESig[G2, P2], indices []int, partials []*Partial[G2, P2], newAdj *AdjudicatorPublicKey[G1, G2, P1, P2]) (*VESig[G2, P2], error) {
+	mux, err := s.combine(indices, partials)
+	if err != nil {
+		return nil, err
+	}
+	return s.reEscrow(sig, mux, newAdj)
+}
+
+func (s *Scheme[G1, G2, P1, P2]) reEscrow(sig *VESig[G2, P2], mux *G2, newAdj *AdjudicatorPublicKey[G1, G2, P1, P2]) (*VESig[G2, P2], error) {
+	r, err := rand.Int(rand.Reader, s.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	res := VESig[G2, P2]{}
+	P2(&res.mu).ScalarMultiplication(&s.G2Gen, r)
+
+	// delta = r'.v'_new - x'_old.mu
+	delta := new(G2)
+	P2(delta).ScalarMultiplication(&newAdj.g2, r)
+	P2(delta).Sub(delta, mux)
+	P2(&res.omega).Add(&sig.omega, delta)
+	return &res, nil
+}
+
+// ReEscrowProof states that New, encrypted to NewAdjudicator, hides the same
+// signature as Old, encrypted to OldAdjudicator. The statement is checked
+// with pairings alone: no secret, message or signer key is needed. Since BLS
+// signatures are unique, the same holds for the signature on any message
+type ReEscrowProof[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	OldAdjudicator *AdjudicatorPublicKey[G1, G2, P1, P2]
+	Old            *VESig[G2, P2]
+	NewAdjudicator *AdjudicatorPublicKey[G1, G2, P1, P2]
+	New            *VESig[G2, P2]
+}
+
+// VerifyReEscrowProof checks omega - x'_old.mu == omega' - x'_new.mu', that
+// is e(g1, omega - omega')^-1 . e(v_old, mu) . e(v_new, mu')^-1 == 1
+func (s *Scheme[G1, G2, P1, P2]) VerifyReEscrowProof(p *ReEscrowProof[G1, G2, P1, P2]) (bool, error) {
+	if !inSubGroup[G1, P1](&p.OldAdjudicator.g1) ||
+		!inSubGroup[G1, P1](&p.NewAdjudicator.g1) ||
+		!inSubGroup[G2, P2](&p.Old.omega) || !inSubGroup[G2, P2](&p.Old.mu) ||
+		!inSubGroup[G2, P2](&p.New.omega) || !inSubGroup[G2, P2](&p.New.mu) {
+		return false, ErrInvalidPoint
+	}
+
+	diff := new(G2)
+	P2(diff).Sub(&p.Old.omega, &p.New.omega)
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	nv := new(G1)
+	P1(nv).Neg(&p.NewAdjudicator.g1)
+	return s.PairingCheck(
+		[]G1{*ng1, p.OldAdjudicator.g1, *nv},
+		[]G2{*diff, p.Old.mu, p.New.mu},
+	)
+}
+
+// VerifyReEscrowFor is VerifyReEscrowProof for the original signer, or
+// anyone knowing msg: it also checks that the new VESig is a valid escrow of
+// a signature on msg under pk
+func (s *Scheme[G1, G2, P1, P2]) VerifyReEscrowFor(pk *PublicKey[G1, P1], msg []byte, p *ReEscrowProof[G1, G2, P1, P2]) (bool, error) {
+	ok, err := s.VerifyReEscrowProof(p)
+	if err != nil || !ok {
+		return false, err
+	}
+	return s.Verify(pk, p.NewAdjudicator, msg, p.New)
+}
+
+// MarshalReEscrowProof encodes the proof as both adjudicator keys and VESigs
+func (s *Scheme[G1, G2, P1, P2]) MarshalReEscrowProof(p *ReEscrowProof[G1, G2, P1, P2]) []byte {
+	b := []byte{byte(s.Suite)}
+	b = s.AppendAdjudicatorPublicKey(b, p.OldAdjudicator)
+	b = s.AppendVESig(b, p.Old)
+	b = s.AppendAdjudicatorPublicKey(b, p.NewAdjudicator)
+	return s.AppendVESig(b, p.New)
+}
+
+// UnmarshalReEscrowProof decodes a proof encoded by MarshalReEscrowProof. It
+// does not verify it
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalReEscrowProof(b []byte) (*ReEscrowProof[G1, G2, P1, P2], error) {
+	na := len(P1(new(G1)).Marshal()) + len(P2(new(G2)).Marshal())
+	ns := 2 * len(P2(new(G2)).Marshal())
+	if len(b) != 1+2*(na+ns) {
+		return nil, ErrInvalidLength
+	}
+	if Ciphersuite(b[0]) != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	b = b[1:]
+
+	p := ReEscrowProof[G1, G2, P1, P2]{
+		OldAdjudicator: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+		Old:            &VESig[G2, P2]{},
+		NewAdjudicator: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+		New:            &VESig[G2, P2]{},
+	}
+	if err := p.OldAdjudicator.Unmarshal(b[:na]); err != nil {
+		return nil, err
+	}
+	if err := p.Old.Unmarshal(b[na : na+ns]); err != nil {
+		return nil, err
+	}
+	b = b[na+ns:]
+	if err := p.NewAdjudicator.Unmarshal(b[:na]); err != nil {
+		return nil, err
+	}
+	if err := p.New.Unmarshal(b[na:]); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
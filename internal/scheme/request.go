@@ -0,0 +1,245 @@
+package scheme
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	ErrInvalidRequest   = errors.New("invalid adjudication request")
+	ErrUnknownRequester = errors.New("requester not registered")
+)
+
+const (
+	requestTag           = "VESS-ADJUDICATION-REQUEST-V1"
+	adjudicationTag      = "VESS-ADJUDICATION-RECEIPT-V1"
+	maxRequestReasonSize = 0xffff
+)
+
+// AdjudicationRequest is a requester's signed demand that an escrow be
+// opened. Adjudicators verify it before adjudicating and keep it, so the
+// audit trail proves who asked for each escrow to be opened
+type AdjudicationRequest[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	Requester   *PublicKey[G1, P1]
+	Adjudicator *AdjudicatorPublicKey[G1, G2, P1, P2]
+	// SHA-256 of the uncompressed VESig encoding
+	VESigDigest [32]byte
+	// Reason references the evidence supporting the request
+	Reason string
+	// Unix time
+	Timestamp uint64
+
+	sig schnorrSig[G1, P1]
+}
+
+// AdjudicationReceipt is the adjudicator's statement that it opened an
+// escrow at a request, signed with Schnorr by the adjudicator key
+type AdjudicationReceipt[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	Adjudicator   *AdjudicatorPublicKey[G1, G2, P1, P2]
+	VESigDigest   [32]byte
+	RequestDigest [32]byte
+	Timestamp     uint64
+
+	sig schnorrSig[G1, P1]
+}
+
+// NewAdjudicationRequest returns a request to open sig, signed with the
+// requester secret key
+func (s *Scheme[G1, G2, P1, P2]) NewAdjudicationRequest(sk *SecretKey, adj *AdjudicatorPublicKey[G1, G2, P1, P2], sig *VESig[G2, P2], reason string, timestamp uint64) (*AdjudicationRequest[G1, G2, P1, P2], error) {
+	if len(reason) > maxRequestReasonSize {
+		return nil, ErrInvalidRequest
+	}
+	req := AdjudicationRequest[G1, G2, P1, P2]{
+		Requester:   s.PublicKey(sk),
+		Adjudicator: adj,
+		VESigDigest: sha256.Sum256(s.AppendVESig(nil, sig)),
+		Reason:      reason,
+		Timestamp:   timestamp,
+	}
+	rs, err := s.schnorrSign(requestTag, sk, s.requestMessage(&req))
+	if err != nil {
+		return nil, err
+	}
+	req.sig = *rs
+	return &req, nil
+}
+
+// requestMessage is the signed part of the request
+func (s *Scheme[G1, G2, P1, P2]) requestMessage(req *AdjudicationRequest[G1, G2, P1, P2]) []byte {
+	b := []byte{byte(s.Suite)}
+	b = s.AppendPublicKey(b, req.Requester)
+	b = s.AppendAdjudicatorPublicKey(b, req.Adjudicator)
+	b = append(b, req.VESigDigest[:]...)
+	n := len(b)
+	b = append(b, make([]byte, 8+2)...)
+	binary.BigEndian.PutUint64(b[n:], req.Timestamp)
+	binary.BigEndian.PutUint16(b[n+8:], uint16(len(req.Reason)))
+	return append(b, req.Reason...)
+}
+
+// VerifyAdjudicationRequest checks that req is a valid request to open sig
+// with adj. If registered is not nil, the requester must be one of its keys
+func (s *Scheme[G1, G2, P1, P2]) VerifyAdjudicationRequest(req *AdjudicationRequest[G1, G2, P1, P2], adj *AdjudicatorPublicKey[G1, G2, P1, P2], sig *VESig[G2, P2], registered []*PublicKey[G1, P1]) error {
+	if req.Requester == nil || req.Adjudicator == nil ||
+		len(req.Reason) > maxRequestReasonSize ||
+		!inSubGroup[G1, P1](&req.Requester.p) {
+		return ErrInvalidRequest
+	}
+	if !req.Adjudicator.Equal(adj) ||
+		req.VESigDigest != sha256.Sum256(s.AppendVESig(nil, sig)) {
+		return ErrInvalidRequest
+	}
+	if registered != nil {
+		found := false
+		for _, pk := range registered {
+			found = found || pk.Equal(req.Requester)
+		}
+		if !found {
+			return ErrUnknownRequester
+		}
+	}
+	if !s.schnorrVerify(requestTag, &req.Requester.p, s.requestMessage(req), &req.sig) {
+		return ErrInvalidRequest
+	}
+	return nil
+}
+
+// MarshalAdjudicationRequest encodes a request
+func (s *Scheme[G1, G2, P1, P2]) MarshalAdjudicationRequest(req *AdjudicationRequest[G1, G2, P1, P2]) []byte {
+	return s.appendSchnorr(s.requestMessage(req), &req.sig)
+}
+
+// UnmarshalAdjudicationRequest decodes a request. Use
+// VerifyAdjudicationRequest to check it
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalAdjudicationRequest(b []byte) (*AdjudicationRequest[G1, G2, P1, P2], error) {
+	n1 := len(P1(new(G1)).Marshal())
+	n2 := len(P2(new(G2)).Marshal())
+	if len(b) < 1+2*n1+n2+32+8+2 {
+		return nil, ErrInvalidLength
+	}
+	if Ciphersuite(b[0]) != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	b = b[1:]
+
+	req := AdjudicationRequest[G1, G2, P1, P2]{
+		Requester:   &PublicKey[G1, P1]{},
+		Adjudicator: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+	}
+	if err := req.Requester.Unmarshal(b[:n1]); err != nil {
+		return nil, err
+	}
+	if err := req.Adjudicator.Unmarshal(b[n1 : 2*n1+n2]); err != nil {
+		return nil, err
+	}
+	b = b[2*n1+n2:]
+	b = b[copy(req.VESigDigest[:], b):]
+	req.Timestamp = binary.BigEndian.Uint64(b)
+	n := int(binary.BigEndian.Uint16(b[8:]))
+	b = b[10:]
+	if len(b) < n {
+		return nil, ErrInvalidLength
+	}
+	req.Reason = string(b[:n])
+	b, err := s.parseSchnorr(&req.sig, b[n:])
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, ErrInvalidLength
+	}
+	return &req, nil
+}
+
+// AdjudicateRequest verifies req and sig, adjudicates sig, and returns the
+// recovered signature along with a receipt binding the request. See
+// VerifyAdjudicationRequest for registered
+func (s *Scheme[G1, G2, P1, P2]) AdjudicateRequest(adjSK *SecretKey, pk *PublicKey[G1, P1], msg []byte, sig *VESig[G2, P2], req *AdjudicationRequest[G1, G2, P1, P2], registered []*PublicKey[G1, P1], timestamp uint64) (*Signature[G2, P2], *AdjudicationReceipt[G1, G2, P1, P2], error) {
+	adj := s.AdjudicatorPublicKey(adjSK)
+	if err := s.VerifyAdjudicationRequest(req, adj, sig, registered); err != nil {
+		return nil, nil, err
+	}
+	res, err := s.VerifyAndAdjudicate(adjSK, pk, msg, sig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := AdjudicationReceipt[G1, G2, P1, P2]{
+		Adjudicator:   adj,
+		VESigDigest:   req.VESigDigest,
+		RequestDigest: sha256.Sum256(s.MarshalAdjudicationRequest(req)),
+		Timestamp:     timestamp,
+	}
+	rs, err := s.schnorrSign(adjudicationTag, adjSK, s.adjudicationMessage(&r))
+	if err != nil {
+		return nil, nil, err
+	}
+	r.sig = *rs
+	return res, &r, nil
+}
+
+// adjudicationMessage is the signed part of the adjudication receipt
+func (s *Scheme[G1, G2, P1, P2]) adjudicationMessage(r *AdjudicationReceipt[G1, G2, P1, P2]) []byte {
+	b := []byte{byte(s.Suite)}
+	b = s.AppendAdjudicatorPublicKey(b, r.Adjudicator)
+	b = append(b, r.VESigDigest[:]...)
+	b = append(b, r.RequestDigest[:]...)
+	n := len(b)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[n:], r.Timestamp)
+	return b
+}
+
+// VerifyAdjudicationReceipt checks that r is the receipt of the adjudicator
+// named in req for opening an escrow at req
+func (s *Scheme[G1, G2, P1, P2]) VerifyAdjudicationReceipt(r *AdjudicationReceipt[G1, G2, P1, P2], req *AdjudicationRequest[G1, G2, P1, P2]) error {
+	if r.Adjudicator == nil || !inSubGroup[G1, P1](&r.Adjudicator.g1) ||
+		req.Adjudicator == nil || !r.Adjudicator.Equal(req.Adjudicator) ||
+		r.VESigDigest != req.VESigDigest ||
+		r.RequestDigest != sha256.Sum256(s.MarshalAdjudicationRequest(req)) {
+		return ErrInvalidReceipt
+	}
+	if !s.schnorrVerify(adjudicationTag, &r.Adjudicator.g1, s.adjudicationMessage(r), &r.sig) {
+		return ErrInvalidReceipt
+	}
+	return nil
+}
+
+// MarshalAdjudicationReceipt encodes an adjudication receipt
+func (s *Scheme[G1, G2, P1, P2]) MarshalAdjudicationReceipt(r *AdjudicationReceipt[G1, G2, P1, P2]) []byte {
+	return s.appendSchnorr(s.adjudicationMessage(r), &r.sig)
+}
+
+// UnmarshalAdjudicationReceipt decodes an adjudication receipt. Use
+// VerifyAdjudicationReceipt to check it
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalAdjudicationReceipt(b []byte) (*AdjudicationReceipt[G1, G2, P1, P2], error) {
+	n1 := len(P1(new(G1)).Marshal())
+	n2 := len(P2(new(G2)).Marshal())
+	if len(b) < 1+n1+n2+32+32+8 {
+		return nil, ErrInvalidLength
+	}
+	if Ciphersuite(b[0]) != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	b = b[1:]
+
+	r := AdjudicationReceipt[G1, G2, P1, P2]{
+		Adjudicator: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+	}
+	if err := r.Adjudicator.Unmarshal(b[:n1+n2]); err != nil {
+		return nil, err
+	}
+	b = b[n1+n2:]
+	b = b[copy(r.VESigDigest[:], b):]
+	b = b[copy(r.RequestDigest[:], b):]
+	r.Timestamp = binary.BigEndian.Uint64(b)
+	b, err := s.parseSchnorr(&r.sig, b[8:])
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, ErrInvalidLength
+	}
+	return &r, nil
+}
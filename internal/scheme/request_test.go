@@ -0,0 +1,46 @@
+package scheme
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+)
+
+func TestVerifyAdjudicationReceipt(t *testing.T) {
+	s := newTestScheme()
+	sk, adjSK, requesterSK, otherSK := generateKey(t), generateKey(t), generateKey(t), generateKey(t)
+	adj := s.AdjudicatorPublicKey(adjSK)
+	msg := []byte("escrow")
+	sig, err := s.Sign(sk, adj, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := s.NewAdjudicationRequest(requesterSK, adj, sig, "case 1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, r, err := s.AdjudicateRequest(adjSK, s.PublicKey(sk), msg, sig, req, nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerifyAdjudicationReceipt(r, req); err != nil {
+		t.Fatalf("valid receipt rejected: %v", err)
+	}
+
+	// A well-formed receipt for the request, signed by another key
+	forged := AdjudicationReceipt[testG1, testG2, *testG1, *testG2]{
+		Adjudicator:   s.AdjudicatorPublicKey(otherSK),
+		VESigDigest:   req.VESigDigest,
+		RequestDigest: sha256.Sum256(s.MarshalAdjudicationRequest(req)),
+		Timestamp:     2,
+	}
+	rs, err := s.schnorrSign(adjudicationTag, otherSK, s.adjudicationMessage(&forged))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged.sig = *rs
+	if err := s.VerifyAdjudicationReceipt(&forged, req); !errors.Is(err, ErrInvalidReceipt) {
+		t.Fatalf("receipt from another adjudicator: got %v, want %v", err, ErrInvalidReceipt)
+	}
+}
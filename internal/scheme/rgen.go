@@ -0,0 +1,176 @@
+package scheme
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	ErrCommitmentMismatch = errors.New("reveal does not match commitment")
+	ErrRoundIncomplete    = errors.New("round incomplete")
+	ErrUnexpectedMessage  = errors.New("unexpected message for this round")
+)
+
+const rCommitTag = "VESS-R-COMMIT-V1"
+
+// RNonce is a signing set member's share r_i of the escrow randomness, for
+// one session. It is used once, by PartialSignNonce
+type RNonce[G2 any, P2 Point[G2]] struct {
+	index int
+	r     *big.Int
+	mu    G2
+}
+
+// RTranscript records a two round commit-reveal generation of
+// mu = sum r_i.g2 for threshold signing, see PartialVESig:
+//
+//  1. Each member draws an RNonce and sends its commitment
+//     H(session || i || mu_i), see Commit
+//  2. Once every commitment is in, each member reveals mu_i, see Reveal
+//
+// Since commitments are fixed before any mu_i is known, no member can bias
+// mu. If a member fails to commit or reveal in time, see Missing, or reveals
+// a mismatching mu_i, the session is aborted and restarted with a new ID and
+// a signing set without that member
+type RTranscript[G2 any, P2 Point[G2]] struct {
+	Session     [32]byte
+	Indices     []int
+	Commitments map[int][32]byte
+	Reveals     map[int]G2
+}
+
+// NewRNonce draws the r_i of the member at index for session, and returns
+// its commitment
+func (s *Scheme[G1, G2, P1, P2]) NewRNonce(session [32]byte, index int) (*RNonce[G2, P2], [32]byte, error) {
+	r, err := rand.Int(rand.Reader, s.Order)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	n := RNonce[G2, P2]{index: index, r: r}
+	P2(&n.mu).ScalarMultiplication(&s.G2Gen, r)
+	return &n, s.rCommitment(session, index, &n.mu), nil
+}
+
+// Mu returns mu_i, to reveal in the second round
+func (n *RNonce[G2, P2]) Mu() G2 {
+	return n.mu
+}
+
+func (s *Scheme[G1, G2, P1, P2]) rCommitment(session [32]byte, index int, mu *G2) [32]byte {
+	b := append([]byte(rCommitTag), session[:]...)
+	n := len(b)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[n:], uint64(index))
+	return sha256.Sum256(s.AppendG2(b, mu))
+}
+
+// NewRTranscript starts a session for the signing set indices
+func NewRTranscript[G2 any, P2 Point[G2]](session [32]byte, indices []int) *RTranscript[G2, P2] {
+	return &RTranscript[G2, P2]{
+		Session:     session,
+		Indices:     append([]int{}, indices...),
+		Commitments: map[int][32]byte{},
+		Reveals:     map[int]G2{},
+	}
+}
+
+func (t *RTranscript[G2, P2]) member(index int) bool {
+	for _, i := range t.Indices {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing returns the members whose message for the current round is
+// missing
+func (t *RTranscript[G2, P2]) Missing() []int {
+	res := []int{}
+	for _, i := range t.Indices {
+		_, committed := t.Commitments[i]
+		_, revealed := t.Reveals[i]
+		if !committed || (len(t.Commitments) == len(t.Indices) && !revealed) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Commit records the first round message of the member at index
+func (t *RTranscript[G2, P2]) Commit(index int, c [32]byte) error {
+	if !t.member(index) {
+		return ErrNotMember
+	}
+	if _, ok := t.Commitments[index]; ok {
+		return ErrUnexpectedMessage
+	}
+	t.Commitments[index] = c
+	return nil
+}
+
+// Reveal records the second round message of the member at index. A
+// mismatch aborts the session
+func (s *Scheme[G1, G2, P1, P2]) Reveal(t *RTranscript[G2, P2], index int, mu G2) error {
+	if !t.member(index) {
+		return ErrNotMember
+	}
+	if len(t.Commitments) != len(t.Indices) {
+		return ErrRoundIncomplete
+	}
+	if _, ok := t.Reveals[index]; ok {
+		return ErrUnexpectedMessage
+	}
+	if !inSubGroup[G2, P2](&mu) || s.rCommitment(t.Session, index, &mu) != t.Commitments[index] {
+		return fmt.Errorf("%w: member %d", ErrCommitmentMismatch, index)
+	}
+	t.Reveals[index] = mu
+	return nil
+}
+
+// Mu returns mu = sum mu_i, once every member revealed
+func (s *Scheme[G1, G2, P1, P2]) Mu(t *RTranscript[G2, P2]) (G2, error) {
+	mu := new(G2)
+	if len(t.Reveals) != len(t.Indices) {
+		return *mu, ErrRoundIncomplete
+	}
+	for _, i := range t.Indices {
+		m := t.Reveals[i]
+		P2(mu).Add(mu, &m)
+	}
+	return *mu, nil
+}
+
+// PartialSignNonce is PartialSignWithRandomness with the r_i of n, which is
+// then erased
+func (s *Scheme[G1, G2, P1, P2]) PartialSignNonce(share *SecretKey, n *RNonce[G2, P2], t *RTranscript[G2, P2], adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte) (*PartialVESig[G2, P2], error) {
+	if n.r == nil {
+		return nil, ErrUnexpectedMessage
+	}
+	if len(t.Reveals) != len(t.Indices) {
+		return nil, ErrRoundIncomplete
+	}
+	pv, err := s.PartialSignWithRandomness(share, n.index, t.Indices, adj, msg, n.r)
+	n.r.SetInt64(0)
+	n.r = nil
+	return pv, err
+}
+
+// CombineTranscript is CombinePartialVESigs for the signing set of t,
+// checking that every part uses the mu_i revealed in t
+func (s *Scheme[G1, G2, P1, P2]) CombineTranscript(c *Committee[G1, P1], t *RTranscript[G2, P2], adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, parts []*PartialVESig[G2, P2]) (*VESig[G2, P2], error) {
+	if len(t.Reveals) != len(t.Indices) || len(parts) != len(t.Indices) {
+		return nil, ErrRoundIncomplete
+	}
+	for i, pv := range parts {
+		mu := t.Reveals[t.Indices[i]]
+		if !P2(&mu).Equal(&pv.mu) {
+			return nil, fmt.Errorf("%w: member %d", ErrCommitmentMismatch, t.Indices[i])
+		}
+	}
+	return s.CombinePartialVESigs(c, t.Indices, adj, msg, parts)
+}
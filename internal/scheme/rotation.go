@@ -0,0 +1,143 @@
+package scheme
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidTransition = errors.New("invalid key transition statement")
+
+const transitionTag = "VESS-KEY-TRANSITION-V1"
+
+// Transition announces the rotation of an adjudicator key. Escrows to the
+// old key are refused from Retire on, and old escrows are adjudicated until
+// GraceEnd. Both keys sign the statement: the old key authorizes the
+// rotation, the new key proves possession
+type Transition[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	Old, New *AdjudicatorPublicKey[G1, G2, P1, P2]
+	// Unix times
+	Retire, GraceEnd uint64
+
+	oldSig, newSig schnorrSig[G1, P1]
+}
+
+// NewTransition returns a signed statement rotating oldSK to newSK
+func (s *Scheme[G1, G2, P1, P2]) NewTransition(oldSK, newSK *SecretKey, retire, graceEnd uint64) (*Transition[G1, G2, P1, P2], error) {
+	if graceEnd < retire {
+		return nil, ErrInvalidTransition
+	}
+	t := Transition[G1, G2, P1, P2]{
+		Old:      s.AdjudicatorPublicKey(oldSK),
+		New:      s.AdjudicatorPublicKey(newSK),
+		Retire:   retire,
+		GraceEnd: graceEnd,
+	}
+	msg := s.transitionMessage(&t)
+	oldSig, err := s.schnorrSign(transitionTag, oldSK, msg)
+	if err != nil {
+		return nil, err
+	}
+	newSig, err := s.schnorrSign(transitionTag, newSK, msg)
+	if err != nil {
+		return nil, err
+	}
+	t.oldSig, t.newSig = *oldSig, *newSig
+	return &t, nil
+}
+
+// transitionMessage is the signed part of the statement
+func (s *Scheme[G1, G2, P1, P2]) transitionMessage(t *Transition[G1, G2, P1, P2]) []byte {
+	b := []byte{byte(s.Suite)}
+	b = s.AppendAdjudicatorPublicKey(b, t.Old)
+	b = s.AppendAdjudicatorPublicKey(b, t.New)
+	n := len(b)
+	b = append(b, make([]byte, 16)...)
+	binary.BigEndian.PutUint64(b[n:], t.Retire)
+	binary.BigEndian.PutUint64(b[n+8:], t.GraceEnd)
+	return b
+}
+
+// VerifyTransition checks both signatures of the statement, and that both
+// adjudicator keys are consistent across G1 and G2
+func (s *Scheme[G1, G2, P1, P2]) VerifyTransition(t *Transition[G1, G2, P1, P2]) error {
+	if t.Old == nil || t.New == nil || t.GraceEnd < t.Retire ||
+		t.Old.Equal(t.New) {
+		return ErrInvalidTransition
+	}
+	for _, apk := range []*AdjudicatorPublicKey[G1, G2, P1, P2]{t.Old, t.New} {
+		ok, err := s.CheckAdjudicatorPublicKey(apk)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidTransition
+		}
+	}
+
+	msg := s.transitionMessage(t)
+	if !s.schnorrVerify(transitionTag, &t.Old.g1, msg, &t.oldSig) ||
+		!s.schnorrVerify(transitionTag, &t.New.g1, msg, &t.newSig) {
+		return ErrInvalidTransition
+	}
+	return nil
+}
+
+// AcceptsEscrows reports whether new escrows may be made to adj at time now
+func (t *Transition[G1, G2, P1, P2]) AcceptsEscrows(adj *AdjudicatorPublicKey[G1, G2, P1, P2], now uint64) bool {
+	return !adj.Equal(t.Old) || now < t.Retire
+}
+
+// Adjudicates reports whether escrows to adj are still adjudicated at time
+// now
+func (t *Transition[G1, G2, P1, P2]) Adjudicates(adj *AdjudicatorPublicKey[G1, G2, P1, P2], now uint64) bool {
+	return !adj.Equal(t.Old) || now < t.GraceEnd
+}
+
+// MarshalTransition encodes a transition statement for publication
+func (s *Scheme[G1, G2, P1, P2]) MarshalTransition(t *Transition[G1, G2, P1, P2]) []byte {
+	b := s.transitionMessage(t)
+	b = s.appendSchnorr(b, &t.oldSig)
+	return s.appendSchnorr(b, &t.newSig)
+}
+
+// UnmarshalTransition decodes and verifies a transition statement
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalTransition(b []byte) (*Transition[G1, G2, P1, P2], error) {
+	n := len(P1(new(G1)).Marshal()) + len(P2(new(G2)).Marshal())
+	if len(b) < 1+2*n+16 {
+		return nil, ErrInvalidLength
+	}
+	if Ciphersuite(b[0]) != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	b = b[1:]
+
+	t := Transition[G1, G2, P1, P2]{
+		Old: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+		New: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+	}
+	if err := t.Old.Unmarshal(b[:n]); err != nil {
+		return nil, err
+	}
+	if err := t.New.Unmarshal(b[n : 2*n]); err != nil {
+		return nil, err
+	}
+	b = b[2*n:]
+	t.Retire = binary.BigEndian.Uint64(b)
+	t.GraceEnd = binary.BigEndian.Uint64(b[8:])
+	b, err := s.parseSchnorr(&t.oldSig, b[16:])
+	if err != nil {
+		return nil, err
+	}
+	b, err = s.parseSchnorr(&t.newSig, b)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, ErrInvalidLength
+	}
+
+	if err := s.VerifyTransition(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,376 @@
+// Package scheme implements the verifiably encrypted signature scheme over
+// any of gnark's pairing-friendly curves. Public keys live in G1 and
+// signatures in G2. Curve packages only supply the curve parameters and
+// alias the generic types
+package scheme
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sync"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrInvalidPoint     = errors.New("point not in the prime order subgroup")
+	ErrIdentity         = errors.New("identity point")
+	ErrKeyMismatch      = errors.New("adjudicator key halves do not match")
+)
+
+// Point is satisfied by pointers to gnark's affine point types
+type Point[T any] interface {
+	*T
+	Set(a *T) *T
+	Add(a, b *T) *T
+	Sub(a, b *T) *T
+	Neg(a *T) *T
+	ScalarMultiplication(a *T, s *big.Int) *T
+	Equal(a *T) bool
+	IsInfinity() bool
+	IsOnCurve() bool
+	IsInSubGroup() bool
+	Marshal() []byte
+	Unmarshal(buf []byte) error
+}
+
+// Curve holds the parameters of a pairing-friendly curve
+type Curve[G1, G2 any] struct {
+	// Suite identifies the curve and hash parameters in versioned encodings
+	Suite Ciphersuite
+	// Order of G1 and G2
+	Order *big.Int
+	// Generators
+	G1Gen G1
+	G2Gen G2
+	// Hash hashes a message to G2
+	Hash func(msg []byte) (G2, error)
+	// PairingCheck reports whether prod e(P[i], Q[i]) == 1
+	PairingCheck func(P []G1, Q []G2) (bool, error)
+	// AppendG1 and AppendG2 append the uncompressed encoding of a point,
+	// without intermediate allocations
+	AppendG1 func(dst []byte, p *G1) []byte
+	AppendG2 func(dst []byte, p *G2) []byte
+	// AppendG1Compressed and AppendG2Compressed append the compressed
+	// encoding of a point. DecodeG1Compressed and DecodeG2Compressed decode
+	// it, rejecting non-canonical encodings and points outside the subgroup
+	AppendG1Compressed func(dst []byte, p *G1) []byte
+	AppendG2Compressed func(dst []byte, p *G2) []byte
+	DecodeG1Compressed func(p *G1, b []byte) error
+	DecodeG2Compressed func(p *G2, b []byte) error
+	// DecodeG1Lenient and DecodeG2Lenient decode uncompressed points, only
+	// checking that they are on the curve. Optional, see Lenient
+	DecodeG1Lenient func(p *G1, b []byte) error
+	DecodeG2Lenient func(p *G2, b []byte) error
+	// MultiExpG2 computes sum scalars[i].points[i] with Pippenger's
+	// algorithm. Optional: repeated scalar multiplications are used otherwise
+	MultiExpG2 func(points []G2, scalars []*big.Int) (G2, error)
+	// ParallelPairingCheck is PairingCheck split across goroutines, for large
+	// checks with a context. Optional, see the function of the same name
+	ParallelPairingCheck func(ctx context.Context, P []G1, Q []G2) (bool, error)
+}
+
+// Scheme implements the scheme over a curve. It is safe for concurrent use:
+// the curve is never modified after New, and decode counters and timings
+// are atomic. Keys, signatures and committees are values, safe for concurrent reads but
+// not for reads concurrent with writes
+type Scheme[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	Curve[G1, G2]
+
+	stats   *decodeStats
+	timings *workTimings
+	// Pool of *pairingInput[G1, G2]
+	pairings *sync.Pool
+}
+
+// SecretKey is a signer or adjudicator secret key
+type SecretKey struct {
+	x big.Int
+}
+
+// PublicKey is a signer public key on G1
+type PublicKey[G1 any, P1 Point[G1]] struct {
+	p G1
+}
+
+// AdjudicatorPublicKey is the adjudicator public key on G1 and G2.
+// The key on G2 is required for Type 3 pairings
+type AdjudicatorPublicKey[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	g1 G1
+	g2 G2
+}
+
+// Signature is a regular BLS signature on G2
+type Signature[G2 any, P2 Point[G2]] struct {
+	p G2
+}
+
+// VESig is a verifiably encrypted signature (omega, mu)
+type VESig[G2 any, P2 Point[G2]] struct {
+	omega G2
+	mu    G2
+}
+
+// New returns a scheme over curve c
+func New[G1, G2 any, P1 Point[G1], P2 Point[G2]](c Curve[G1, G2]) *Scheme[G1, G2, P1, P2] {
+	return &Scheme[G1, G2, P1, P2]{Curve: c, stats: &decodeStats{}, timings: &workTimings{}, pairings: &sync.Pool{}}
+}
+
+// inSubGroup reports whether p is on the curve and in the prime order subgroup
+func inSubGroup[T any, P Point[T]](p *T) bool {
+	return P(p).IsOnCurve() && P(p).IsInSubGroup()
+}
+
+// NewPublicKey returns the public key for point p. The identity is
+// rejected: it would verify any signature of the identity
+func NewPublicKey[G1 any, P1 Point[G1]](p G1) (*PublicKey[G1, P1], error) {
+	if !inSubGroup[G1, P1](&p) {
+		return nil, ErrInvalidPoint
+	}
+	if P1(&p).IsInfinity() {
+		return nil, ErrIdentity
+	}
+	return &PublicKey[G1, P1]{p: p}, nil
+}
+
+// NewAdjudicatorPublicKey returns the adjudicator public key for points g1
+// and g2, which must be the same secret key on each group, see
+// CheckAdjudicatorPublicKey
+func (s *Scheme[G1, G2, P1, P2]) NewAdjudicatorPublicKey(g1 G1, g2 G2) (*AdjudicatorPublicKey[G1, G2, P1, P2], error) {
+	if !inSubGroup[G1, P1](&g1) || !inSubGroup[G2, P2](&g2) {
+		return nil, ErrInvalidPoint
+	}
+	if P1(&g1).IsInfinity() || P2(&g2).IsInfinity() {
+		return nil, ErrIdentity
+	}
+	apk := AdjudicatorPublicKey[G1, G2, P1, P2]{g1: g1, g2: g2}
+	ok, err := s.CheckAdjudicatorPublicKey(&apk)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrKeyMismatch
+	}
+	return &apk, nil
+}
+
+// CheckAdjudicatorPublicKey reports whether both halves of apk share the
+// same secret key, that is e(g1^x', g2) == e(g1, g2^x')
+func (s *Scheme[G1, G2, P1, P2]) CheckAdjudicatorPublicKey(apk *AdjudicatorPublicKey[G1, G2, P1, P2]) (bool, error) {
+	if !inSubGroup[G1, P1](&apk.g1) || !inSubGroup[G2, P2](&apk.g2) {
+		return false, nil
+	}
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	return s.PairingCheck(
+		[]G1{apk.g1, *ng1},
+		[]G2{s.G2Gen, apk.g2},
+	)
+}
+
+// NewSignature returns the signature for point p, which may not be the
+// identity
+func NewSignature[G2 any, P2 Point[G2]](p G2) (*Signature[G2, P2], error) {
+	if !inSubGroup[G2, P2](&p) {
+		return nil, ErrInvalidPoint
+	}
+	if P2(&p).IsInfinity() {
+		return nil, ErrIdentity
+	}
+	return &Signature[G2, P2]{p: p}, nil
+}
+
+// NewVESig returns the verifiably encrypted signature (omega, mu)
+func NewVESig[G2 any, P2 Point[G2]](omega, mu G2) (*VESig[G2, P2], error) {
+	if !inSubGroup[G2, P2](&omega) || !inSubGroup[G2, P2](&mu) {
+		return nil, ErrInvalidPoint
+	}
+	return &VESig[G2, P2]{omega: omega, mu: mu}, nil
+}
+
+// GenerateKey returns a random secret key for a curve of the given order
+func GenerateKey(order *big.Int) (*SecretKey, error) {
+	sk := SecretKey{}
+	for sk.x.Sign() == 0 {
+		x, err := rand.Int(rand.Reader, order)
+		if err != nil {
+			return nil, err
+		}
+		sk.x.Set(x)
+	}
+	return &sk, nil
+}
+
+// Scalar returns a copy of the secret scalar
+func (sk *SecretKey) Scalar() *big.Int {
+	return new(big.Int).Set(&sk.x)
+}
+
+// Point returns the public key point
+func (pk *PublicKey[G1, P1]) Point() G1 {
+	return pk.p
+}
+
+// G1 returns the adjudicator public key on G1
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) G1() G1 {
+	return apk.g1
+}
+
+// G2 returns the adjudicator public key on G2
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) G2() G2 {
+	return apk.g2
+}
+
+// Point returns the signature point
+func (s *Signature[G2, P2]) Point() G2 {
+	return s.p
+}
+
+// Omega returns the omega component of the signature
+func (sig *VESig[G2, P2]) Omega() G2 {
+	return sig.omega
+}
+
+// Mu returns the mu component of the signature
+func (sig *VESig[G2, P2]) Mu() G2 {
+	return sig.mu
+}
+
+// PublicKey returns the signer public key for sk
+func (s *Scheme[G1, G2, P1, P2]) PublicKey(sk *SecretKey) *PublicKey[G1, P1] {
+	pk := PublicKey[G1, P1]{}
+	P1(&pk.p).ScalarMultiplication(&s.G1Gen, &sk.x)
+	return &pk
+}
+
+// AdjudicatorPublicKey returns the adjudicator public key for sk
+func (s *Scheme[G1, G2, P1, P2]) AdjudicatorPublicKey(sk *SecretKey) *AdjudicatorPublicKey[G1, G2, P1, P2] {
+	apk := AdjudicatorPublicKey[G1, G2, P1, P2]{}
+	P1(&apk.g1).ScalarMultiplication(&s.G1Gen, &sk.x)
+	P2(&apk.g2).ScalarMultiplication(&s.G2Gen, &sk.x)
+	return &apk
+}
+
+// Sign creates a verifiably encrypted signature on msg, which only the
+// adjudicator owning adj can open
+func (s *Scheme[G1, G2, P1, P2]) Sign(sk *SecretKey, adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte) (*VESig[G2, P2], error) {
+	// Select r at random from Zp
+	r, err := rand.Int(rand.Reader, s.Order)
+	if err != nil {
+		return nil, err
+	}
+	return s.SignWithRandomness(sk, adj, msg, r)
+}
+
+// SignWithRandomness is Sign with a caller-supplied r. Reusing r, or using a
+// predictable one, lets anyone open the signature: this is only meant for
+// deterministic test vectors
+func (s *Scheme[G1, G2, P1, P2]) SignWithRandomness(sk *SecretKey, adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, r *big.Int) (*VESig[G2, P2], error) {
+	// Compute h = H(M), sigma = h^x
+	h, err := s.Hash(msg)
+	if err != nil {
+		return nil, err
+	}
+	sigma := new(G2)
+	P2(sigma).ScalarMultiplication(&h, &sk.x)
+
+	// Set mu = phi(g2)^r
+	// Note the ETH2 spec swaps the G1 and G2 groups to get smaller public keys
+	// Also note that phi(g2) == g1
+	sig := VESig[G2, P2]{}
+	P2(&sig.mu).ScalarMultiplication(&s.G2Gen, r)
+
+	// Set sigma_2 = phi(v')^r
+	// Reminder: phi(v') is is the adjudicator pubkey on G2
+	sigma2 := new(G2)
+	P2(sigma2).ScalarMultiplication(&adj.g2, r)
+
+	// Aggregate sigma and sigma_2 as omega = sigma * sigma_2
+	// In the original paper, G1 is a multiplicative group. Here G1 is additive
+	P2(&sig.omega).Add(sigma, sigma2)
+
+	return &sig, nil
+}
+
+// Verify checks a verifiably encrypted signature on msg, given the signer
+// public key pk and the adjudicator public key adj
+func (s *Scheme[G1, G2, P1, P2]) Verify(pk *PublicKey[G1, P1], adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, sig *VESig[G2, P2]) (bool, error) {
+	return s.verify(pk, &adj.g1, msg, sig)
+}
+
+func (s *Scheme[G1, G2, P1, P2]) verify(pk *PublicKey[G1, P1], adjG1 *G1, msg []byte, sig *VESig[G2, P2]) (bool, error) {
+	h, err := s.Hash(msg)
+	if err != nil {
+		return false, err
+	}
+
+	// Accept if e(omega, g2) == e(h, v) . e(mu, v')
+	// Friendly reminder that ETH2 swaps G1 and G2
+	// Check e(omega, g2)^-1 . e(h, v) . e(mu, v') == 1 instead, which shares
+	// the final exponentiation
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	return s.PairingCheck(
+		[]G1{*ng1, pk.p, *adjG1},
+		[]G2{sig.omega, h, sig.mu},
+	)
+}
+
+// Adjudicate recovers the original signature from a verifiably encrypted
+// signature. The caller is expected to have verified sig
+func (s *Scheme[G1, G2, P1, P2]) Adjudicate(adjSK *SecretKey, sig *VESig[G2, P2]) *Signature[G2, P2] {
+	// sigma = omega / mu^adjSKey
+	res := Signature[G2, P2]{}
+	P2(&res.p).ScalarMultiplication(&sig.mu, &adjSK.x)
+	P2(&res.p).Sub(&sig.omega, &res.p)
+	return &res
+}
+
+// VerifyRecovered checks a regular BLS signature on msg, such as one
+// recovered by Adjudicate
+func (s *Scheme[G1, G2, P1, P2]) VerifyRecovered(pk *PublicKey[G1, P1], msg []byte, sig *Signature[G2, P2]) (bool, error) {
+	h, err := s.Hash(msg)
+	if err != nil {
+		return false, err
+	}
+
+	// e(sigma, g2)^-1 . e(h, v) == 1
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	return s.PairingCheck(
+		[]G1{*ng1, pk.p},
+		[]G2{sig.p, h},
+	)
+}
+
+// AdjudicateAndVerify adjudicates sig and checks that the recovered signature
+// is a valid signature on msg under pk
+func (s *Scheme[G1, G2, P1, P2]) AdjudicateAndVerify(adjSK *SecretKey, pk *PublicKey[G1, P1], msg []byte, sig *VESig[G2, P2]) (*Signature[G2, P2], error) {
+	res := s.Adjudicate(adjSK, sig)
+	ok, err := s.VerifyRecovered(pk, msg, res)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	return res, nil
+}
+
+// VerifyAndAdjudicate verifies sig and adjudicates it, in a single pass.
+// sig is verified against the adjudicator key derived from adjSK, so
+// e(omega / mu^x', g2) == e(h, v) holds and the recovered signature does not
+// need to be verified again, unlike with Verify followed by AdjudicateAndVerify
+func (s *Scheme[G1, G2, P1, P2]) VerifyAndAdjudicate(adjSK *SecretKey, pk *PublicKey[G1, P1], msg []byte, sig *VESig[G2, P2]) (*Signature[G2, P2], error) {
+	adjG1 := new(G1)
+	P1(adjG1).ScalarMultiplication(&s.G1Gen, &adjSK.x)
+	ok, err := s.verify(pk, adjG1, msg, sig)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	return s.Adjudicate(adjSK, sig), nil
+}
@@ -0,0 +1,92 @@
+package scheme
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// The package tests run over gnark's BN254, which needs no cgo. They cover
+// what the curve packages cannot reach, such as statements signed with keys
+// other than the ones the API allows
+
+type testG1 = bn254.G1Affine
+type testG2 = bn254.G2Affine
+
+func newTestScheme() *Scheme[testG1, testG2, *testG1, *testG2] {
+	_, _, g1, g2 := bn254.Generators()
+	return New[testG1, testG2, *testG1, *testG2](Curve[testG1, testG2]{
+		Suite: CiphersuiteBN254G2XMDSHA256,
+		Order: fr.Modulus(),
+		G1Gen: g1,
+		G2Gen: g2,
+		Hash: func(msg []byte) (testG2, error) {
+			return bn254.HashToCurveG2Svdw(msg, []byte("VESS-TEST"))
+		},
+		PairingCheck: bn254.PairingCheck,
+		AppendG1: func(dst []byte, p *testG1) []byte {
+			b := p.RawBytes()
+			return append(dst, b[:]...)
+		},
+		AppendG2: func(dst []byte, p *testG2) []byte {
+			b := p.RawBytes()
+			return append(dst, b[:]...)
+		},
+		AppendG1Compressed: func(dst []byte, p *testG1) []byte {
+			b := p.Bytes()
+			return append(dst, b[:]...)
+		},
+		AppendG2Compressed: func(dst []byte, p *testG2) []byte {
+			b := p.Bytes()
+			return append(dst, b[:]...)
+		},
+		DecodeG1Compressed: func(p *testG1, b []byte) error {
+			_, err := p.SetBytes(b)
+			return err
+		},
+		DecodeG2Compressed: func(p *testG2, b []byte) error {
+			_, err := p.SetBytes(b)
+			return err
+		},
+	})
+}
+
+func generateKey(t *testing.T) *SecretKey {
+	sk, err := GenerateKey(fr.Modulus())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk
+}
+
+func TestConstructorsRejectIdentity(t *testing.T) {
+	s := newTestScheme()
+	if _, err := NewPublicKey[testG1, *testG1](testG1{}); !errors.Is(err, ErrIdentity) {
+		t.Fatalf("public key: got %v, want %v", err, ErrIdentity)
+	}
+	if _, err := NewSignature[testG2, *testG2](testG2{}); !errors.Is(err, ErrIdentity) {
+		t.Fatalf("signature: got %v, want %v", err, ErrIdentity)
+	}
+	if _, err := s.NewAdjudicatorPublicKey(testG1{}, testG2{}); !errors.Is(err, ErrIdentity) {
+		t.Fatalf("adjudicator key: got %v, want %v", err, ErrIdentity)
+	}
+}
+
+func TestNewAdjudicatorPublicKey(t *testing.T) {
+	s := newTestScheme()
+	a := s.AdjudicatorPublicKey(generateKey(t))
+	b := s.AdjudicatorPublicKey(generateKey(t))
+
+	apk, err := s.NewAdjudicatorPublicKey(a.G1(), a.G2())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !apk.Equal(a) {
+		t.Fatal("key differs")
+	}
+	if _, err := s.NewAdjudicatorPublicKey(a.G1(), b.G2()); !errors.Is(err, ErrKeyMismatch) {
+		t.Fatalf("got %v, want %v", err, ErrKeyMismatch)
+	}
+}
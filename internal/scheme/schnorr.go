@@ -0,0 +1,84 @@
+package scheme
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"math/big"
+)
+
+// schnorrSig is a Schnorr signature in G1. Adjudicator keys sign with
+// Schnorr rather than BLS: adjudicating a VESig computes mu^x' in G2 for an
+// attacker controlled mu, which would act as a BLS signing oracle
+type schnorrSig[G1 any, P1 Point[G1]] struct {
+	r G1
+	z big.Int
+}
+
+// schnorrChallenge returns H(tag || X || R || msg) mod order
+func (s *Scheme[G1, G2, P1, P2]) schnorrChallenge(tag string, x, r *G1, msg []byte) *big.Int {
+	h := sha512.New()
+	h.Write([]byte(tag))
+	h.Write(s.AppendG1(nil, x))
+	h.Write(s.AppendG1(nil, r))
+	h.Write(msg)
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, s.Order)
+}
+
+func (s *Scheme[G1, G2, P1, P2]) schnorrSign(tag string, sk *SecretKey, msg []byte) (*schnorrSig[G1, P1], error) {
+	k, err := rand.Int(rand.Reader, s.Order)
+	if err != nil {
+		return nil, err
+	}
+	x := new(G1)
+	P1(x).ScalarMultiplication(&s.G1Gen, &sk.x)
+
+	// R = g1^k, z = k + c.x
+	sig := schnorrSig[G1, P1]{}
+	P1(&sig.r).ScalarMultiplication(&s.G1Gen, k)
+	c := s.schnorrChallenge(tag, x, &sig.r, msg)
+	sig.z.Mul(c, &sk.x)
+	sig.z.Add(&sig.z, k)
+	sig.z.Mod(&sig.z, s.Order)
+	return &sig, nil
+}
+
+// schnorrVerify checks g1^z == R . X^c
+func (s *Scheme[G1, G2, P1, P2]) schnorrVerify(tag string, x *G1, msg []byte, sig *schnorrSig[G1, P1]) bool {
+	if !inSubGroup[G1, P1](&sig.r) || sig.z.Cmp(s.Order) >= 0 {
+		return false
+	}
+	c := s.schnorrChallenge(tag, x, &sig.r, msg)
+	lhs := new(G1)
+	P1(lhs).ScalarMultiplication(&s.G1Gen, &sig.z)
+	rhs := new(G1)
+	P1(rhs).ScalarMultiplication(x, c)
+	P1(rhs).Add(rhs, &sig.r)
+	return P1(lhs).Equal(rhs)
+}
+
+func (s *Scheme[G1, G2, P1, P2]) appendSchnorr(dst []byte, sig *schnorrSig[G1, P1]) []byte {
+	dst = s.AppendG1(dst, &sig.r)
+	n := len(dst)
+	dst = append(dst, make([]byte, ScalarSize)...)
+	sig.z.FillBytes(dst[n:])
+	return dst
+}
+
+// parseSchnorr decodes a signature encoded by appendSchnorr and returns the
+// rest of b
+func (s *Scheme[G1, G2, P1, P2]) parseSchnorr(sig *schnorrSig[G1, P1], b []byte) ([]byte, error) {
+	n := len(P1(new(G1)).Marshal())
+	if len(b) < n+ScalarSize {
+		return nil, ErrInvalidLength
+	}
+	if err := unmarshalPoint[G1, P1](&sig.r, b[:n]); err != nil {
+		return nil, err
+	}
+	// z + order would verify too
+	sig.z.SetBytes(b[n : n+ScalarSize])
+	if sig.z.Cmp(s.Order) >= 0 {
+		return nil, ErrInvalidSignature
+	}
+	return b[n+ScalarSize:], nil
+}
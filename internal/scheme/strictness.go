@@ -0,0 +1,166 @@
+package scheme
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+)
+
+var ErrLenientUnsupported = errors.New("lenient decoding not supported by this curve")
+
+// Strictness selects how thoroughly decoded points are validated
+type Strictness int
+
+const (
+	// Strict requires points to be on the curve and in the prime order
+	// subgroup. This is what Unmarshal does
+	Strict Strictness = iota
+	// Lenient only requires points to be on the curve. Some legacy escrows
+	// were serialized without subgroup checks: this is meant to migrate them,
+	// never to accept new data
+	Lenient
+)
+
+// DecodeStats counts lenient decodes
+type DecodeStats struct {
+	// Lenient is the number of objects decoded in lenient mode
+	Lenient uint64
+	// NotInSubGroup is the number of lenient decodes that a strict decode
+	// would have rejected
+	NotInSubGroup uint64
+}
+
+type decodeStats struct {
+	lenient       uint64
+	notInSubGroup uint64
+}
+
+// DecodeStats returns the lenient decode counters
+func (s *Scheme[G1, G2, P1, P2]) DecodeStats() DecodeStats {
+	return DecodeStats{
+		Lenient:       atomic.LoadUint64(&s.stats.lenient),
+		NotInSubGroup: atomic.LoadUint64(&s.stats.notInSubGroup),
+	}
+}
+
+func (s *Scheme[G1, G2, P1, P2]) decodeG1(p *G1, b []byte, mode Strictness) error {
+	if mode == Strict {
+		return unmarshalPoint[G1, P1](p, b)
+	}
+	if s.DecodeG1Lenient == nil {
+		return ErrLenientUnsupported
+	}
+	if err := s.DecodeG1Lenient(p, b); err != nil {
+		return err
+	}
+	if !bytes.Equal(s.AppendG1(nil, p), b) {
+		return ErrNonCanonical
+	}
+	if !P1(p).IsInSubGroup() {
+		atomic.AddUint64(&s.stats.notInSubGroup, 1)
+	}
+	return nil
+}
+
+func (s *Scheme[G1, G2, P1, P2]) decodeG2(p *G2, b []byte, mode Strictness) error {
+	if mode == Strict {
+		return unmarshalPoint[G2, P2](p, b)
+	}
+	if s.DecodeG2Lenient == nil {
+		return ErrLenientUnsupported
+	}
+	if err := s.DecodeG2Lenient(p, b); err != nil {
+		return err
+	}
+	if !bytes.Equal(s.AppendG2(nil, p), b) {
+		return ErrNonCanonical
+	}
+	if !P2(p).IsInSubGroup() {
+		atomic.AddUint64(&s.stats.notInSubGroup, 1)
+	}
+	return nil
+}
+
+func (s *Scheme[G1, G2, P1, P2]) countDecode(mode Strictness) {
+	if mode == Lenient {
+		atomic.AddUint64(&s.stats.lenient, 1)
+	}
+}
+
+// UnmarshalPublicKey decodes a public key with the given strictness. The
+// identity is rejected in both modes
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalPublicKey(b []byte, mode Strictness) (*PublicKey[G1, P1], error) {
+	pk := PublicKey[G1, P1]{}
+	if err := s.decodeG1(&pk.p, b, mode); err != nil {
+		return nil, err
+	}
+	if P1(&pk.p).IsInfinity() {
+		return nil, ErrIdentity
+	}
+	s.countDecode(mode)
+	return &pk, nil
+}
+
+// UnmarshalAdjudicatorPublicKey decodes an adjudicator public key with the
+// given strictness. The identity is rejected in both modes
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalAdjudicatorPublicKey(b []byte, mode Strictness) (*AdjudicatorPublicKey[G1, G2, P1, P2], error) {
+	n1 := len(P1(new(G1)).Marshal())
+	n2 := len(P2(new(G2)).Marshal())
+	if len(b) != n1+n2 {
+		return nil, ErrInvalidLength
+	}
+	apk := AdjudicatorPublicKey[G1, G2, P1, P2]{}
+	if err := s.decodeG1(&apk.g1, b[:n1], mode); err != nil {
+		return nil, err
+	}
+	if err := s.decodeG2(&apk.g2, b[n1:], mode); err != nil {
+		return nil, err
+	}
+	if P1(&apk.g1).IsInfinity() || P2(&apk.g2).IsInfinity() {
+		return nil, ErrIdentity
+	}
+	s.countDecode(mode)
+	return &apk, nil
+}
+
+// UnmarshalSignature decodes a signature with the given strictness. The
+// identity is rejected in both modes
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalSignature(b []byte, mode Strictness) (*Signature[G2, P2], error) {
+	sig := Signature[G2, P2]{}
+	if err := s.decodeG2(&sig.p, b, mode); err != nil {
+		return nil, err
+	}
+	if P2(&sig.p).IsInfinity() {
+		return nil, ErrIdentity
+	}
+	s.countDecode(mode)
+	return &sig, nil
+}
+
+// UnmarshalVESig decodes a verifiably encrypted signature with the given
+// strictness
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalVESig(b []byte, mode Strictness) (*VESig[G2, P2], error) {
+	n := len(P2(new(G2)).Marshal())
+	if len(b) != 2*n {
+		return nil, ErrInvalidLength
+	}
+	sig := VESig[G2, P2]{}
+	if err := s.decodeG2(&sig.omega, b[:n], mode); err != nil {
+		return nil, err
+	}
+	if err := s.decodeG2(&sig.mu, b[n:], mode); err != nil {
+		return nil, err
+	}
+	s.countDecode(mode)
+	return &sig, nil
+}
+
+// UnmarshalPartial decodes a partial adjudication with the given strictness
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalPartial(b []byte, mode Strictness) (*Partial[G2, P2], error) {
+	pa := Partial[G2, P2]{}
+	if err := s.decodeG2(&pa.p, b, mode); err != nil {
+		return nil, err
+	}
+	s.countDecode(mode)
+	return &pa, nil
+}
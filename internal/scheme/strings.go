@@ -0,0 +1,158 @@
+package scheme
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidString = errors.New("invalid string encoding")
+
+// Text encodings of the uncompressed encodings returned by Marshal. Hex is
+// lowercase, and parsed case-insensitively with an optional 0x prefix.
+// Base64 is standard, padded base64 and parsed strictly. Whitespace is
+// rejected by both
+
+var b64 = base64.StdEncoding.Strict()
+
+func parseHex(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, ErrInvalidString
+	}
+	return b, nil
+}
+
+func parseBase64(s string) ([]byte, error) {
+	// The decoder skips newlines
+	if strings.ContainsAny(s, "\r\n") {
+		return nil, ErrInvalidString
+	}
+	b, err := b64.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, ErrInvalidString
+	}
+	return b, nil
+}
+
+type unmarshaler[T any] interface {
+	*T
+	Unmarshal(b []byte) error
+}
+
+func parse[T any, PT unmarshaler[T]](b []byte, err error) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	v := new(T)
+	if err := PT(v).Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Hex returns the hex encoding of pk
+func (pk *PublicKey[G1, P1]) Hex() string {
+	return hex.EncodeToString(pk.Marshal())
+}
+
+// Base64 returns the base64 encoding of pk
+func (pk *PublicKey[G1, P1]) Base64() string {
+	return b64.EncodeToString(pk.Marshal())
+}
+
+// ParsePublicKeyHex decodes a public key encoded by Hex
+func (s *Scheme[G1, G2, P1, P2]) ParsePublicKeyHex(str string) (*PublicKey[G1, P1], error) {
+	return parse[PublicKey[G1, P1]](parseHex(str))
+}
+
+// ParsePublicKeyBase64 decodes a public key encoded by Base64
+func (s *Scheme[G1, G2, P1, P2]) ParsePublicKeyBase64(str string) (*PublicKey[G1, P1], error) {
+	return parse[PublicKey[G1, P1]](parseBase64(str))
+}
+
+// Hex returns the hex encoding of apk
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) Hex() string {
+	return hex.EncodeToString(apk.Marshal())
+}
+
+// Base64 returns the base64 encoding of apk
+func (apk *AdjudicatorPublicKey[G1, G2, P1, P2]) Base64() string {
+	return b64.EncodeToString(apk.Marshal())
+}
+
+// ParseAdjudicatorPublicKeyHex decodes an adjudicator public key encoded by
+// Hex
+func (s *Scheme[G1, G2, P1, P2]) ParseAdjudicatorPublicKeyHex(str string) (*AdjudicatorPublicKey[G1, G2, P1, P2], error) {
+	return parse[AdjudicatorPublicKey[G1, G2, P1, P2]](parseHex(str))
+}
+
+// ParseAdjudicatorPublicKeyBase64 decodes an adjudicator public key encoded
+// by Base64
+func (s *Scheme[G1, G2, P1, P2]) ParseAdjudicatorPublicKeyBase64(str string) (*AdjudicatorPublicKey[G1, G2, P1, P2], error) {
+	return parse[AdjudicatorPublicKey[G1, G2, P1, P2]](parseBase64(str))
+}
+
+// Hex returns the hex encoding of sig
+func (sig *Signature[G2, P2]) Hex() string {
+	return hex.EncodeToString(sig.Marshal())
+}
+
+// Base64 returns the base64 encoding of sig
+func (sig *Signature[G2, P2]) Base64() string {
+	return b64.EncodeToString(sig.Marshal())
+}
+
+// ParseSignatureHex decodes a signature encoded by Hex
+func (s *Scheme[G1, G2, P1, P2]) ParseSignatureHex(str string) (*Signature[G2, P2], error) {
+	return parse[Signature[G2, P2]](parseHex(str))
+}
+
+// ParseSignatureBase64 decodes a signature encoded by Base64
+func (s *Scheme[G1, G2, P1, P2]) ParseSignatureBase64(str string) (*Signature[G2, P2], error) {
+	return parse[Signature[G2, P2]](parseBase64(str))
+}
+
+// Hex returns the hex encoding of sig
+func (sig *VESig[G2, P2]) Hex() string {
+	return hex.EncodeToString(sig.Marshal())
+}
+
+// Base64 returns the base64 encoding of sig
+func (sig *VESig[G2, P2]) Base64() string {
+	return b64.EncodeToString(sig.Marshal())
+}
+
+// ParseVESigHex decodes a VESig encoded by Hex
+func (s *Scheme[G1, G2, P1, P2]) ParseVESigHex(str string) (*VESig[G2, P2], error) {
+	return parse[VESig[G2, P2]](parseHex(str))
+}
+
+// ParseVESigBase64 decodes a VESig encoded by Base64
+func (s *Scheme[G1, G2, P1, P2]) ParseVESigBase64(str string) (*VESig[G2, P2], error) {
+	return parse[VESig[G2, P2]](parseBase64(str))
+}
+
+// Hex returns the hex encoding of pa
+func (pa *Partial[G2, P2]) Hex() string {
+	return hex.EncodeToString(pa.Marshal())
+}
+
+// Base64 returns the base64 encoding of pa
+func (pa *Partial[G2, P2]) Base64() string {
+	return b64.EncodeToString(pa.Marshal())
+}
+
+// ParsePartialHex decodes a partial adjudication encoded by Hex
+func (s *Scheme[G1, G2, P1, P2]) ParsePartialHex(str string) (*Partial[G2, P2], error) {
+	return parse[Partial[G2, P2]](parseHex(str))
+}
+
+// ParsePartialBase64 decodes a partial adjudication encoded by Base64
+func (s *Scheme[G1, G2, P1, P2]) ParsePartialBase64(str string) (*Partial[G2, P2], error) {
+	return parse[Partial[G2, P2]](parseBase64(str))
+}
@@ -0,0 +1,71 @@
+package scheme
+
+import (
+	"crypto/x509"
+	"errors"
+)
+
+var ErrInvalidSVID = errors.New("invalid X.509-SVID")
+
+// SPIFFEID returns the SPIFFE ID of an X.509-SVID leaf: its only URI SAN,
+// of the form spiffe://<trust domain>/<path>
+func SPIFFEID(cert *x509.Certificate) (string, error) {
+	if cert.IsCA || len(cert.URIs) != 1 {
+		return "", ErrInvalidSVID
+	}
+	u := cert.URIs[0]
+	if u.Scheme != "spiffe" || u.Host == "" || u.Port() != "" || u.User != nil ||
+		u.RawQuery != "" || u.Fragment != "" {
+		return "", ErrInvalidSVID
+	}
+	return u.String(), nil
+}
+
+// MemberBySVID returns the member whose ID is the SPIFFE ID of cert. The
+// chain of cert is not verified, see VerifyPeerSVID
+func (c *Committee[G1, P1]) MemberBySVID(cert *x509.Certificate) (Member, error) {
+	id, err := SPIFFEID(cert)
+	if err != nil {
+		return Member{}, err
+	}
+	for _, m := range c.Members {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return Member{}, ErrNotMember
+}
+
+// VerifyPeerSVID returns a tls.Config VerifyPeerCertificate callback
+// accepting only committee members, with SVIDs chaining to the trust bundle
+// roots. As SVIDs carry no DNS names, it replaces the default verification:
+// set InsecureSkipVerify on clients, and ClientAuth to
+// RequireAnyClientCert on servers
+func (c *Committee[G1, P1]) VerifyPeerSVID(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(raw [][]byte, _ [][]*x509.Certificate) error {
+		if len(raw) == 0 {
+			return ErrInvalidSVID
+		}
+		certs := make([]*x509.Certificate, len(raw))
+		for i, b := range raw {
+			cert, err := x509.ParseCertificate(b)
+			if err != nil {
+				return err
+			}
+			certs[i] = cert
+		}
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: x509.NewCertPool(),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		if _, err := certs[0].Verify(opts); err != nil {
+			return err
+		}
+		_, err := c.MemberBySVID(certs[0])
+		return err
+	}
+}
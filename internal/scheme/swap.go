@@ -0,0 +1,249 @@
+package scheme
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	ErrInvalidSwap  = errors.New("invalid swap")
+	ErrSwapExpired  = errors.New("swap deadline passed")
+	ErrNotSwapParty = errors.New("key is not a party to the swap")
+)
+
+const swapTag = "VESS-SWAP-V1"
+
+// Swap is an exchange of signatures on MsgA, by A, and MsgB, by B, each
+// escrowed to an adjudicator, possibly the same one. It runs optimistically:
+//
+//  1. A and B exchange SigA and SigB, and both countersign the swap
+//  2. Each checks VerifySwap, then releases its plain signature
+//  3. A party that released its signature without receiving the other one
+//     calls on the other's adjudicator before Deadline, see ResolveSwap
+//
+// The adjudicator opens the missing escrow only against the caller's own
+// plain signature, which it forwards to the other party, so either both
+// signatures become available or neither does. Past Deadline no escrow of
+// the swap is opened: that is the refund path
+type Swap[G1, G2 any, P1 Point[G1], P2 Point[G2]] struct {
+	A, B       *PublicKey[G1, P1]
+	AdjA, AdjB *AdjudicatorPublicKey[G1, G2, P1, P2]
+	MsgA, MsgB []byte
+	SigA, SigB *VESig[G2, P2]
+	// Unix time
+	Deadline uint64
+
+	// Countersignatures
+	csA, csB *schnorrSig[G1, P1]
+}
+
+// swapMessage is the countersigned part of the swap
+func (s *Scheme[G1, G2, P1, P2]) swapMessage(sw *Swap[G1, G2, P1, P2]) []byte {
+	b := []byte{byte(s.Suite)}
+	b = s.AppendPublicKey(b, sw.A)
+	b = s.AppendPublicKey(b, sw.B)
+	b = s.AppendAdjudicatorPublicKey(b, sw.AdjA)
+	b = s.AppendAdjudicatorPublicKey(b, sw.AdjB)
+	for _, m := range [][]byte{sw.MsgA, sw.MsgB, s.AppendVESig(nil, sw.SigA), s.AppendVESig(nil, sw.SigB)} {
+		d := sha256.Sum256(m)
+		b = append(b, d[:]...)
+	}
+	n := len(b)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[n:], sw.Deadline)
+	return b
+}
+
+func (sw *Swap[G1, G2, P1, P2]) complete() bool {
+	return sw.A != nil && sw.B != nil && sw.AdjA != nil && sw.AdjB != nil &&
+		sw.SigA != nil && sw.SigB != nil
+}
+
+// CountersignSwap adds the countersignature of the party owning sk
+func (s *Scheme[G1, G2, P1, P2]) CountersignSwap(sk *SecretKey, sw *Swap[G1, G2, P1, P2]) error {
+	if !sw.complete() {
+		return ErrInvalidSwap
+	}
+	pk := s.PublicKey(sk)
+	if !pk.Equal(sw.A) && !pk.Equal(sw.B) {
+		return ErrNotSwapParty
+	}
+	cs, err := s.schnorrSign(swapTag, sk, s.swapMessage(sw))
+	if err != nil {
+		return err
+	}
+	if pk.Equal(sw.A) {
+		sw.csA = cs
+	} else {
+		sw.csB = cs
+	}
+	return nil
+}
+
+// VerifySwap checks both escrows and both countersignatures
+func (s *Scheme[G1, G2, P1, P2]) VerifySwap(sw *Swap[G1, G2, P1, P2]) error {
+	if !sw.complete() || sw.csA == nil || sw.csB == nil {
+		return ErrInvalidSwap
+	}
+	m := s.swapMessage(sw)
+	if !s.schnorrVerify(swapTag, &sw.A.p, m, sw.csA) ||
+		!s.schnorrVerify(swapTag, &sw.B.p, m, sw.csB) {
+		return ErrInvalidSwap
+	}
+	for _, e := range []struct {
+		pk  *PublicKey[G1, P1]
+		adj *AdjudicatorPublicKey[G1, G2, P1, P2]
+		msg []byte
+		sig *VESig[G2, P2]
+	}{{sw.A, sw.AdjA, sw.MsgA, sw.SigA}, {sw.B, sw.AdjB, sw.MsgB, sw.SigB}} {
+		ok, err := s.Verify(e.pk, e.adj, e.msg, e.sig)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidSwap
+		}
+	}
+	return nil
+}
+
+// ResolveSwap is run by an adjudicator of the swap at time now, for a party
+// that released its plain signature, released, without receiving the other
+// one. It returns the other party's signature. The adjudicator must forward
+// released to the other party
+func (s *Scheme[G1, G2, P1, P2]) ResolveSwap(adjSK *SecretKey, sw *Swap[G1, G2, P1, P2], released *Signature[G2, P2], now uint64) (*Signature[G2, P2], error) {
+	if err := s.VerifySwap(sw); err != nil {
+		return nil, err
+	}
+	if now > sw.Deadline {
+		return nil, ErrSwapExpired
+	}
+
+	// Open the escrow of the party that did not release: B released if it
+	// signed MsgB, otherwise A must have signed MsgA
+	adj := s.AdjudicatorPublicKey(adjSK)
+	if ok, err := s.VerifyRecovered(sw.B, sw.MsgB, released); err != nil {
+		return nil, err
+	} else if ok && adj.Equal(sw.AdjA) {
+		return s.VerifyAndAdjudicate(adjSK, sw.A, sw.MsgA, sw.SigA)
+	}
+	if ok, err := s.VerifyRecovered(sw.A, sw.MsgA, released); err != nil {
+		return nil, err
+	} else if ok && adj.Equal(sw.AdjB) {
+		return s.VerifyAndAdjudicate(adjSK, sw.B, sw.MsgB, sw.SigB)
+	}
+	return nil, ErrInvalidSignature
+}
+
+// Countersignatures present in an encoded swap
+const (
+	swapHasCSA = 1 << iota
+	swapHasCSB
+)
+
+// MarshalSwap encodes a swap along with the countersignatures it has so
+// far, so a partly countersigned swap can be sent to the other party
+func (s *Scheme[G1, G2, P1, P2]) MarshalSwap(sw *Swap[G1, G2, P1, P2]) []byte {
+	b := []byte{byte(s.Suite)}
+	b = s.AppendPublicKey(b, sw.A)
+	b = s.AppendPublicKey(b, sw.B)
+	b = s.AppendAdjudicatorPublicKey(b, sw.AdjA)
+	b = s.AppendAdjudicatorPublicKey(b, sw.AdjB)
+	b = s.AppendVESig(b, sw.SigA)
+	b = s.AppendVESig(b, sw.SigB)
+	n := len(b)
+	b = append(b, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[n:], sw.Deadline)
+	for _, m := range [][]byte{sw.MsgA, sw.MsgB} {
+		n := len(b)
+		b = append(b, make([]byte, 4)...)
+		binary.BigEndian.PutUint32(b[n:], uint32(len(m)))
+		b = append(b, m...)
+	}
+	flags := byte(0)
+	if sw.csA != nil {
+		flags |= swapHasCSA
+	}
+	if sw.csB != nil {
+		flags |= swapHasCSB
+	}
+	b = append(b, flags)
+	if sw.csA != nil {
+		b = s.appendSchnorr(b, sw.csA)
+	}
+	if sw.csB != nil {
+		b = s.appendSchnorr(b, sw.csB)
+	}
+	return b
+}
+
+// UnmarshalSwap decodes a swap. Use VerifySwap to check it
+func (s *Scheme[G1, G2, P1, P2]) UnmarshalSwap(b []byte) (*Swap[G1, G2, P1, P2], error) {
+	n1 := len(P1(new(G1)).Marshal())
+	n2 := len(P2(new(G2)).Marshal())
+	if len(b) < 1+4*n1+6*n2+8 {
+		return nil, ErrInvalidLength
+	}
+	if Ciphersuite(b[0]) != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	b = b[1:]
+
+	sw := Swap[G1, G2, P1, P2]{
+		A:    &PublicKey[G1, P1]{},
+		B:    &PublicKey[G1, P1]{},
+		AdjA: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+		AdjB: &AdjudicatorPublicKey[G1, G2, P1, P2]{},
+		SigA: &VESig[G2, P2]{},
+		SigB: &VESig[G2, P2]{},
+	}
+	for _, f := range []struct {
+		n int
+		u interface{ Unmarshal([]byte) error }
+	}{{n1, sw.A}, {n1, sw.B}, {n1 + n2, sw.AdjA}, {n1 + n2, sw.AdjB}, {2 * n2, sw.SigA}, {2 * n2, sw.SigB}} {
+		if err := f.u.Unmarshal(b[:f.n]); err != nil {
+			return nil, err
+		}
+		b = b[f.n:]
+	}
+	sw.Deadline = binary.BigEndian.Uint64(b)
+	b = b[8:]
+	for _, m := range []*[]byte{&sw.MsgA, &sw.MsgB} {
+		if len(b) < 4 {
+			return nil, ErrInvalidLength
+		}
+		n := binary.BigEndian.Uint32(b)
+		b = b[4:]
+		if uint64(len(b)) < uint64(n) {
+			return nil, ErrInvalidLength
+		}
+		*m = append([]byte{}, b[:n]...)
+		b = b[n:]
+	}
+	if len(b) < 1 {
+		return nil, ErrInvalidLength
+	}
+	flags := b[0]
+	if flags&^(swapHasCSA|swapHasCSB) != 0 {
+		return nil, ErrInvalidSwap
+	}
+	b = b[1:]
+	var err error
+	if flags&swapHasCSA != 0 {
+		sw.csA = &schnorrSig[G1, P1]{}
+		if b, err = s.parseSchnorr(sw.csA, b); err != nil {
+			return nil, err
+		}
+	}
+	if flags&swapHasCSB != 0 {
+		sw.csB = &schnorrSig[G1, P1]{}
+		if b, err = s.parseSchnorr(sw.csB, b); err != nil {
+			return nil, err
+		}
+	}
+	if len(b) != 0 {
+		return nil, ErrInvalidLength
+	}
+	return &sw, nil
+}
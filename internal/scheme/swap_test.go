@@ -0,0 +1,65 @@
+package scheme
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestMarshalSwap(t *testing.T) {
+	s := newTestScheme()
+	skA, skB, adjSK := generateKey(t), generateKey(t), generateKey(t)
+	adj := s.AdjudicatorPublicKey(adjSK)
+	sw := Swap[testG1, testG2, *testG1, *testG2]{
+		A:        s.PublicKey(skA),
+		B:        s.PublicKey(skB),
+		AdjA:     adj,
+		AdjB:     adj,
+		MsgA:     []byte("pay B"),
+		MsgB:     []byte("deliver to A"),
+		Deadline: 1000,
+	}
+	var err error
+	if sw.SigA, err = s.Sign(skA, adj, sw.MsgA); err != nil {
+		t.Fatal(err)
+	}
+	if sw.SigB, err = s.Sign(skB, adj, sw.MsgB); err != nil {
+		t.Fatal(err)
+	}
+
+	// A countersigns and sends the swap to B, who countersigns the decoded
+	// swap
+	if err := s.CountersignSwap(skA, &sw); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.UnmarshalSwap(s.MarshalSwap(&sw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.csA == nil || got.csB != nil {
+		t.Fatal("countersignatures not preserved")
+	}
+	if err := s.CountersignSwap(skB, got); err != nil {
+		t.Fatal(err)
+	}
+	b := s.MarshalSwap(got)
+	got, err = s.UnmarshalSwap(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerifySwap(got); err != nil {
+		t.Fatalf("decoded swap rejected: %v", err)
+	}
+	if !bytes.Equal(s.MarshalSwap(got), b) {
+		t.Fatal("swap encoding is not stable")
+	}
+
+	for i := 0; i < len(b); i++ {
+		if _, err := s.UnmarshalSwap(b[:i]); err == nil {
+			t.Fatalf("swap truncated to %d bytes accepted", i)
+		}
+	}
+	if _, err := s.UnmarshalSwap(append(append([]byte{}, b...), 0)); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("trailing byte: got %v, want %v", err, ErrInvalidLength)
+	}
+}
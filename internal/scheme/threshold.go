@@ -0,0 +1,162 @@
+package scheme
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+var ErrInvalidThreshold = errors.New("invalid threshold parameters")
+
+// Partial is a partial adjudication mu^share, computed by a key share holder
+type Partial[G2 any, P2 Point[G2]] struct {
+	p G2
+}
+
+// Marshal returns the uncompressed encoding of the partial adjudication
+func (pa *Partial[G2, P2]) Marshal() []byte {
+	return P2(&pa.p).Marshal()
+}
+
+// Unmarshal decodes a partial adjudication
+func (pa *Partial[G2, P2]) Unmarshal(b []byte) error {
+	return unmarshalPoint[G2, P2](&pa.p, b)
+}
+
+// SplitKey splits an adjudicator secret key into n shares, any t of which
+// are enough to adjudicate (t-of-n). Share i is the evaluation of a random
+// polynomial of degree t-1 at x = i+1, whose free coefficient is sk
+// Note that we're creating the shares from a single private key
+// In a real setting, a DKG protocol would probably be used
+func (s *Scheme[G1, G2, P1, P2]) SplitKey(sk *SecretKey, t, n int) ([]*SecretKey, error) {
+	poly, err := s.polynomial(sk, t, n)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]*SecretKey, n)
+	for i := range shares {
+		shares[i] = s.evaluate(poly, i+1)
+	}
+
+	return shares, nil
+}
+
+// polynomial returns a random polynomial of degree t-1 whose free coefficient
+// is sk
+func (s *Scheme[G1, G2, P1, P2]) polynomial(sk *SecretKey, t, n int) ([]*big.Int, error) {
+	if t < 1 || n < t {
+		return nil, ErrInvalidThreshold
+	}
+
+	// The free coefficient is the original private key
+	poly := make([]*big.Int, t)
+	poly[0] = new(big.Int).Set(&sk.x)
+
+	// Random secret key coefficients
+	for i := 1; i < t; i++ {
+		c, err := rand.Int(rand.Reader, s.Order)
+		if err != nil {
+			return nil, err
+		}
+		poly[i] = c
+	}
+
+	return poly, nil
+}
+
+// evaluate evaluates the polynomial at x, using Horner's rule
+func (s *Scheme[G1, G2, P1, P2]) evaluate(poly []*big.Int, x int) *SecretKey {
+	bx := big.NewInt(int64(x))
+	share := SecretKey{}
+	for j := len(poly) - 1; j >= 0; j-- {
+		share.x.Mul(&share.x, bx)
+		share.x.Add(&share.x, poly[j])
+		share.x.Mod(&share.x, s.Order)
+	}
+	return &share
+}
+
+// PartialAdjudicate computes mu^share
+func (s *Scheme[G1, G2, P1, P2]) PartialAdjudicate(share *SecretKey, sig *VESig[G2, P2]) *Partial[G2, P2] {
+	pa := Partial[G2, P2]{}
+	P2(&pa.p).ScalarMultiplication(&sig.mu, &share.x)
+	return &pa
+}
+
+// Combine recovers the original signature from t partial adjudications.
+// indices[i] is the share index (starting at 1) of partials[i]
+func (s *Scheme[G1, G2, P1, P2]) Combine(sig *VESig[G2, P2], indices []int, partials []*Partial[G2, P2]) (*Signature[G2, P2], error) {
+	mux, err := s.combine(indices, partials)
+	if err != nil {
+		return nil, err
+	}
+
+	// Final adjudication step
+	res := Signature[G2, P2]{}
+	P2(&res.p).Sub(&sig.omega, mux)
+	return &res, nil
+}
+
+// combine recovers mu^adjKey from partial adjudications
+func (s *Scheme[G1, G2, P1, P2]) combine(indices []int, partials []*Partial[G2, P2]) (*G2, error) {
+	if len(indices) == 0 || len(indices) != len(partials) {
+		return nil, ErrInvalidThreshold
+	}
+	lambdas, err := s.lagrange(indices)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lagrange interpolation at 0 recovers mu^adjKey
+	if s.MultiExpG2 != nil {
+		points := make([]G2, len(partials))
+		for i, pa := range partials {
+			points[i] = pa.p
+		}
+		mux, err := s.MultiExpG2(points, lambdas)
+		if err != nil {
+			return nil, err
+		}
+		return &mux, nil
+	}
+
+	mux := new(G2)
+	term := new(G2)
+	for i, pa := range partials {
+		P2(term).ScalarMultiplication(&pa.p, lambdas[i])
+		P2(mux).Add(mux, term)
+	}
+	return mux, nil
+}
+
+// lagrange returns the Lagrange coefficients at 0 for the given indices
+func (s *Scheme[G1, G2, P1, P2]) lagrange(indices []int) ([]*big.Int, error) {
+	seen := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		if i < 1 || seen[i] {
+			return nil, ErrInvalidThreshold
+		}
+		seen[i] = true
+	}
+
+	// lambda_i = prod_{j != i} x_j / (x_j - x_i)
+	lambdas := make([]*big.Int, len(indices))
+	for i, xi := range indices {
+		num := big.NewInt(1)
+		den := big.NewInt(1)
+		for j, xj := range indices {
+			if i == j {
+				continue
+			}
+			num.Mul(num, big.NewInt(int64(xj)))
+			num.Mod(num, s.Order)
+			den.Mul(den, big.NewInt(int64(xj-xi)))
+			den.Mod(den, s.Order)
+		}
+		den.ModInverse(den, s.Order)
+		lambdas[i] = num.Mul(num, den).Mod(num, s.Order)
+	}
+
+	return lambdas, nil
+}
@@ -0,0 +1,138 @@
+package scheme
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// PartialVESig is a share holder's part of an escrow by a threshold signer,
+// whose key is split as with SplitKey and described by a Committee, with
+// Vector[0] the signer public key:
+//
+//	omega_i = l_i.x_i.H(m) + r_i.v', mu_i = r_i.g2
+//
+// l_i is the Lagrange coefficient of index i in the signing set. The parts
+// of the whole set sum to an escrow under the signer key, with r = sum r_i,
+// and no machine ever holds the signer key or the plain signature
+type PartialVESig[G2 any, P2 Point[G2]] struct {
+	omega G2
+	mu    G2
+}
+
+// Mu returns the r_i commitment mu_i
+func (pv *PartialVESig[G2, P2]) Mu() G2 {
+	return pv.mu
+}
+
+// Marshal returns the uncompressed encodings of omega_i and mu_i
+func (pv *PartialVESig[G2, P2]) Marshal() []byte {
+	return append(P2(&pv.omega).Marshal(), P2(&pv.mu).Marshal()...)
+}
+
+// Unmarshal decodes a partial escrow
+func (pv *PartialVESig[G2, P2]) Unmarshal(b []byte) error {
+	n := len(P2(new(G2)).Marshal())
+	if len(b) != 2*n {
+		return ErrInvalidLength
+	}
+	if err := unmarshalPoint[G2, P2](&pv.omega, b[:n]); err != nil {
+		return err
+	}
+	return unmarshalPoint[G2, P2](&pv.mu, b[n:])
+}
+
+// PartialSign returns the part of the share at index, in the signing set
+// indices, of an escrow of msg to adj
+func (s *Scheme[G1, G2, P1, P2]) PartialSign(share *SecretKey, index int, indices []int, adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte) (*PartialVESig[G2, P2], error) {
+	r, err := rand.Int(rand.Reader, s.Order)
+	if err != nil {
+		return nil, err
+	}
+	return s.PartialSignWithRandomness(share, index, indices, adj, msg, r)
+}
+
+// PartialSignWithRandomness is PartialSign with a caller-supplied r_i, such
+// as one committed to beforehand. r_i must never be reused
+func (s *Scheme[G1, G2, P1, P2]) PartialSignWithRandomness(share *SecretKey, index int, indices []int, adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, r *big.Int) (*PartialVESig[G2, P2], error) {
+	l, err := s.lagrangeOf(index, indices)
+	if err != nil {
+		return nil, err
+	}
+	h, err := s.Hash(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	pv := PartialVESig[G2, P2]{}
+	P2(&pv.mu).ScalarMultiplication(&s.G2Gen, r)
+	mask := new(G2)
+	P2(mask).ScalarMultiplication(&adj.g2, r)
+	k := new(big.Int).Mul(l, &share.x)
+	P2(&pv.omega).ScalarMultiplication(&h, k.Mod(k, s.Order))
+	P2(&pv.omega).Add(&pv.omega, mask)
+	return &pv, nil
+}
+
+// lagrangeOf returns the Lagrange coefficient of index in indices
+func (s *Scheme[G1, G2, P1, P2]) lagrangeOf(index int, indices []int) (*big.Int, error) {
+	lambdas, err := s.lagrange(indices)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := range indices {
+		if j == index {
+			return lambdas[i], nil
+		}
+	}
+	return nil, ErrNotMember
+}
+
+// VerifyPartialVESig checks the part pv of the share at index, in the
+// signing set indices:
+// e(g1, omega_i)^-1 . e(l_i.X_i, H(m)) . e(v, mu_i) == 1
+func (s *Scheme[G1, G2, P1, P2]) VerifyPartialVESig(c *Committee[G1, P1], index int, indices []int, adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, pv *PartialVESig[G2, P2]) (bool, error) {
+	l, err := s.lagrangeOf(index, indices)
+	if err != nil {
+		return false, err
+	}
+	pk, err := s.MemberPublicKey(c, index)
+	if err != nil {
+		return false, err
+	}
+	h, err := s.Hash(msg)
+	if err != nil {
+		return false, err
+	}
+	P1(&pk.p).ScalarMultiplication(&pk.p, l)
+	ng1 := new(G1)
+	P1(ng1).Neg(&s.G1Gen)
+	return s.PairingCheck(
+		[]G1{*ng1, pk.p, adj.g1},
+		[]G2{pv.omega, h, pv.mu},
+	)
+}
+
+// CombinePartialVESigs verifies the parts of every index in the signing set
+// and sums them into an escrow under the signer key c.Vector[0]
+func (s *Scheme[G1, G2, P1, P2]) CombinePartialVESigs(c *Committee[G1, P1], indices []int, adj *AdjudicatorPublicKey[G1, G2, P1, P2], msg []byte, parts []*PartialVESig[G2, P2]) (*VESig[G2, P2], error) {
+	if err := s.CheckCommittee(c); err != nil {
+		return nil, err
+	}
+	if len(indices) < c.Threshold || len(indices) != len(parts) {
+		return nil, ErrInvalidThreshold
+	}
+
+	sig := VESig[G2, P2]{}
+	for i, pv := range parts {
+		ok, err := s.VerifyPartialVESig(c, indices[i], indices, adj, msg, pv)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrInvalidPartial
+		}
+		P2(&sig.omega).Add(&sig.omega, &pv.omega)
+		P2(&sig.mu).Add(&sig.mu, &pv.mu)
+	}
+	return &sig, nil
+}
@@ -0,0 +1,375 @@
+package scheme
+
+import "errors"
+
+var (
+	ErrUnknownFormat      = errors.New("unknown wire format")
+	ErrUnsupportedVersion = errors.New("unsupported wire format version")
+	ErrSuiteMismatch      = errors.New("ciphersuite mismatch")
+	ErrKindMismatch       = errors.New("unexpected object kind")
+)
+
+// Ciphersuite identifies the curve, the group convention and the hash to
+// curve suite an object was produced with
+type Ciphersuite byte
+
+const (
+	CiphersuiteUnknown Ciphersuite = iota
+	// Public keys in G1, signatures in G2
+	CiphersuiteBLS12381G2XMDSHA256
+	CiphersuiteBLS12381G2XOFSHAKE256
+	CiphersuiteBN254G2XMDSHA256
+	CiphersuiteBLS12377G2XMDSHA256
+	// A custom DST or expand function. Both sides must agree out of band
+	CiphersuiteCustom Ciphersuite = 0xff
+)
+
+// String returns the name of the ciphersuite
+func (c Ciphersuite) String() string {
+	switch c {
+	case CiphersuiteBLS12381G2XMDSHA256:
+		return "BLS12381G2_XMD:SHA-256_SSWU_RO"
+	case CiphersuiteBLS12381G2XOFSHAKE256:
+		return "BLS12381G2_XOF:SHAKE-256_SSWU_RO"
+	case CiphersuiteBN254G2XMDSHA256:
+		return "BN254G2_XMD:SHA-256_SVDW_RO"
+	case CiphersuiteBLS12377G2XMDSHA256:
+		return "BLS12377G2_XMD:SHA-256_SVDW_RO"
+	case CiphersuiteCustom:
+		return "custom"
+	}
+	return "unknown"
+}
+
+// Ciphersuite returns the ciphersuite of the objects this scheme encodes
+func (s *Scheme[G1, G2, P1, P2]) Ciphersuite() Ciphersuite {
+	return s.Suite
+}
+
+// Kind is the type of a serialized object
+type Kind byte
+
+const (
+	KindVESig Kind = iota + 1
+	KindPartial
+	KindKeyShare
+	KindBackupShare
+	KindPublicKey
+	KindAdjudicatorPublicKey
+	KindSignature
+	KindAggregateVESig
+)
+
+// Wire format versions. Version 0 is the legacy headerless encoding, as
+// returned by Marshal. Version 2 compresses points, halving the size of
+// VESigs; objects without points are laid out as in version 1
+const (
+	Version0 byte = iota
+	Version1
+	Version2
+
+	CurrentVersion = Version2
+)
+
+// HeaderSize is the size of the version 1 and 2 headers
+const HeaderSize = 3
+
+// Header describes a serialized object
+type Header struct {
+	Version byte
+	Suite   Ciphersuite
+	Kind    Kind
+}
+
+func (h Header) append(dst []byte) []byte {
+	return append(dst, h.Version, byte(h.Suite), byte(h.Kind))
+}
+
+// sizes returns the size of each kind at version v, header excluded
+func (s *Scheme[G1, G2, P1, P2]) sizes(v byte) map[Kind]int {
+	n1, n2 := s.pointSizes(v)
+	return map[Kind]int{
+		KindVESig:                2 * n2,
+		KindPartial:              n2,
+		KindKeyShare:             keyShareSize,
+		KindBackupShare:          backupShareSize,
+		KindPublicKey:            n1,
+		KindAdjudicatorPublicKey: n1 + n2,
+		KindSignature:            n2,
+		KindAggregateVESig:       2*n2 + 4,
+	}
+}
+
+// pointSizes returns the size of G1 and G2 points at version v
+func (s *Scheme[G1, G2, P1, P2]) pointSizes(v byte) (int, int) {
+	if v == Version1 {
+		return len(s.AppendG1(nil, &s.G1Gen)), len(s.AppendG2(nil, &s.G2Gen))
+	}
+	return len(s.AppendG1Compressed(nil, &s.G1Gen)), len(s.AppendG2Compressed(nil, &s.G2Gen))
+}
+
+// Detect returns the header of a serialized object.
+// Legacy objects are told apart by their length, which never matches the
+// length of a versioned object. Their suite is unknown
+func (s *Scheme[G1, G2, P1, P2]) Detect(b []byte) (Header, error) {
+	_, n := s.pointSizes(Version1)
+	switch len(b) {
+	case 2 * n:
+		return Header{Version: Version0, Kind: KindVESig}, nil
+	case n:
+		return Header{Version: Version0, Kind: KindPartial}, nil
+	}
+	if len(b) < HeaderSize {
+		return Header{}, ErrUnknownFormat
+	}
+
+	h := Header{Version: b[0], Suite: Ciphersuite(b[1]), Kind: Kind(b[2])}
+	if h.Version != Version1 && h.Version != Version2 {
+		return h, ErrUnsupportedVersion
+	}
+	if sz, ok := s.sizes(h.Version)[h.Kind]; !ok || len(b) != HeaderSize+sz {
+		return h, ErrUnknownFormat
+	}
+	return h, nil
+}
+
+func (s *Scheme[G1, G2, P1, P2]) header(kind Kind) Header {
+	return Header{Version: CurrentVersion, Suite: s.Suite, Kind: kind}
+}
+
+// body checks the header of b and returns the version and the encoded object
+func (s *Scheme[G1, G2, P1, P2]) body(b []byte, kind Kind) (byte, []byte, error) {
+	h, err := s.Detect(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	if h.Version == Version0 {
+		return 0, nil, ErrUnsupportedVersion
+	}
+	if h.Suite != s.Suite {
+		return 0, nil, ErrSuiteMismatch
+	}
+	if h.Kind != kind {
+		return 0, nil, ErrKindMismatch
+	}
+	return h.Version, b[HeaderSize:], nil
+}
+
+// decodeG1V and decodeG2V decode a point encoded at version v, strictly
+func (s *Scheme[G1, G2, P1, P2]) decodeG1V(p *G1, b []byte, v byte) error {
+	if v == Version1 {
+		return unmarshalPoint[G1, P1](p, b)
+	}
+	return s.DecodeG1Compressed(p, b)
+}
+
+func (s *Scheme[G1, G2, P1, P2]) decodeG2V(p *G2, b []byte, v byte) error {
+	if v == Version1 {
+		return unmarshalPoint[G2, P2](p, b)
+	}
+	return s.DecodeG2Compressed(p, b)
+}
+
+// encode appends the header of kind to a buffer of the right size
+func (s *Scheme[G1, G2, P1, P2]) encode(kind Kind) []byte {
+	dst := make([]byte, 0, HeaderSize+s.sizes(CurrentVersion)[kind])
+	return s.header(kind).append(dst)
+}
+
+// EncodeVESig returns the versioned encoding of sig
+func (s *Scheme[G1, G2, P1, P2]) EncodeVESig(sig *VESig[G2, P2]) []byte {
+	dst := s.AppendG2Compressed(s.encode(KindVESig), &sig.omega)
+	return s.AppendG2Compressed(dst, &sig.mu)
+}
+
+// DecodeVESig decodes a versioned VESig, rejecting objects from other
+// ciphersuites. Use Migrate to convert legacy objects first
+func (s *Scheme[G1, G2, P1, P2]) DecodeVESig(b []byte) (*VESig[G2, P2], error) {
+	v, body, err := s.body(b, KindVESig)
+	if err != nil {
+		return nil, err
+	}
+	n := len(body) / 2
+	sig := VESig[G2, P2]{}
+	if err := s.decodeG2V(&sig.omega, body[:n], v); err != nil {
+		return nil, err
+	}
+	if err := s.decodeG2V(&sig.mu, body[n:], v); err != nil {
+		return nil, err
+	}
+	return &sig, nil
+}
+
+// EncodePartial returns the versioned encoding of pa
+func (s *Scheme[G1, G2, P1, P2]) EncodePartial(pa *Partial[G2, P2]) []byte {
+	return s.AppendG2Compressed(s.encode(KindPartial), &pa.p)
+}
+
+// DecodePartial decodes a versioned partial adjudication
+func (s *Scheme[G1, G2, P1, P2]) DecodePartial(b []byte) (*Partial[G2, P2], error) {
+	v, body, err := s.body(b, KindPartial)
+	if err != nil {
+		return nil, err
+	}
+	pa := Partial[G2, P2]{}
+	if err := s.decodeG2V(&pa.p, body, v); err != nil {
+		return nil, err
+	}
+	return &pa, nil
+}
+
+// EncodePublicKey returns the versioned encoding of pk
+func (s *Scheme[G1, G2, P1, P2]) EncodePublicKey(pk *PublicKey[G1, P1]) []byte {
+	return s.AppendG1Compressed(s.encode(KindPublicKey), &pk.p)
+}
+
+// DecodePublicKey decodes a versioned public key. Like NewPublicKey, it
+// rejects the identity
+func (s *Scheme[G1, G2, P1, P2]) DecodePublicKey(b []byte) (*PublicKey[G1, P1], error) {
+	v, body, err := s.body(b, KindPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	var p G1
+	if err := s.decodeG1V(&p, body, v); err != nil {
+		return nil, err
+	}
+	return NewPublicKey[G1, P1](p)
+}
+
+// EncodeAdjudicatorPublicKey returns the versioned encoding of apk
+func (s *Scheme[G1, G2, P1, P2]) EncodeAdjudicatorPublicKey(apk *AdjudicatorPublicKey[G1, G2, P1, P2]) []byte {
+	dst := s.AppendG1Compressed(s.encode(KindAdjudicatorPublicKey), &apk.g1)
+	return s.AppendG2Compressed(dst, &apk.g2)
+}
+
+// DecodeAdjudicatorPublicKey decodes a versioned adjudicator public key.
+// Like NewAdjudicatorPublicKey, it rejects the identity and keys whose halves
+// do not match
+func (s *Scheme[G1, G2, P1, P2]) DecodeAdjudicatorPublicKey(b []byte) (*AdjudicatorPublicKey[G1, G2, P1, P2], error) {
+	v, body, err := s.body(b, KindAdjudicatorPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	n1, _ := s.pointSizes(v)
+	var g1 G1
+	var g2 G2
+	if err := s.decodeG1V(&g1, body[:n1], v); err != nil {
+		return nil, err
+	}
+	if err := s.decodeG2V(&g2, body[n1:], v); err != nil {
+		return nil, err
+	}
+	return s.NewAdjudicatorPublicKey(g1, g2)
+}
+
+// EncodeSignature returns the versioned encoding of sig
+func (s *Scheme[G1, G2, P1, P2]) EncodeSignature(sig *Signature[G2, P2]) []byte {
+	return s.AppendG2Compressed(s.encode(KindSignature), &sig.p)
+}
+
+// DecodeSignature decodes a versioned signature. Like NewSignature, it
+// rejects the identity
+func (s *Scheme[G1, G2, P1, P2]) DecodeSignature(b []byte) (*Signature[G2, P2], error) {
+	v, body, err := s.body(b, KindSignature)
+	if err != nil {
+		return nil, err
+	}
+	var p G2
+	if err := s.decodeG2V(&p, body, v); err != nil {
+		return nil, err
+	}
+	return NewSignature[G2, P2](p)
+}
+
+// Migrate converts a serialized object to the current version. Legacy
+// objects are assumed to belong to this scheme's ciphersuite, and are decoded
+// with the given strictness. Versioned objects are decoded strictly and
+// re-encoded. Since versioned encodings are always decoded strictly, legacy
+// points outside the subgroup, which lenient decoding accepts, fail with
+// ErrInvalidPoint instead of being migrated to a blob that can't be read back
+func (s *Scheme[G1, G2, P1, P2]) Migrate(b []byte, mode Strictness) ([]byte, error) {
+	h, err := s.Detect(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.Version == Version0 {
+		switch h.Kind {
+		case KindVESig:
+			sig, err := s.UnmarshalVESig(b, mode)
+			if err != nil {
+				return nil, err
+			}
+			if !inSubGroup[G2, P2](&sig.omega) || !inSubGroup[G2, P2](&sig.mu) {
+				return nil, ErrInvalidPoint
+			}
+			return s.EncodeVESig(sig), nil
+		case KindPartial:
+			pa, err := s.UnmarshalPartial(b, mode)
+			if err != nil {
+				return nil, err
+			}
+			if !inSubGroup[G2, P2](&pa.p) {
+				return nil, ErrInvalidPoint
+			}
+			return s.EncodePartial(pa), nil
+		}
+		return nil, ErrUnknownFormat
+	}
+
+	if h.Suite != s.Suite {
+		return nil, ErrSuiteMismatch
+	}
+	switch h.Kind {
+	case KindVESig:
+		sig, err := s.DecodeVESig(b)
+		if err != nil {
+			return nil, err
+		}
+		return s.EncodeVESig(sig), nil
+	case KindPartial:
+		pa, err := s.DecodePartial(b)
+		if err != nil {
+			return nil, err
+		}
+		return s.EncodePartial(pa), nil
+	case KindKeyShare:
+		ks := KeyShare{}
+		if err := ks.UnmarshalBinary(b); err != nil {
+			return nil, err
+		}
+		return ks.MarshalBinary()
+	case KindBackupShare:
+		bs := BackupShare{}
+		if err := bs.UnmarshalBinary(b); err != nil {
+			return nil, err
+		}
+		return bs.MarshalBinary()
+	case KindPublicKey:
+		pk, err := s.DecodePublicKey(b)
+		if err != nil {
+			return nil, err
+		}
+		return s.EncodePublicKey(pk), nil
+	case KindAdjudicatorPublicKey:
+		apk, err := s.DecodeAdjudicatorPublicKey(b)
+		if err != nil {
+			return nil, err
+		}
+		return s.EncodeAdjudicatorPublicKey(apk), nil
+	case KindSignature:
+		sig, err := s.DecodeSignature(b)
+		if err != nil {
+			return nil, err
+		}
+		return s.EncodeSignature(sig), nil
+	case KindAggregateVESig:
+		agg, err := s.DecodeAggregateVESig(b)
+		if err != nil {
+			return nil, err
+		}
+		return s.EncodeAggregateVESig(agg), nil
+	}
+	return nil, ErrKindMismatch
+}
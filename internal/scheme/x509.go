@@ -0,0 +1,60 @@
+package scheme
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+)
+
+var ErrNoAdjudicatorExtension = errors.New("certificate has no adjudicator key extension")
+
+// adjudicatorExtension is the DER value of the certificate extension
+type adjudicatorExtension struct {
+	Suite       int
+	Adjudicator []byte
+}
+
+// AdjudicatorExtension returns a non-critical certificate extension
+// carrying adj, for a CA to issue. There is no registered OID: oid is
+// allocated by the organization, under its own arc
+func (s *Scheme[G1, G2, P1, P2]) AdjudicatorExtension(oid asn1.ObjectIdentifier, adj *AdjudicatorPublicKey[G1, G2, P1, P2]) (pkix.Extension, error) {
+	v, err := asn1.Marshal(adjudicatorExtension{int(s.Suite), s.AppendAdjudicatorPublicKey(nil, adj)})
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+	return pkix.Extension{Id: oid, Value: v}, nil
+}
+
+// AdjudicatorFromCertificate verifies the chain of leaf with opts, and
+// returns the adjudicator key in its oid extension
+func (s *Scheme[G1, G2, P1, P2]) AdjudicatorFromCertificate(oid asn1.ObjectIdentifier, leaf *x509.Certificate, opts x509.VerifyOptions) (*AdjudicatorPublicKey[G1, G2, P1, P2], error) {
+	if _, err := leaf.Verify(opts); err != nil {
+		return nil, err
+	}
+	for _, e := range leaf.Extensions {
+		if !e.Id.Equal(oid) {
+			continue
+		}
+		v := adjudicatorExtension{}
+		if rest, err := asn1.Unmarshal(e.Value, &v); err != nil || len(rest) != 0 {
+			return nil, ErrNoAdjudicatorExtension
+		}
+		if Ciphersuite(v.Suite) != s.Suite {
+			return nil, ErrSuiteMismatch
+		}
+		adj := AdjudicatorPublicKey[G1, G2, P1, P2]{}
+		if err := adj.Unmarshal(v.Adjudicator); err != nil {
+			return nil, err
+		}
+		ok, err := s.CheckAdjudicatorPublicKey(&adj)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrInvalidPoint
+		}
+		return &adj, nil
+	}
+	return nil, ErrNoAdjudicatorExtension
+}
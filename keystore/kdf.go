@@ -0,0 +1,163 @@
+package keystore
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KDF derives the 32-byte decryption key from a normalized password. Its
+// JSON encoding is the params object of the keystore kdf module
+type KDF interface {
+	// Function is the name of the kdf module, such as "scrypt"
+	Function() string
+	DeriveKey(password []byte) ([]byte, error)
+}
+
+var (
+	kdfMu sync.RWMutex
+	kdfs  = map[string]func() KDF{
+		"scrypt":   func() KDF { return &Scrypt{} },
+		"pbkdf2":   func() KDF { return &PBKDF2{} },
+		"argon2id": func() KDF { return &Argon2id{} },
+	}
+)
+
+// RegisterKDF makes a KDF available to Decrypt, under its function name.
+// newKDF returns an empty KDF to decode the params into
+func RegisterKDF(function string, newKDF func() KDF) {
+	kdfMu.Lock()
+	defer kdfMu.Unlock()
+	kdfs[function] = newKDF
+}
+
+func lookupKDF(function string) (KDF, error) {
+	kdfMu.RLock()
+	defer kdfMu.RUnlock()
+	f, ok := kdfs[function]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupported, function)
+	}
+	return f(), nil
+}
+
+// hexBytes is a byte string encoded as hex in JSON
+type hexBytes []byte
+
+func (b hexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(b))
+}
+
+func (b *hexBytes) UnmarshalJSON(data []byte) error {
+	s := ""
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	d, err := hex.DecodeString(s)
+	*b = d
+	return err
+}
+
+func randomSalt() (hexBytes, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// Limits on decoded parameters, so that a keystore cannot make Decrypt
+// exhaust memory or time
+const (
+	maxScryptN      = 1 << 20
+	maxPBKDF2Rounds = 1 << 24
+	maxArgon2Memory = 4 << 20 // KiB
+	maxArgon2Time   = 64
+)
+
+var errParams = errors.New("keystore: KDF parameters out of range")
+
+// Scrypt is the scrypt kdf module of EIP-2335
+type Scrypt struct {
+	DKLen int      `json:"dklen"`
+	N     int      `json:"n"`
+	P     int      `json:"p"`
+	R     int      `json:"r"`
+	Salt  hexBytes `json:"salt"`
+}
+
+// NewScrypt returns the scrypt KDF with the EIP-2335 parameters and a
+// random salt
+func NewScrypt() (*Scrypt, error) {
+	salt, err := randomSalt()
+	return &Scrypt{DKLen: 32, N: 1 << 18, P: 1, R: 8, Salt: salt}, err
+}
+
+func (k *Scrypt) Function() string { return "scrypt" }
+
+func (k *Scrypt) DeriveKey(password []byte) ([]byte, error) {
+	if k.DKLen != 32 || k.N > maxScryptN || k.R < 1 || k.R > 32 || k.P < 1 || k.P > 16 {
+		return nil, errParams
+	}
+	return scrypt.Key(password, k.Salt, k.N, k.R, k.P, k.DKLen)
+}
+
+// PBKDF2 is the pbkdf2 kdf module of EIP-2335, with HMAC-SHA256
+type PBKDF2 struct {
+	DKLen int      `json:"dklen"`
+	C     int      `json:"c"`
+	PRF   string   `json:"prf"`
+	Salt  hexBytes `json:"salt"`
+}
+
+// NewPBKDF2 returns the pbkdf2 KDF with the EIP-2335 parameters and a
+// random salt
+func NewPBKDF2() (*PBKDF2, error) {
+	salt, err := randomSalt()
+	return &PBKDF2{DKLen: 32, C: 262144, PRF: "hmac-sha256", Salt: salt}, err
+}
+
+func (k *PBKDF2) Function() string { return "pbkdf2" }
+
+func (k *PBKDF2) DeriveKey(password []byte) ([]byte, error) {
+	if k.DKLen != 32 || k.PRF != "hmac-sha256" || k.C < 1 || k.C > maxPBKDF2Rounds {
+		return nil, errParams
+	}
+	return pbkdf2.Key(password, k.Salt, k.C, k.DKLen, sha256.New), nil
+}
+
+// Argon2id is a kdf module beyond EIP-2335, for deployments that mandate a
+// memory-hard KDF other than scrypt. Memory is in KiB
+type Argon2id struct {
+	DKLen   int      `json:"dklen"`
+	Memory  uint32   `json:"m"`
+	Time    uint32   `json:"t"`
+	Threads uint8    `json:"p"`
+	Salt    hexBytes `json:"salt"`
+}
+
+// NewArgon2id returns the argon2id KDF with the given cost and a random
+// salt. RFC 9106 recommends 2 GiB of memory and one pass, or 64 MiB and
+// three passes when memory is constrained
+func NewArgon2id(memory, time uint32, threads uint8) (*Argon2id, error) {
+	salt, err := randomSalt()
+	return &Argon2id{DKLen: 32, Memory: memory, Time: time, Threads: threads, Salt: salt}, err
+}
+
+func (k *Argon2id) Function() string { return "argon2id" }
+
+func (k *Argon2id) DeriveKey(password []byte) ([]byte, error) {
+	if k.DKLen != 32 || k.Memory < 8*uint32(k.Threads) || k.Memory > maxArgon2Memory ||
+		k.Time < 1 || k.Time > maxArgon2Time || k.Threads < 1 || len(k.Salt) < 16 {
+		return nil, errParams
+	}
+	return argon2.IDKey(password, k.Salt, k.Time, k.Memory, k.Threads, uint32(k.DKLen)), nil
+}
@@ -0,0 +1,173 @@
+// Package keystore encrypts secret keys in EIP-2335 keystores, with a
+// pluggable KDF: scrypt and pbkdf2 as in the EIP, argon2id, or any KDF
+// registered with RegisterKDF
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrUnsupported = errors.New("keystore: unsupported module")
+	ErrPassword    = errors.New("keystore: password must be valid UTF-8")
+	ErrChecksum    = errors.New("keystore: wrong password or corrupted keystore")
+	ErrInvalid     = errors.New("keystore: invalid keystore")
+)
+
+// Version is the EIP-2335 keystore version
+const Version = 4
+
+// Module is a crypto module of the keystore
+type Module struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  hexBytes        `json:"message"`
+}
+
+// Keystore is an EIP-2335 keystore, encoded as JSON
+type Keystore struct {
+	Crypto struct {
+		KDF      Module `json:"kdf"`
+		Checksum Module `json:"checksum"`
+		Cipher   Module `json:"cipher"`
+	} `json:"crypto"`
+	Description string   `json:"description"`
+	Pubkey      hexBytes `json:"pubkey"`
+	Path        string   `json:"path"`
+	UUID        string   `json:"uuid"`
+	Version     int      `json:"version"`
+}
+
+type cipherParams struct {
+	IV hexBytes `json:"iv"`
+}
+
+// normalize returns the NFKD form of password without its C0, C1 and
+// Delete control codes, as EIP-2335 requires
+func normalize(password string) ([]byte, error) {
+	if !utf8.ValidString(password) {
+		return nil, ErrPassword
+	}
+	res := make([]byte, 0, len(password))
+	for _, r := range norm.NFKD.String(password) {
+		if r >= 0x20 && !(r >= 0x7f && r <= 0x9f) {
+			res = utf8.AppendRune(res, r)
+		}
+	}
+	return res, nil
+}
+
+// Encrypt returns a keystore of secret, the encoding of a secret key whose
+// public key is pubkey, encrypted under a key derived from password by kdf
+func Encrypt(secret, pubkey []byte, password string, kdf KDF) (*Keystore, error) {
+	pw, err := normalize(password)
+	if err != nil {
+		return nil, err
+	}
+	dk, err := kdf.DeriveKey(pw)
+	if err != nil {
+		return nil, err
+	}
+
+	ks := Keystore{Pubkey: pubkey, Version: Version}
+	if ks.UUID, err = newUUID(); err != nil {
+		return nil, err
+	}
+	ks.Crypto.KDF.Function = kdf.Function()
+	if ks.Crypto.KDF.Params, err = json.Marshal(kdf); err != nil {
+		return nil, err
+	}
+	ks.Crypto.KDF.Message = hexBytes{}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	ct, err := aes128CTR(dk[:16], iv, secret)
+	if err != nil {
+		return nil, err
+	}
+	ks.Crypto.Cipher.Function = "aes-128-ctr"
+	ks.Crypto.Cipher.Params, _ = json.Marshal(cipherParams{iv})
+	ks.Crypto.Cipher.Message = ct
+
+	sum := checksum(dk, ct)
+	ks.Crypto.Checksum.Function = "sha256"
+	ks.Crypto.Checksum.Params = json.RawMessage("{}")
+	ks.Crypto.Checksum.Message = sum[:]
+	return &ks, nil
+}
+
+// Decrypt returns the secret key encoding stored in ks
+func Decrypt(ks *Keystore, password string) ([]byte, error) {
+	if ks.Version != Version {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupported, ks.Version)
+	}
+	if ks.Crypto.Checksum.Function != "sha256" {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupported, ks.Crypto.Checksum.Function)
+	}
+	if ks.Crypto.Cipher.Function != "aes-128-ctr" {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupported, ks.Crypto.Cipher.Function)
+	}
+	cp := cipherParams{}
+	if err := json.Unmarshal(ks.Crypto.Cipher.Params, &cp); err != nil || len(cp.IV) != aes.BlockSize {
+		return nil, ErrInvalid
+	}
+	kdf, err := lookupKDF(ks.Crypto.KDF.Function)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(ks.Crypto.KDF.Params, kdf); err != nil {
+		return nil, ErrInvalid
+	}
+
+	pw, err := normalize(password)
+	if err != nil {
+		return nil, err
+	}
+	dk, err := kdf.DeriveKey(pw)
+	if err != nil {
+		return nil, err
+	}
+	ct := ks.Crypto.Cipher.Message
+	sum := checksum(dk, ct)
+	if subtle.ConstantTimeCompare(sum[:], ks.Crypto.Checksum.Message) != 1 {
+		return nil, ErrChecksum
+	}
+	return aes128CTR(dk[:16], cp.IV, ct)
+}
+
+func checksum(dk, ct []byte) [32]byte {
+	return sha256.Sum256(append(append([]byte{}, dk[16:32]...), ct...))
+}
+
+func aes128CTR(key, iv, in []byte) ([]byte, error) {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(b, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() (string, error) {
+	u := make([]byte, 16)
+	if _, err := rand.Read(u); err != nil {
+		return "", err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
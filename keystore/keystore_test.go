@@ -0,0 +1,68 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The test vectors of EIP-2335, in testdata
+const (
+	vectorPassword = "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+	vectorSecret   = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+)
+
+func load(t *testing.T, name string) *Keystore {
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := &Keystore{}
+	if err := json.Unmarshal(b, ks); err != nil {
+		t.Fatal(err)
+	}
+	return ks
+}
+
+func TestVectors(t *testing.T) {
+	want, _ := hex.DecodeString(vectorSecret)
+	for _, name := range []string{"scrypt.json", "pbkdf2.json"} {
+		ks := load(t, name)
+		secret, err := Decrypt(ks, vectorPassword)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(secret, want) {
+			t.Fatalf("%s: got %x", name, secret)
+		}
+		if _, err := Decrypt(ks, "testpassword"); !errors.Is(err, ErrChecksum) {
+			t.Fatalf("%s: got %v, want %v", name, err, ErrChecksum)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for _, c := range []struct{ password, want string }{
+		// The EIP-2335 example: NFKD maps the fraktur letters to ASCII
+		{vectorPassword, "testpassword\U0001f511"},
+		{"ascii", "ascii"},
+		// C0, Delete and C1 control codes are stripped
+		{"a\x00b\tc\x1fd\x7fe\u0080f\u009fg", "abcdefg"},
+		// Decomposed, and compatibility characters replaced
+		{"\u00e9", "e\u0301"},
+		{"\ufb01", "fi"},
+		{"\u00a0", " "},
+	} {
+		got, err := normalize(c.password)
+		if err != nil || string(got) != c.want {
+			t.Errorf("%q: got %q, %v, want %q", c.password, got, err, c.want)
+		}
+	}
+	if _, err := normalize("\xff"); !errors.Is(err, ErrPassword) {
+		t.Fatalf("got %v, want %v", err, ErrPassword)
+	}
+}
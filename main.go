@@ -1,9 +1,227 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/poupas/bls-vess/audit"
+	"github.com/poupas/bls-vess/bench"
+	"github.com/poupas/bls-vess/ceremony"
+	"github.com/poupas/bls-vess/config"
+	"github.com/poupas/bls-vess/dr"
+	"github.com/poupas/bls-vess/policy"
 	"github.com/poupas/bls-vess/vess"
+	"github.com/poupas/bls-vess/wallet"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		results, err := bench.Run()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		bench.Print(os.Stdout, results)
+		return
+	}
+
+	// bls-vess config check [file]. The file defaults to $VESS_CONFIG
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "check" {
+		path := os.Getenv("VESS_CONFIG")
+		if len(os.Args) > 3 {
+			path = os.Args[3]
+		}
+		c, err := config.Load(path)
+		if err == nil {
+			err = c.Check()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println("ok")
+		return
+	}
+
+	// bls-vess dr reconstruct [flags], see package dr
+	if len(os.Args) > 2 && os.Args[1] == "dr" && os.Args[2] == "reconstruct" {
+		c, err := config.Load(os.Getenv("VESS_CONFIG"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		v, err := c.VESS()
+		if err == nil {
+			audit := log.New(os.Stderr, "audit: ", log.LstdFlags|log.LUTC)
+			err = dr.Reconstruct(v, os.Args[3:], os.Stdin, os.Stdout, audit)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// bls-vess audit verify <log> <hex Ed25519 public key>
+	if len(os.Args) == 5 && os.Args[1] == "audit" && os.Args[2] == "verify" {
+		if err := verifyAudit(os.Args[3], os.Args[4]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// bls-vess audit export <log> json|cef
+	if len(os.Args) == 5 && os.Args[1] == "audit" && os.Args[2] == "export" {
+		f, err := os.Open(os.Args[3])
+		if err == nil {
+			err = audit.Export(os.Stdout, f, os.Args[4])
+			f.Close()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// bls-vess policy simulate <log> <quorum rules>
+	if len(os.Args) == 5 && os.Args[1] == "policy" && os.Args[2] == "simulate" {
+		if err := simulatePolicy(os.Args[3], os.Args[4]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// bls-vess ceremony manifest <dir> <artifact>... | verify <manifest> <dir>
+	if len(os.Args) > 4 && os.Args[1] == "ceremony" {
+		if err := ceremonyCommand(os.Args[2], os.Args[3], os.Args[4:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// bls-vess wallet list|export json|csv|mark <digest> <adjudicator> <status>.
+	// The wallet file is set by the configuration, see package config
+	if len(os.Args) > 2 && os.Args[1] == "wallet" {
+		if err := walletCommand(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	vess.Test()
 }
+
+func simulatePolicy(logPath, rulesPath string) error {
+	rules, err := os.Open(rulesPath)
+	if err != nil {
+		return err
+	}
+	defer rules.Close()
+	q, err := policy.Parse(rules)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(logPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	changes, n, err := policy.Replay(f, q)
+	if err != nil {
+		return err
+	}
+	policy.PrintChanges(os.Stdout, changes, n)
+	return nil
+}
+
+func ceremonyCommand(cmd, path string, args []string) error {
+	switch {
+	case cmd == "manifest":
+		m, err := ceremony.NewManifest(path, args)
+		if err != nil {
+			return err
+		}
+		b, err := m.Marshal()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(b)
+		return err
+	case cmd == "verify" && len(args) == 1:
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		m, err := ceremony.Unmarshal(b)
+		if err != nil {
+			return err
+		}
+		if err := m.Verify(args[0]); err != nil {
+			return err
+		}
+		digest, err := m.Digest()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("ok %x\n", digest)
+		return nil
+	}
+	return errors.New("usage: bls-vess ceremony manifest <dir> <artifact>...|verify <manifest> <dir>")
+}
+
+func walletCommand(args []string) error {
+	c, err := config.Load(os.Getenv("VESS_CONFIG"))
+	if err != nil {
+		return err
+	}
+	if c.Wallet == "" {
+		return errors.New("no wallet configured, set VESS_WALLET")
+	}
+	w, err := wallet.Open(c.Wallet)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		wallet.Print(os.Stdout, w.List(time.Now()))
+		return nil
+	case len(args) == 2 && args[0] == "export":
+		return wallet.Export(os.Stdout, w.List(time.Now()), args[1])
+	case len(args) == 4 && args[0] == "mark":
+		status, err := wallet.ParseStatus(args[3])
+		if err != nil {
+			return err
+		}
+		return w.SetStatus(args[1], args[2], status)
+	}
+	return errors.New("usage: bls-vess wallet list|export json|csv|mark <digest> <adjudicator> released|adjudicated")
+}
+
+func verifyAudit(path, pubHex string) error {
+	pub, err := hex.DecodeString(pubHex)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return errors.New("invalid public key")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	last, checkpoint, err := audit.Verify(f, pub)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("last entry %d %x\n", last.Seq, last.Hash)
+	fmt.Printf("last checkpoint %d %x\n", checkpoint.Seq, checkpoint.Hash)
+	return nil
+}
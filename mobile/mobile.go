@@ -0,0 +1,123 @@
+// Package mobile exposes the BLS12-381 scheme to gomobile
+//
+// gomobile only binds a subset of Go types, so every object is passed as a
+// byte slice, using the same encodings as package vess:
+//
+//	gomobile bind -target=android github.com/poupas/bls-vess/mobile
+//	gomobile bind -target=ios github.com/poupas/bls-vess/mobile
+package mobile
+
+import (
+	"sync"
+
+	"github.com/poupas/bls-vess/vess"
+)
+
+var (
+	v       *vess.VESS
+	initErr error
+	once    sync.Once
+)
+
+func instance() (*vess.VESS, error) {
+	once.Do(func() {
+		v, initErr = vess.New()
+	})
+	return v, initErr
+}
+
+// GenerateKey returns a random secret key
+func GenerateKey() ([]byte, error) {
+	sk, err := vess.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	return sk.Marshal(), nil
+}
+
+// PublicKey returns the signer public key for sk
+func PublicKey(sk []byte) ([]byte, error) {
+	v, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	s, err := v.SecretKeyFromBytes(sk)
+	if err != nil {
+		return nil, err
+	}
+	return v.PublicKey(s).Marshal(), nil
+}
+
+// AdjudicatorPublicKey returns the adjudicator public key for sk
+func AdjudicatorPublicKey(sk []byte) ([]byte, error) {
+	v, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	s, err := v.SecretKeyFromBytes(sk)
+	if err != nil {
+		return nil, err
+	}
+	return v.AdjudicatorPublicKey(s).Marshal(), nil
+}
+
+// Sign creates a verifiably encrypted signature on msg, to the adjudicator
+// public key apk
+func Sign(sk, apk, msg []byte) ([]byte, error) {
+	v, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	s, err := v.SecretKeyFromBytes(sk)
+	if err != nil {
+		return nil, err
+	}
+	adj := vess.AdjudicatorPublicKey{}
+	if err := adj.Unmarshal(apk); err != nil {
+		return nil, err
+	}
+	sig, err := v.Sign(s, &adj, msg)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Marshal(), nil
+}
+
+// Verify checks a verifiably encrypted signature on msg
+func Verify(pk, apk, msg, vesig []byte) (bool, error) {
+	v, err := instance()
+	if err != nil {
+		return false, err
+	}
+	p := vess.PublicKey{}
+	if err := p.Unmarshal(pk); err != nil {
+		return false, err
+	}
+	adj := vess.AdjudicatorPublicKey{}
+	if err := adj.Unmarshal(apk); err != nil {
+		return false, err
+	}
+	sig := vess.VESig{}
+	if err := sig.Unmarshal(vesig); err != nil {
+		return false, err
+	}
+	return v.Verify(&p, &adj, msg, &sig)
+}
+
+// Adjudicate recovers the original signature from a verifiably encrypted
+// signature
+func Adjudicate(adjSK, vesig []byte) ([]byte, error) {
+	v, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	s, err := v.SecretKeyFromBytes(adjSK)
+	if err != nil {
+		return nil, err
+	}
+	sig := vess.VESig{}
+	if err := sig.Unmarshal(vesig); err != nil {
+		return nil, err
+	}
+	return v.Adjudicate(s, &sig).Marshal(), nil
+}
@@ -0,0 +1,190 @@
+// Package pin detects adjudicator key substitution on the signer side. A
+// Store pins, on first use, the adjudicator key fingerprint and bundle
+// publisher of each adjudicator name, and later bundles must match. A
+// RevocationList, signed by the publisher, revokes adjudicator keys before
+// their bundles expire
+package pin
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+var (
+	ErrPinMismatch           = errors.New("pin: adjudicator does not match the pinned one")
+	ErrRevoked               = errors.New("pin: adjudicator key revoked")
+	ErrInvalidRevocationList = errors.New("pin: invalid revocation list")
+	ErrStaleRevocationList   = errors.New("pin: revocation list past its next update")
+)
+
+// Pin is what is remembered of an adjudicator
+type Pin struct {
+	Adjudicator string `json:"adjudicator"`
+	Publisher   string `json:"publisher"`
+	FirstSeen   int64  `json:"first_seen"`
+}
+
+// Store is a file of pins, by adjudicator name, such as the bundle URL. It
+// is safe for concurrent use within a process
+type Store struct {
+	mu   sync.Mutex
+	path string
+	pins map[string]Pin
+}
+
+// Open loads the store at path, which need not exist yet
+func Open(path string) (*Store, error) {
+	s := Store{path: path, pins: map[string]Pin{}}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.pins); err != nil {
+		return nil, fmt.Errorf("pin: %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func newPin(adjudicator scheme.Fingerprint, publisher ed25519.PublicKey, now time.Time) Pin {
+	return Pin{adjudicator.String(), hex.EncodeToString(publisher), now.Unix()}
+}
+
+// Check pins adjudicator and publisher under name on first use, and
+// otherwise checks that they match the pin
+func (s *Store) Check(name string, adjudicator scheme.Fingerprint, publisher ed25519.PublicKey, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := newPin(adjudicator, publisher, now)
+	old, ok := s.pins[name]
+	if !ok {
+		return s.commit(name, p)
+	}
+	if old.Adjudicator != p.Adjudicator || old.Publisher != p.Publisher {
+		return fmt.Errorf("%w: %s pinned to %s", ErrPinMismatch, name, old.Adjudicator)
+	}
+	return nil
+}
+
+// Replace pins a new adjudicator under name, after a key rotation verified
+// out of band
+func (s *Store) Replace(name string, adjudicator scheme.Fingerprint, publisher ed25519.PublicKey, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commit(name, newPin(adjudicator, publisher, now))
+}
+
+// commit saves the pins with p under name, and only then pins it. If saving
+// fails, the store is left as it was, in memory as on disk
+func (s *Store) commit(name string, p Pin) error {
+	pins := make(map[string]Pin, len(s.pins)+1)
+	for k, v := range s.pins {
+		pins[k] = v
+	}
+	pins[name] = p
+	if err := s.save(pins); err != nil {
+		return err
+	}
+	s.pins = pins
+	return nil
+}
+
+// save writes pins to a temporary file, renamed over the store
+func (s *Store) save(pins map[string]Pin) error {
+	b, err := json.MarshalIndent(pins, "", "\t")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), ".pins-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
+
+const revocationTag = "VESS-REVOCATION-LIST-V1"
+
+// Revocation revokes an adjudicator key, by fingerprint
+type Revocation struct {
+	Adjudicator string `json:"adjudicator"`
+	RevokedAt   int64  `json:"revoked_at"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// RevocationList is published by an adjudicator's bundle publisher, and
+// replaced before NextUpdate
+type RevocationList struct {
+	Issued     int64        `json:"issued"`
+	NextUpdate int64        `json:"next_update"`
+	Revoked    []Revocation `json:"revoked"`
+}
+
+type revocationEnvelope struct {
+	List      json.RawMessage `json:"list"`
+	Signature string          `json:"signature"`
+}
+
+// SignRevocationList returns the signed JSON encoding of l
+func SignRevocationList(l *RevocationList, publisher ed25519.PrivateKey) ([]byte, error) {
+	payload, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	sig := ed25519.Sign(publisher, append([]byte(revocationTag), payload...))
+	return json.Marshal(revocationEnvelope{payload, hex.EncodeToString(sig)})
+}
+
+// OpenRevocationList verifies a list signed by publisher, which must not be
+// past its next update at now
+func OpenRevocationList(data []byte, publisher ed25519.PublicKey, now time.Time) (*RevocationList, error) {
+	env := revocationEnvelope{}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, ErrInvalidRevocationList
+	}
+	sig, err := hex.DecodeString(env.Signature)
+	if err != nil || !ed25519.Verify(publisher, append([]byte(revocationTag), env.List...), sig) {
+		return nil, ErrInvalidRevocationList
+	}
+	l := RevocationList{}
+	d := json.NewDecoder(bytes.NewReader(env.List))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&l); err != nil {
+		return nil, ErrInvalidRevocationList
+	}
+	if now.Unix() > l.NextUpdate {
+		return nil, ErrStaleRevocationList
+	}
+	return &l, nil
+}
+
+// Check returns an error if adjudicator is revoked
+func (l *RevocationList) Check(adjudicator scheme.Fingerprint) error {
+	fp := adjudicator.String()
+	for _, r := range l.Revoked {
+		if r.Adjudicator == fp {
+			return fmt.Errorf("%w: %s since %s: %s", ErrRevoked, fp,
+				time.Unix(r.RevokedAt, 0).UTC().Format(time.RFC3339), r.Reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package pin
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// A store whose directory is gone fails to save
+func TestStoreUnchangedWhenSaveFails(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pins")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(filepath.Join(dir, "pins.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	publisher := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	now := time.Unix(1000, 0)
+	if err := s.Check("a", scheme.Fingerprint{1}, publisher, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Check("b", scheme.Fingerprint{2}, publisher, now); err == nil {
+		t.Fatal("save did not fail")
+	}
+	if err := s.Replace("a", scheme.Fingerprint{2}, publisher, now); err == nil {
+		t.Fatal("save did not fail")
+	}
+
+	// Neither b nor the replacement of a was pinned
+	if err := s.Check("a", scheme.Fingerprint{1}, publisher, now); err != nil {
+		t.Fatalf("pin of a changed: %v", err)
+	}
+	if err := s.Check("a", scheme.Fingerprint{2}, publisher, now); !errors.Is(err, ErrPinMismatch) {
+		t.Fatalf("got %v, want %v", err, ErrPinMismatch)
+	}
+	if _, ok := s.pins["b"]; ok {
+		t.Fatal("b pinned although saving failed")
+	}
+}
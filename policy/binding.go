@@ -0,0 +1,36 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// Bind checks that conditions parse, then binds them to sig, escrowed with
+// adj, see Scheme.BindConditions
+func Bind[G1, G2 any, P1 scheme.Point[G1], P2 scheme.Point[G2]](s *scheme.Scheme[G1, G2, P1, P2], sk *scheme.SecretKey, adj *scheme.AdjudicatorPublicKey[G1, G2, P1, P2], sig *scheme.VESig[G2, P2], conditions []byte) (*scheme.ConditionsBinding[G1, G2, P1, P2], error) {
+	if _, err := ParseConditions(conditions); err != nil {
+		return nil, err
+	}
+	return s.BindConditions(sk, adj, sig, conditions)
+}
+
+// AdjudicateConditional is Scheme.AdjudicateRequest for an escrow with
+// conditions. The binding is verified and the conditions enforced on req at
+// time now before adjudicating
+func AdjudicateConditional[G1, G2 any, P1 scheme.Point[G1], P2 scheme.Point[G2]](s *scheme.Scheme[G1, G2, P1, P2], adjSK *scheme.SecretKey, pk *scheme.PublicKey[G1, P1], msg []byte, sig *scheme.VESig[G2, P2], b *scheme.ConditionsBinding[G1, G2, P1, P2], conditions []byte, req *scheme.AdjudicationRequest[G1, G2, P1, P2], registered []*scheme.PublicKey[G1, P1], now time.Time) (*scheme.Signature[G2, P2], *scheme.AdjudicationReceipt[G1, G2, P1, P2], error) {
+	if err := s.VerifyConditions(b, pk, s.AdjudicatorPublicKey(adjSK), sig, conditions); err != nil {
+		return nil, nil, err
+	}
+	c, err := ParseConditions(conditions)
+	if err != nil {
+		return nil, nil, err
+	}
+	if req.Requester == nil {
+		return nil, nil, scheme.ErrInvalidRequest
+	}
+	if err := c.Allow(req.Requester.Fingerprint().String(), req.Reason, now); err != nil {
+		return nil, nil, err
+	}
+	return s.AdjudicateRequest(adjSK, pk, msg, sig, req, registered, uint64(now.Unix()))
+}
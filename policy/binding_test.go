@@ -0,0 +1,65 @@
+package policy_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/poupas/bls-vess/bn254"
+	"github.com/poupas/bls-vess/policy"
+)
+
+func generateKey(t *testing.T) *bn254.SecretKey {
+	sk, err := bn254.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk
+}
+
+func TestAdjudicateConditional(t *testing.T) {
+	v, err := bn254.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, adjSK, requesterSK := generateKey(t), generateKey(t), generateKey(t)
+	pk, adj := v.PublicKey(sk), v.AdjudicatorPublicKey(adjSK)
+	msg := []byte("escrow")
+	sig, err := v.Sign(sk, adj, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := policy.Bind(v.Scheme, sk, adj, sig, []byte(`{"evidence": ["court-order"], "unknown": 1}`)); !errors.Is(err, policy.ErrSyntax) {
+		t.Fatalf("got %v, want %v", err, policy.ErrSyntax)
+	}
+	conditions := []byte(`{"evidence": ["court-order"], "not_before": 1000}`)
+	b, err := policy.Bind(v.Scheme, sk, adj, sig, conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		reason string
+		now    int64
+		err    error
+	}{
+		{"court-order:123", 2000, nil},
+		{"subpoena:123", 2000, policy.ErrEvidenceNotAccepted},
+		{"court-order:123", 500, policy.ErrOutsideWindow},
+	} {
+		req, err := v.NewAdjudicationRequest(requesterSK, adj, sig, tc.reason, uint64(tc.now))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigma, _, err := policy.AdjudicateConditional(v.Scheme, adjSK, pk, msg, sig, b, conditions, req, nil, time.Unix(tc.now, 0))
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("%s at %d: got %v, want %v", tc.reason, tc.now, err, tc.err)
+		}
+		if err == nil {
+			if ok, err := v.VerifyRecovered(pk, msg, sigma); err != nil || !ok {
+				t.Fatalf("recovered signature rejected: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRequesterNotAllowed = errors.New("policy: requester not allowed by the escrow conditions")
+	ErrEvidenceNotAccepted = errors.New("policy: evidence not accepted by the escrow conditions")
+	ErrOutsideWindow       = errors.New("policy: outside the adjudication window of the escrow conditions")
+)
+
+// Conditions are a signer's terms for opening one escrow. The signer binds
+// the hash of the JSON document to the escrow, see Bind
+type Conditions struct {
+	// Fingerprints of the keys allowed to request adjudication. Empty allows
+	// any requester
+	Requesters []string `json:"requesters,omitempty"`
+	// Accepted evidence kinds. A request reason is "<kind>" or
+	// "<kind>:<reference>". Empty accepts any reason
+	Evidence []string `json:"evidence,omitempty"`
+	// Unix times bounding when adjudication may happen. Zero is unbounded
+	NotBefore int64 `json:"not_before,omitempty"`
+	NotAfter  int64 `json:"not_after,omitempty"`
+	// Seconds from a valid request to adjudication, see SLA
+	SLASeconds int64 `json:"sla_seconds,omitempty"`
+}
+
+// ParseConditions decodes a conditions document. Unknown fields are rejected,
+// so that no term the signer wrote is silently ignored
+func ParseConditions(b []byte) (*Conditions, error) {
+	c := Conditions{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSyntax, err)
+	}
+	if d.More() {
+		return nil, fmt.Errorf("%w: trailing data", ErrSyntax)
+	}
+	return &c, nil
+}
+
+// Allow returns nil if requester, a key fingerprint, may have the escrow
+// opened for reason at time at
+func (c *Conditions) Allow(requester, reason string, at time.Time) error {
+	if len(c.Requesters) > 0 && !contains(c.Requesters, requester) {
+		return fmt.Errorf("%w: %s", ErrRequesterNotAllowed, requester)
+	}
+	kind := reason
+	if i := strings.IndexByte(reason, ':'); i >= 0 {
+		kind = reason[:i]
+	}
+	if len(c.Evidence) > 0 && !contains(c.Evidence, kind) {
+		return fmt.Errorf("%w: %q", ErrEvidenceNotAccepted, kind)
+	}
+	if (c.NotBefore != 0 && at.Unix() < c.NotBefore) ||
+		(c.NotAfter != 0 && at.Unix() > c.NotAfter) {
+		return ErrOutsideWindow
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+// Package policy decides whether a committee may adjudicate, before any share
+// is requested. Quorum rules are written one per line:
+//
+//	threshold 3       any 3 members must approve
+//	require alice     alice must be among them
+//	veto bob 1h       bob can veto within 1h of the request
+//	orgs 2            approvers must span 2 organizations
+//	org alice acme    alice belongs to acme
+//	escalate appeals  committee to escalate to when an SLA is breached
+//
+// Blank lines and text after # are ignored. Members are named by their
+// committee member IDs
+package policy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSyntax          = errors.New("policy: syntax error")
+	ErrUnknownMember   = errors.New("policy: unknown member")
+	ErrNoQuorum        = errors.New("policy: not enough approvals")
+	ErrMissingRequired = errors.New("policy: required member has not approved")
+	ErrTooFewOrgs      = errors.New("policy: not enough organizations")
+	ErrVetoed          = errors.New("policy: vetoed")
+	ErrVetoPending     = errors.New("policy: veto window still open")
+)
+
+// Quorum holds the rules a set of approvals must satisfy
+type Quorum struct {
+	Threshold int
+	Required  []string
+	// Veto windows, by member
+	Vetoers map[string]time.Duration
+	// Minimum number of distinct organizations among approvers
+	Orgs int
+	// Organization, by member
+	Org map[string]string
+	// Fallback committee, see SLA
+	Fallback string
+}
+
+// Veto is a member's objection to a request
+type Veto struct {
+	Member string    `json:"member"`
+	At     time.Time `json:"at"`
+}
+
+// Request is the state of an adjudication request
+type Request struct {
+	// When the request was opened
+	Opened    time.Time
+	Approvals []string
+	Vetoes    []Veto
+}
+
+// Parse reads quorum rules, see the package documentation
+func Parse(r io.Reader) (*Quorum, error) {
+	q := Quorum{Vetoers: map[string]time.Duration{}, Org: map[string]string{}}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		f := strings.Fields(line)
+		if len(f) == 0 {
+			continue
+		}
+		if err := q.rule(f); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", ErrSyntax, n, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if q.Threshold < 1 {
+		return nil, fmt.Errorf("%w: missing threshold", ErrSyntax)
+	}
+	return &q, nil
+}
+
+func (q *Quorum) rule(f []string) error {
+	arity := map[string]int{"threshold": 2, "require": 2, "veto": 3, "orgs": 2, "org": 3, "escalate": 2}
+	if n, ok := arity[f[0]]; !ok {
+		return fmt.Errorf("unknown rule %q", f[0])
+	} else if len(f) != n {
+		return fmt.Errorf("%s takes %d arguments", f[0], n-1)
+	}
+
+	var err error
+	switch f[0] {
+	case "threshold":
+		q.Threshold, err = positive(f[1])
+	case "require":
+		q.Required = append(q.Required, f[1])
+	case "veto":
+		var d time.Duration
+		if d, err = time.ParseDuration(f[2]); err == nil && d <= 0 {
+			err = errors.New("veto window must be positive")
+		}
+		q.Vetoers[f[1]] = d
+	case "orgs":
+		q.Orgs, err = positive(f[1])
+	case "org":
+		q.Org[f[1]] = f[2]
+	case "escalate":
+		q.Fallback = f[1]
+	}
+	return err
+}
+
+func positive(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err == nil && n < 1 {
+		err = errors.New("must be positive")
+	}
+	return n, err
+}
+
+// Check validates the rules against a committee of members, which
+// adjudicates with threshold shares
+func (q *Quorum) Check(members []string, threshold int) error {
+	known := map[string]bool{}
+	for _, m := range members {
+		known[m] = true
+	}
+	names := append([]string{}, q.Required...)
+	for m := range q.Vetoers {
+		names = append(names, m)
+	}
+	for m := range q.Org {
+		names = append(names, m)
+	}
+	for _, m := range names {
+		if !known[m] {
+			return fmt.Errorf("%w: %s", ErrUnknownMember, m)
+		}
+	}
+	if q.Threshold < threshold || q.Threshold > len(members) {
+		return fmt.Errorf("%w: threshold %d of %d, committee needs %d",
+			ErrSyntax, q.Threshold, len(members), threshold)
+	}
+	return nil
+}
+
+// Evaluate returns nil if the approvers of req may be asked for their shares
+// at time now
+func (q *Quorum) Evaluate(req *Request, now time.Time) error {
+	approved := map[string]bool{}
+	for _, m := range req.Approvals {
+		approved[m] = true
+	}
+
+	for _, v := range req.Vetoes {
+		w, ok := q.Vetoers[v.Member]
+		if ok && !v.At.Before(req.Opened) && v.At.Sub(req.Opened) <= w {
+			return fmt.Errorf("%w by %s", ErrVetoed, v.Member)
+		}
+	}
+	if len(approved) < q.Threshold {
+		return fmt.Errorf("%w: %d of %d", ErrNoQuorum, len(approved), q.Threshold)
+	}
+	for _, m := range q.Required {
+		if !approved[m] {
+			return fmt.Errorf("%w: %s", ErrMissingRequired, m)
+		}
+	}
+	orgs := map[string]bool{}
+	for m := range approved {
+		if o, ok := q.Org[m]; ok {
+			orgs[o] = true
+		}
+	}
+	if len(orgs) < q.Orgs {
+		return fmt.Errorf("%w: %d of %d", ErrTooFewOrgs, len(orgs), q.Orgs)
+	}
+	// Vetoers who approved have waived their window
+	for m, w := range q.Vetoers {
+		if !approved[m] && now.Before(req.Opened.Add(w)) {
+			return fmt.Errorf("%w: %s until %s", ErrVetoPending, m,
+				req.Opened.Add(w).UTC().Format(time.RFC3339))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/poupas/bls-vess/audit"
+)
+
+// decisionPrefix starts the audit log events recording quorum decisions
+const decisionPrefix = "policy-decision "
+
+// Decision records the outcome of evaluating an adjudication request, for
+// the audit log. Replay evaluates recorded decisions against new rules
+type Decision struct {
+	// ID identifies the request, such as the hex digest of its encoding
+	ID        string    `json:"id"`
+	Opened    time.Time `json:"opened"`
+	Approvals []string  `json:"approvals,omitempty"`
+	Vetoes    []Veto    `json:"vetoes,omitempty"`
+	At        time.Time `json:"at"`
+	Granted   bool      `json:"granted"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// NewDecision returns the decision of Evaluate for req at time at, which
+// returned err
+func NewDecision(id string, req *Request, at time.Time, err error) *Decision {
+	d := Decision{
+		ID:        id,
+		Opened:    req.Opened,
+		Approvals: req.Approvals,
+		Vetoes:    req.Vetoes,
+		At:        at,
+		Granted:   err == nil,
+	}
+	if err != nil {
+		d.Reason = err.Error()
+	}
+	return &d
+}
+
+// Event returns the audit log event for d
+func (d *Decision) Event() string {
+	b, _ := json.Marshal(d)
+	return decisionPrefix + string(b)
+}
+
+// Request returns the request d was made on
+func (d *Decision) Request() *Request {
+	return &Request{Opened: d.Opened, Approvals: d.Approvals, Vetoes: d.Vetoes}
+}
+
+// Change is a recorded decision that q decides differently
+type Change struct {
+	Decision *Decision
+	// Reason is why q denies the request, if it was granted
+	Reason string
+}
+
+// Replay re-evaluates, with q, every decision recorded in the audit log r,
+// and returns those whose outcome changes along with the number of decisions
+// replayed. Nothing is adjudicated. The log is not verified, see
+// audit.Verify
+func Replay(r io.Reader, q *Quorum) ([]Change, int, error) {
+	changes := []Change{}
+	n := 0
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		e := audit.Entry{}
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return nil, n, err
+		}
+		if !strings.HasPrefix(e.Event, decisionPrefix) {
+			continue
+		}
+		d := Decision{}
+		if err := json.Unmarshal([]byte(e.Event[len(decisionPrefix):]), &d); err != nil {
+			return nil, n, fmt.Errorf("policy: audit entry %d: %w", e.Seq, err)
+		}
+		n++
+		err := q.Evaluate(d.Request(), d.At)
+		if (err == nil) != d.Granted {
+			c := Change{Decision: &d}
+			if err != nil {
+				c.Reason = err.Error()
+			}
+			changes = append(changes, c)
+		}
+	}
+	return changes, n, s.Err()
+}
+
+// PrintChanges writes a report of changes, for the CLI
+func PrintChanges(w io.Writer, changes []Change, replayed int) {
+	for _, c := range changes {
+		if c.Decision.Granted {
+			fmt.Fprintf(w, "%s granted at %s, now denied: %s\n",
+				c.Decision.ID, c.Decision.At.UTC().Format(time.RFC3339), c.Reason)
+		} else {
+			fmt.Fprintf(w, "%s denied at %s (%s), now granted\n",
+				c.Decision.ID, c.Decision.At.UTC().Format(time.RFC3339), c.Decision.Reason)
+		}
+	}
+	fmt.Fprintf(w, "%d of %d decisions change\n", len(changes), replayed)
+}
@@ -0,0 +1,57 @@
+package policy
+
+import "time"
+
+// SLAState is the state of an adjudication against its SLA
+type SLAState int
+
+const (
+	SLAOnTrack SLAState = iota
+	SLAAtRisk
+	SLABreached
+)
+
+func (s SLAState) String() string {
+	switch s {
+	case SLAOnTrack:
+		return "on track"
+	case SLAAtRisk:
+		return "at risk"
+	case SLABreached:
+		return "breached"
+	}
+	return "unknown"
+}
+
+// SLA bounds the time from a valid adjudication request to adjudication
+type SLA struct {
+	Within time.Duration
+	// The SLA is at risk past Warn
+	Warn time.Duration
+}
+
+// Deadline returns the adjudication deadline of a request opened at opened
+func (s *SLA) Deadline(opened time.Time) time.Time {
+	return opened.Add(s.Within)
+}
+
+// State returns the state at now of a pending request opened at opened
+func (s *SLA) State(opened, now time.Time) SLAState {
+	switch d := now.Sub(opened); {
+	case d > s.Within:
+		return SLABreached
+	case d > s.Warn:
+		return SLAAtRisk
+	}
+	return SLAOnTrack
+}
+
+// SLA returns the SLA of the escrow, nil if it has none. It is at risk
+// after 80% of its time
+func (c *Conditions) SLA() *SLA {
+	if c.SLASeconds <= 0 {
+		return nil
+	}
+	d := time.Duration(c.SLASeconds) * time.Second
+	return &SLA{Within: d, Warn: d / 5 * 4}
+}
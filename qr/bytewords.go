@@ -0,0 +1,88 @@
+package qr
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strings"
+)
+
+var ErrInvalidBytewords = errors.New("invalid bytewords")
+
+// bytewords is the Bytewords wordlist (BCR-2020-012). Words are uniquely
+// identified by their first and last letters
+var bytewords = [256]string{
+	"able", "acid", "also", "apex", "aqua", "arch", "atom", "aunt", "away",
+	"axis", "back", "bald", "barn", "belt", "beta", "bias", "blue", "body",
+	"brag", "brew", "bulb", "buzz", "calm", "cash", "cats", "chef", "city",
+	"claw", "code", "cola", "cook", "cost", "crux", "curl", "cusp", "cyan",
+	"dark", "data", "days", "deli", "dice", "diet", "door", "down", "draw",
+	"drop", "drum", "dull", "duty", "each", "easy", "echo", "edge", "epic",
+	"even", "exam", "exit", "eyes", "fact", "fair", "fern", "figs", "film",
+	"fish", "fizz", "flap", "flew", "flux", "foxy", "free", "frog", "fuel",
+	"fund", "gala", "game", "gear", "gems", "gift", "girl", "glow", "good",
+	"gray", "grim", "guru", "gush", "gyro", "half", "hang", "hard", "hawk",
+	"heat", "help", "high", "hill", "holy", "hope", "horn", "huts", "iced",
+	"idea", "idle", "inch", "inky", "into", "iris", "iron", "item", "jade",
+	"jazz", "join", "jolt", "jowl", "judo", "jugs", "jump", "junk", "jury",
+	"keep", "keno", "kept", "keys", "kick", "kiln", "king", "kite", "kiwi",
+	"knob", "lamb", "lava", "lazy", "leaf", "legs", "liar", "limp", "lion",
+	"list", "logo", "loud", "love", "luau", "luck", "lung", "main", "many",
+	"math", "maze", "memo", "menu", "meow", "mild", "mint", "miss", "monk",
+	"nail", "navy", "need", "news", "next", "noon", "note", "numb", "obey",
+	"oboe", "omit", "onyx", "open", "oval", "owls", "paid", "part", "peck",
+	"play", "plus", "poem", "pool", "pose", "puff", "puma", "purr", "quad",
+	"quiz", "race", "ramp", "real", "redo", "rich", "road", "rock", "roof",
+	"ruby", "ruin", "runs", "rust", "safe", "saga", "scar", "sets", "silk",
+	"skew", "slot", "soap", "solo", "song", "stub", "surf", "swan", "taco",
+	"task", "taxi", "tent", "tied", "time", "tiny", "toil", "tomb", "toys",
+	"trip", "tuna", "twin", "ugly", "undo", "unit", "urge", "user", "vast",
+	"very", "veto", "vial", "vibe", "view", "visa", "void", "vows", "wall",
+	"wand", "warm", "wasp", "wave", "waxy", "webs", "what", "when", "whiz",
+	"wolf", "work", "yank", "yawn", "yell", "yoga", "yurt", "zaps", "zero",
+	"zest", "zinc", "zone", "zoom",
+}
+
+var minimalIndex = func() map[string]byte {
+	m := make(map[string]byte, len(bytewords))
+	for i, w := range bytewords {
+		m[w[:1]+w[3:]] = byte(i)
+	}
+	return m
+}()
+
+// encodeMinimal encodes b in minimal Bytewords, two letters per byte,
+// followed by its CRC-32 checksum
+func encodeMinimal(b []byte) string {
+	crc := [4]byte{}
+	binary.BigEndian.PutUint32(crc[:], crc32.ChecksumIEEE(b))
+	sb := strings.Builder{}
+	sb.Grow(2 * (len(b) + len(crc)))
+	for _, c := range append(append([]byte{}, b...), crc[:]...) {
+		w := bytewords[c]
+		sb.WriteByte(w[0])
+		sb.WriteByte(w[3])
+	}
+	return sb.String()
+}
+
+// decodeMinimal decodes minimal Bytewords and checks the checksum
+func decodeMinimal(s string) ([]byte, error) {
+	s = strings.ToLower(s)
+	if len(s)%2 != 0 || len(s) < 2*4 {
+		return nil, ErrInvalidBytewords
+	}
+	b := make([]byte, len(s)/2)
+	for i := range b {
+		c, ok := minimalIndex[s[2*i:2*i+2]]
+		if !ok {
+			return nil, ErrInvalidBytewords
+		}
+		b[i] = c
+	}
+	n := len(b) - 4
+	if crc32.ChecksumIEEE(b[:n]) != binary.BigEndian.Uint32(b[n:]) {
+		return nil, ErrInvalidBytewords
+	}
+	return b[:n], nil
+}
@@ -0,0 +1,181 @@
+// Package qr encodes payloads for transfer through QR codes between
+// air-gapped machines, in the style of Uniform Resources (BCR-2020-005).
+//
+// A payload is split in sequential fragments. Each part reads
+//
+//	VESS:TYPE/SEQ-TOTAL/BYTEWORDS
+//
+// where BYTEWORDS is the minimal Bytewords encoding of the CRC-32 of the
+// whole payload, the fragment and the CRC-32 of both. Single part payloads
+// omit SEQ-TOTAL. Parts only use QR alphanumeric characters, and are decoded
+// case-insensitively. Unlike UR, payloads are not CBOR and fragments are not
+// fountain coded: every part must be scanned
+package qr
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+const prefix = "vess:"
+
+// Types of the payloads exchanged by this module
+const (
+	TypeVESig       = "vesig"
+	TypePartial     = "partial"
+	TypeKeyShare    = "keyshare"
+	TypeBackupShare = "backupshare"
+)
+
+var (
+	ErrInvalidType    = errors.New("invalid payload type")
+	ErrInvalidPart    = errors.New("invalid part")
+	ErrPartMismatch   = errors.New("part belongs to a different payload")
+	ErrIncomplete     = errors.New("missing parts")
+	ErrInvalidPayload = errors.New("invalid payload checksum")
+)
+
+// Encode splits payload in parts of at most maxFragment payload bytes each
+func Encode(typ string, payload []byte, maxFragment int) ([]string, error) {
+	if !validType(typ) {
+		return nil, ErrInvalidType
+	}
+	if maxFragment < 1 {
+		return nil, errors.New("invalid fragment size")
+	}
+
+	crc := [4]byte{}
+	binary.BigEndian.PutUint32(crc[:], crc32.ChecksumIEEE(payload))
+	total := (len(payload) + maxFragment - 1) / maxFragment
+	if total == 0 {
+		total = 1
+	}
+
+	parts := make([]string, total)
+	for i := range parts {
+		end := (i + 1) * maxFragment
+		if end > len(payload) {
+			end = len(payload)
+		}
+		body := encodeMinimal(append(crc[:], payload[i*maxFragment:end]...))
+		part := prefix + typ + "/"
+		if total > 1 {
+			part += strconv.Itoa(i+1) + "-" + strconv.Itoa(total) + "/"
+		}
+		parts[i] = strings.ToUpper(part + body)
+	}
+	return parts, nil
+}
+
+// Decode decodes a payload from all of its parts, in any order
+func Decode(parts ...string) (string, []byte, error) {
+	d := Decoder{}
+	for _, p := range parts {
+		if err := d.Add(p); err != nil {
+			return "", nil, err
+		}
+	}
+	return d.Result()
+}
+
+// Decoder collects the parts of a payload as they are scanned
+type Decoder struct {
+	typ       string
+	crc       uint32
+	fragments [][]byte
+	received  int
+}
+
+// Add adds a part. Duplicate parts are ignored
+func (d *Decoder) Add(part string) error {
+	part = strings.ToLower(part)
+	if !strings.HasPrefix(part, prefix) {
+		return ErrInvalidPart
+	}
+	fields := strings.Split(part[len(prefix):], "/")
+
+	seq, total := 1, 1
+	switch len(fields) {
+	case 2:
+	case 3:
+		s, t, ok := strings.Cut(fields[1], "-")
+		if !ok {
+			return ErrInvalidPart
+		}
+		var err1, err2 error
+		seq, err1 = strconv.Atoi(s)
+		total, err2 = strconv.Atoi(t)
+		if err1 != nil || err2 != nil || total < 1 || seq < 1 || seq > total {
+			return ErrInvalidPart
+		}
+	default:
+		return ErrInvalidPart
+	}
+	typ := fields[0]
+	if !validType(typ) {
+		return ErrInvalidType
+	}
+
+	body, err := decodeMinimal(fields[len(fields)-1])
+	if err != nil {
+		return err
+	}
+	if len(body) < 4 {
+		return ErrInvalidPart
+	}
+	crc := binary.BigEndian.Uint32(body)
+
+	if d.fragments == nil {
+		d.typ = typ
+		d.crc = crc
+		d.fragments = make([][]byte, total)
+	} else if typ != d.typ || crc != d.crc || total != len(d.fragments) {
+		return ErrPartMismatch
+	}
+	if d.fragments[seq-1] == nil {
+		d.fragments[seq-1] = body[4:]
+		d.received++
+	}
+	return nil
+}
+
+// Progress returns the number of distinct parts received, and the total
+// number of parts. total is 0 before the first part
+func (d *Decoder) Progress() (received, total int) {
+	return d.received, len(d.fragments)
+}
+
+// Complete reports whether all parts were received
+func (d *Decoder) Complete() bool {
+	return d.fragments != nil && d.received == len(d.fragments)
+}
+
+// Result returns the payload type and the payload
+func (d *Decoder) Result() (string, []byte, error) {
+	if !d.Complete() {
+		return "", nil, ErrIncomplete
+	}
+	payload := []byte{}
+	for _, f := range d.fragments {
+		payload = append(payload, f...)
+	}
+	if crc32.ChecksumIEEE(payload) != d.crc {
+		return "", nil, ErrInvalidPayload
+	}
+	return d.typ, payload, nil
+}
+
+func validType(typ string) bool {
+	if typ == "" {
+		return false
+	}
+	for _, c := range typ {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// The Bytewords test vector of BCR-2020-012
+func TestBytewordsVector(t *testing.T) {
+	b := []byte{0, 1, 2, 128, 255}
+	if got := encodeMinimal(b); got != "aeadaolazmjendeoti" {
+		t.Fatalf("got %s", got)
+	}
+	got, err := decodeMinimal("AEADAOLAZMJENDEOTI")
+	if err != nil || !bytes.Equal(got, b) {
+		t.Fatalf("got %x, %v", got, err)
+	}
+	for _, s := range []string{"aeadaolazmjendeota", "aeadaolazmjendeot", "aeadaolazmjendeoxx", "aeadao"} {
+		if _, err := decodeMinimal(s); !errors.Is(err, ErrInvalidBytewords) {
+			t.Errorf("%s: got %v, want %v", s, err, ErrInvalidBytewords)
+		}
+	}
+}
+
+// Parts computed independently from the format description
+func TestEncodeKnownParts(t *testing.T) {
+	for _, c := range []struct {
+		typ     string
+		payload []byte
+		max     int
+		parts   []string
+	}{
+		{TypeVESig, []byte{0, 1, 2, 128, 255}, 60, []string{"VESS:VESIG/JENDEOTIAEADAOLAZMJZTASSHL"}},
+		{TypeKeyShare, nil, 60, []string{"VESS:KEYSHARE/AEAEAEAECLFYURCE"}},
+		{TypePartial, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, 4, []string{
+			"VESS:PARTIAL/1-3/FEJZTSFGAEADAOAXFTFNWEAE",
+			"VESS:PARTIAL/2-3/FEJZTSFGAAAHAMATTTHFTEMT",
+			"VESS:PARTIAL/3-3/FEJZTSFGAYASSFAAVOTO",
+		}},
+	} {
+		parts, err := Encode(c.typ, c.payload, c.max)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(parts, " ") != strings.Join(c.parts, " ") {
+			t.Errorf("%s: got %v, want %v", c.typ, parts, c.parts)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	payload := bytes.Repeat([]byte{1, 2, 3, 250}, 50)
+	parts, err := Encode(TypeVESig, payload, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 4 {
+		t.Fatalf("got %d parts", len(parts))
+	}
+	// Out of order, with a duplicate and in lower case
+	typ, got, err := Decode(parts[3], parts[1], strings.ToLower(parts[0]), parts[2], parts[1])
+	if err != nil || typ != TypeVESig || !bytes.Equal(got, payload) {
+		t.Fatalf("got %s %x, %v", typ, got, err)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	parts, err := Encode(TypeBackupShare, []byte("backup share"), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Decoder{}
+	if _, total := d.Progress(); total != 0 {
+		t.Fatal("total before the first part")
+	}
+	for i, p := range parts {
+		if d.Complete() {
+			t.Fatal("complete too early")
+		}
+		if _, _, err := d.Result(); !errors.Is(err, ErrIncomplete) {
+			t.Fatalf("got %v, want %v", err, ErrIncomplete)
+		}
+		if err := d.Add(p); err != nil {
+			t.Fatal(err)
+		}
+		if received, total := d.Progress(); received != i+1 || total != len(parts) {
+			t.Fatalf("progress %d/%d", received, total)
+		}
+	}
+	if _, got, err := d.Result(); err != nil || string(got) != "backup share" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	a, _ := Encode(TypeVESig, []byte("first payload"), 5)
+	b, _ := Encode(TypeVESig, []byte("other payload"), 5)
+	for _, c := range []struct {
+		name  string
+		parts []string
+		err   error
+	}{
+		{"prefix", []string{"UR:VESIG/AEADAOLAZMJENDEOTI"}, ErrInvalidPart},
+		{"type", []string{"VESS:VE_SIG/AEADAOLAZMJENDEOTI"}, ErrInvalidType},
+		{"sequence", []string{"VESS:VESIG/4-3/AEADAOLAZMJENDEOTI"}, ErrInvalidPart},
+		{"fields", []string{"VESS:VESIG/1-3/X/AEADAOLAZMJENDEOTI"}, ErrInvalidPart},
+		{"checksum", []string{a[0][:len(a[0])-2] + "AE"}, ErrInvalidBytewords},
+		{"mixed", []string{a[0], b[1]}, ErrPartMismatch},
+		{"missing", a[1:], ErrIncomplete},
+	} {
+		if _, _, err := Decode(c.parts...); !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.err)
+		}
+	}
+	if _, err := Encode("VESig", nil, 10); !errors.Is(err, ErrInvalidType) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidType)
+	}
+}
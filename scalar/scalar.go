@@ -0,0 +1,163 @@
+// Package scalar implements arithmetic modulo the order r of the BLS12-381
+// groups, for threshold users handling share indices, Lagrange coefficients
+// and blinding factors along with package vess.
+//
+// Arithmetic is gnark's Montgomery field arithmetic, which does not branch
+// on values. Inversion uses Fermat's little theorem, and comparisons are
+// constant time. Encodings are 32 bytes, big-endian, and must be reduced
+// modulo r: the same encoding as vess secret keys
+package scalar
+
+import (
+	"crypto/subtle"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Size is the size of an encoded scalar
+const Size = fr.Bytes
+
+var (
+	ErrInvalidLength = errors.New("scalar: invalid encoding length")
+	ErrNonCanonical  = errors.New("scalar: encoding not reduced modulo r")
+	ErrInvalidIndex  = errors.New("scalar: indices must be positive and distinct")
+)
+
+// rMinus2 is the inversion exponent
+var rMinus2 = new(big.Int).Sub(fr.Modulus(), big.NewInt(2))
+
+// Scalar is an integer modulo r. The zero value is 0
+type Scalar struct {
+	e fr.Element
+}
+
+// Order returns r
+func Order() *big.Int {
+	return fr.Modulus()
+}
+
+// Random returns a uniformly random scalar
+func Random() (*Scalar, error) {
+	s := Scalar{}
+	if _, err := s.e.SetRandom(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// FromUint64 returns v modulo r
+func FromUint64(v uint64) *Scalar {
+	s := Scalar{}
+	s.e.SetUint64(v)
+	return &s
+}
+
+// FromBigInt returns v modulo r
+func FromBigInt(v *big.Int) *Scalar {
+	s := Scalar{}
+	s.e.SetBigInt(v)
+	return &s
+}
+
+// FromBytes decodes a scalar, rejecting encodings not reduced modulo r
+func FromBytes(b []byte) (*Scalar, error) {
+	if len(b) != Size {
+		return nil, ErrInvalidLength
+	}
+	s := Scalar{}
+	s.e.SetBytes(b)
+	if c := s.e.Bytes(); subtle.ConstantTimeCompare(c[:], b) != 1 {
+		return nil, ErrNonCanonical
+	}
+	return &s, nil
+}
+
+// Bytes returns the big-endian encoding of s
+func (s *Scalar) Bytes() [Size]byte {
+	return s.e.Bytes()
+}
+
+// BigInt returns s as a big.Int. big.Int arithmetic is not constant time
+func (s *Scalar) BigInt() *big.Int {
+	return s.e.ToBigIntRegular(new(big.Int))
+}
+
+// Set sets s to a and returns s
+func (s *Scalar) Set(a *Scalar) *Scalar {
+	s.e.Set(&a.e)
+	return s
+}
+
+// Add sets s to a+b and returns s
+func (s *Scalar) Add(a, b *Scalar) *Scalar {
+	s.e.Add(&a.e, &b.e)
+	return s
+}
+
+// Sub sets s to a-b and returns s
+func (s *Scalar) Sub(a, b *Scalar) *Scalar {
+	s.e.Sub(&a.e, &b.e)
+	return s
+}
+
+// Mul sets s to a*b and returns s
+func (s *Scalar) Mul(a, b *Scalar) *Scalar {
+	s.e.Mul(&a.e, &b.e)
+	return s
+}
+
+// Neg sets s to -a and returns s
+func (s *Scalar) Neg(a *Scalar) *Scalar {
+	s.e.Neg(&a.e)
+	return s
+}
+
+// Inverse sets s to 1/a, or 0 if a is 0, and returns s
+func (s *Scalar) Inverse(a *Scalar) *Scalar {
+	s.e.Exp(a.e, rMinus2)
+	return s
+}
+
+// IsZero reports whether s is 0
+func (s *Scalar) IsZero() bool {
+	return s.Equal(&Scalar{})
+}
+
+// Equal reports whether s and o are equal, in constant time
+func (s *Scalar) Equal(o *Scalar) bool {
+	a, b := s.e.Bytes(), o.e.Bytes()
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// Lagrange returns the Lagrange coefficients at 0 for the share indices
+// (starting at 1), as used to combine partial adjudications
+func Lagrange(indices []int) ([]*Scalar, error) {
+	seen := make(map[int]bool, len(indices))
+	xs := make([]Scalar, len(indices))
+	for i, x := range indices {
+		if x < 1 || seen[x] {
+			return nil, ErrInvalidIndex
+		}
+		seen[x] = true
+		xs[i].e.SetUint64(uint64(x))
+	}
+
+	// lambda_i = prod_{j != i} x_j / (x_j - x_i)
+	lambdas := make([]*Scalar, len(indices))
+	num, den, d := Scalar{}, Scalar{}, Scalar{}
+	for i := range xs {
+		num.e.SetOne()
+		den.e.SetOne()
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			num.Mul(&num, &xs[j])
+			den.Mul(&den, d.Sub(&xs[j], &xs[i]))
+		}
+		lambdas[i] = new(Scalar).Mul(&num, den.Inverse(&den))
+	}
+	return lambdas, nil
+}
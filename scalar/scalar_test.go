@@ -0,0 +1,169 @@
+package scalar
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+const rHex = "73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001"
+
+func decode(t *testing.T, h string) []byte {
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestEncoding(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		hex  string
+		want *Scalar
+		err  error
+	}{
+		{"zero", "0000000000000000000000000000000000000000000000000000000000000000", FromUint64(0), nil},
+		{"one", "0000000000000000000000000000000000000000000000000000000000000001", FromUint64(1), nil},
+		{"r-1", "73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000000", new(Scalar).Neg(FromUint64(1)), nil},
+		{"r", rHex, nil, ErrNonCanonical},
+		{"r+1", "73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000002", nil, ErrNonCanonical},
+		{"all ones", "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", nil, ErrNonCanonical},
+		{"short", "01", nil, ErrInvalidLength},
+		{"long", "00" + rHex, nil, ErrInvalidLength},
+	} {
+		b := decode(t, c.hex)
+		s, err := FromBytes(b)
+		if !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !s.Equal(c.want) {
+			t.Errorf("%s: decoded to %x", c.name, s.Bytes())
+		}
+		if got := s.Bytes(); !bytes.Equal(got[:], b) {
+			t.Errorf("%s: re-encoded to %x", c.name, got)
+		}
+		if s.BigInt().Cmp(new(big.Int).SetBytes(b)) != 0 {
+			t.Errorf("%s: BigInt %v", c.name, s.BigInt())
+		}
+	}
+	if Order().Text(16) != rHex {
+		t.Fatalf("order %x", Order())
+	}
+}
+
+func TestEqual(t *testing.T) {
+	r := Order()
+	for _, c := range []struct {
+		name string
+		a, b *Scalar
+		want bool
+	}{
+		{"zero", FromUint64(0), &Scalar{}, true},
+		{"one", FromUint64(1), FromBigInt(big.NewInt(1)), true},
+		{"different", FromUint64(1), FromUint64(2), false},
+		{"reduced", FromBigInt(new(big.Int).Add(r, big.NewInt(5))), FromUint64(5), true},
+		{"r is zero", FromBigInt(r), &Scalar{}, true},
+		{"negative", FromBigInt(big.NewInt(-1)), new(Scalar).Neg(FromUint64(1)), true},
+		{"last byte", FromUint64(1 << 8), FromUint64(1<<8 + 1), false},
+		{"first byte", FromBigInt(new(big.Int).Lsh(big.NewInt(1), 248)), &Scalar{}, false},
+	} {
+		if got := c.a.Equal(c.b); got != c.want {
+			t.Errorf("%s: got %t", c.name, got)
+		}
+		if got := c.b.Equal(c.a); got != c.want {
+			t.Errorf("%s reversed: got %t", c.name, got)
+		}
+	}
+	if !(&Scalar{}).IsZero() || FromUint64(1).IsZero() || !FromBigInt(r).IsZero() {
+		t.Fatal("IsZero")
+	}
+}
+
+func TestInverse(t *testing.T) {
+	r := Order()
+	for _, c := range []struct {
+		name string
+		a    *Scalar
+	}{
+		{"one", FromUint64(1)},
+		{"two", FromUint64(2)},
+		{"r-1", new(Scalar).Neg(FromUint64(1))},
+		{"r-2", FromBigInt(new(big.Int).Sub(r, big.NewInt(2)))},
+		{"large", FromBigInt(new(big.Int).Lsh(big.NewInt(1), 250))},
+	} {
+		inv := new(Scalar).Inverse(c.a)
+		if !new(Scalar).Mul(inv, c.a).Equal(FromUint64(1)) {
+			t.Errorf("%s: a * 1/a != 1", c.name)
+		}
+		want := new(big.Int).ModInverse(c.a.BigInt(), r)
+		if inv.BigInt().Cmp(want) != 0 {
+			t.Errorf("%s: got %v, want %v", c.name, inv.BigInt(), want)
+		}
+	}
+
+	// 0 has no inverse: Fermat's little theorem gives 0
+	if !new(Scalar).Inverse(&Scalar{}).IsZero() {
+		t.Fatal("1/0 != 0")
+	}
+
+	// In place
+	a := FromUint64(3)
+	if a.Inverse(a); !a.Equal(new(Scalar).Inverse(FromUint64(3))) {
+		t.Fatal("in place inverse differs")
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	r := Order()
+	a, b := FromUint64(7), new(Scalar).Neg(FromUint64(2))
+	for _, c := range []struct {
+		name string
+		got  *Scalar
+		want *big.Int
+	}{
+		{"add", new(Scalar).Add(a, b), big.NewInt(5)},
+		{"add wraps", new(Scalar).Add(b, b), new(big.Int).Sub(r, big.NewInt(4))},
+		{"sub", new(Scalar).Sub(b, a), new(big.Int).Sub(r, big.NewInt(9))},
+		{"mul", new(Scalar).Mul(a, b), new(big.Int).Sub(r, big.NewInt(14))},
+		{"neg zero", new(Scalar).Neg(&Scalar{}), big.NewInt(0)},
+		{"set", new(Scalar).Set(a), big.NewInt(7)},
+	} {
+		if c.got.BigInt().Cmp(c.want) != 0 {
+			t.Errorf("%s: got %v, want %v", c.name, c.got.BigInt(), c.want)
+		}
+	}
+}
+
+func TestLagrange(t *testing.T) {
+	// At 0 for {1, 2}: 2 and -1. For {1, 2, 3}: 3, -3 and 1
+	for _, c := range []struct {
+		indices []int
+		want    []int64
+	}{
+		{[]int{1, 2}, []int64{2, -1}},
+		{[]int{1, 2, 3}, []int64{3, -3, 1}},
+		{[]int{5}, []int64{1}},
+	} {
+		lambdas, err := Lagrange(c.indices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, w := range c.want {
+			if !lambdas[i].Equal(FromBigInt(big.NewInt(w))) {
+				t.Errorf("%v: lambda %d = %v, want %d", c.indices, i, lambdas[i].BigInt(), w)
+			}
+		}
+	}
+	for _, indices := range [][]int{{0, 1}, {-1}, {1, 1}} {
+		if _, err := Lagrange(indices); !errors.Is(err, ErrInvalidIndex) {
+			t.Errorf("%v: got %v, want %v", indices, err, ErrInvalidIndex)
+		}
+	}
+}
@@ -0,0 +1,447 @@
+// Package slip39 implements SLIP-0039 mnemonic shares
+// (https://github.com/satoshilabs/slips/blob/master/slip-0039.md)
+//
+// Secrets are split in a single group. Mnemonics from multi-group splits are
+// accepted when combining
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	radixBits = 10
+	// Words holding the identifier, the extendable flag and the iteration
+	// exponent, and words holding the group and member parameters
+	idExpWords    = 2
+	paramsWords   = 2
+	checksumWords = 3
+	metadataWords = idExpWords + paramsWords + checksumWords
+	minWords      = metadataWords + 13
+
+	maxShares      = 16
+	digestLength   = 4
+	digestIndex    = 254
+	secretIndex    = 255
+	roundCount     = 4
+	baseIterations = 10000
+
+	// iterationExponent is the default of the reference implementation
+	iterationExponent = 1
+)
+
+// MaxIterationExponent is the largest iteration exponent Combine accepts.
+// Mnemonics encode exponents up to 15, which would have Combine run 10000 *
+// 2^15 PBKDF2 iterations: minutes of work, for anyone handing us a mnemonic.
+// Use CombineMaxExponent to accept more
+const MaxIterationExponent = 5
+
+var (
+	ErrInvalidSecret     = errors.New("secret must be at least 16 bytes long, and of even length")
+	ErrInvalidThreshold  = errors.New("invalid threshold parameters")
+	ErrInvalidMnemonic   = errors.New("invalid mnemonic")
+	ErrInvalidChecksum   = errors.New("invalid mnemonic checksum")
+	ErrMismatch          = errors.New("mnemonics belong to different secrets")
+	ErrInsufficientShare = errors.New("insufficient number of mnemonic shares")
+	ErrInvalidDigest     = errors.New("invalid digest of the shared secret")
+	ErrIterationExponent = errors.New("iteration exponent above the limit")
+)
+
+// share is a decoded mnemonic
+type share struct {
+	id             uint16
+	extendable     bool
+	exponent       int
+	groupIndex     int
+	groupThreshold int
+	groupCount     int
+	index          int
+	threshold      int
+	value          []byte
+}
+
+// Split splits secret into count mnemonics, any threshold of which recover it.
+// The secret is encrypted with passphrase, which may be empty
+func Split(secret []byte, threshold, count int, passphrase []byte) ([]string, error) {
+	if len(secret) < 16 || len(secret)%2 != 0 {
+		return nil, ErrInvalidSecret
+	}
+	if threshold < 1 || count < threshold || count > maxShares ||
+		(threshold == 1 && count > 1) {
+		return nil, ErrInvalidThreshold
+	}
+
+	idb := [2]byte{}
+	if _, err := rand.Read(idb[:]); err != nil {
+		return nil, err
+	}
+	id := binary.BigEndian.Uint16(idb[:]) >> 1
+
+	ems := encrypt(secret, passphrase, iterationExponent, id)
+	values, err := splitSecret(threshold, count, ems)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonics := make([]string, count)
+	for i, v := range values {
+		mnemonics[i] = encode(&share{
+			id:             id,
+			exponent:       iterationExponent,
+			groupThreshold: 1,
+			groupCount:     1,
+			index:          i,
+			threshold:      threshold,
+			value:          v,
+		})
+	}
+	return mnemonics, nil
+}
+
+// Combine recovers the secret from mnemonics, checking their checksums.
+// Mnemonics with an iteration exponent above MaxIterationExponent are
+// rejected
+func Combine(mnemonics []string, passphrase []byte) ([]byte, error) {
+	return CombineMaxExponent(mnemonics, passphrase, MaxIterationExponent)
+}
+
+// CombineMaxExponent is Combine, accepting iteration exponents up to
+// maxExponent
+func CombineMaxExponent(mnemonics []string, passphrase []byte, maxExponent int) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, ErrInsufficientShare
+	}
+	shares := make([]*share, len(mnemonics))
+	for i, m := range mnemonics {
+		s, err := decode(m)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = s
+	}
+
+	// Group member shares
+	first := shares[0]
+	groups := map[int][]*share{}
+	for _, s := range shares {
+		if s.id != first.id || s.extendable != first.extendable ||
+			s.exponent != first.exponent ||
+			s.groupThreshold != first.groupThreshold ||
+			s.groupCount != first.groupCount ||
+			len(s.value) != len(first.value) {
+			return nil, ErrMismatch
+		}
+		g := groups[s.groupIndex]
+		if len(g) > 0 && g[0].threshold != s.threshold {
+			return nil, ErrMismatch
+		}
+		for _, o := range g {
+			if o.index == s.index {
+				return nil, ErrInvalidMnemonic
+			}
+		}
+		groups[s.groupIndex] = append(g, s)
+	}
+	if first.exponent > maxExponent {
+		return nil, ErrIterationExponent
+	}
+
+	// Recover group shares, then the encrypted master secret
+	indices := []int{}
+	values := [][]byte{}
+	for gi, g := range groups {
+		if len(g) < g[0].threshold {
+			continue
+		}
+		gx := make([]int, g[0].threshold)
+		gv := make([][]byte, g[0].threshold)
+		for i, s := range g[:g[0].threshold] {
+			gx[i] = s.index
+			gv[i] = s.value
+		}
+		v, err := recoverSecret(gx, gv)
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, gi)
+		values = append(values, v)
+	}
+	if len(indices) < first.groupThreshold {
+		return nil, ErrInsufficientShare
+	}
+	ems, err := recoverSecret(indices[:first.groupThreshold], values[:first.groupThreshold])
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(ems, passphrase, first.exponent, first.id, first.extendable), nil
+}
+
+// splitSecret splits secret in count shares, at x = 0..count-1. The shared
+// polynomial also goes through the digest at x = 254 and the secret at x = 255
+func splitSecret(threshold, count int, secret []byte) ([][]byte, error) {
+	values := make([][]byte, count)
+	if threshold == 1 {
+		for i := range values {
+			values[i] = append([]byte{}, secret...)
+		}
+		return values, nil
+	}
+
+	// threshold-2 random shares, plus the digest and the secret
+	xs := make([]int, 0, threshold)
+	ys := make([][]byte, 0, threshold)
+	for i := 0; i < threshold-2; i++ {
+		v := make([]byte, len(secret))
+		if _, err := rand.Read(v); err != nil {
+			return nil, err
+		}
+		values[i] = v
+		xs = append(xs, i)
+		ys = append(ys, v)
+	}
+	digest := make([]byte, len(secret))
+	if _, err := rand.Read(digest[digestLength:]); err != nil {
+		return nil, err
+	}
+	copy(digest, secretDigest(digest[digestLength:], secret))
+	xs = append(xs, digestIndex, secretIndex)
+	ys = append(ys, digest, secret)
+
+	for i := threshold - 2; i < count; i++ {
+		values[i] = interpolate(xs, ys, i)
+	}
+	return values, nil
+}
+
+// recoverSecret interpolates the secret and checks its digest
+func recoverSecret(xs []int, ys [][]byte) ([]byte, error) {
+	if len(xs) == 1 {
+		return append([]byte{}, ys[0]...), nil
+	}
+	secret := interpolate(xs, ys, secretIndex)
+	digest := interpolate(xs, ys, digestIndex)
+	if !hmac.Equal(digest[:digestLength], secretDigest(digest[digestLength:], secret)) {
+		return nil, ErrInvalidDigest
+	}
+	return secret, nil
+}
+
+func secretDigest(key, secret []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(secret)
+	return h.Sum(nil)[:digestLength]
+}
+
+// GF(256) with the AES polynomial x^8 + x^4 + x^3 + x + 1
+var expTable, logTable = func() (e [255]int, l [256]int) {
+	poly := 1
+	for i := range e {
+		e[i] = poly
+		l[poly] = i
+		// Multiply by x + 1 and reduce
+		poly = (poly << 1) ^ poly
+		if poly&0x100 != 0 {
+			poly ^= 0x11b
+		}
+	}
+	return
+}()
+
+// interpolate evaluates at x the polynomial going through (xs[i], ys[i])
+func interpolate(xs []int, ys [][]byte, x int) []byte {
+	for i, xi := range xs {
+		if xi == x {
+			return append([]byte{}, ys[i]...)
+		}
+	}
+
+	logProd := 0
+	for _, xi := range xs {
+		logProd += logTable[xi^x]
+	}
+	res := make([]byte, len(ys[0]))
+	for i, xi := range xs {
+		logBasis := logProd - logTable[xi^x]
+		for _, xj := range xs {
+			logBasis -= logTable[xi^xj]
+		}
+		logBasis = ((logBasis % 255) + 255) % 255
+		for k, v := range ys[i] {
+			if v != 0 {
+				res[k] ^= byte(expTable[(logTable[v]+logBasis)%255])
+			}
+		}
+	}
+	return res
+}
+
+// encrypt applies the 4-round Feistel network to the master secret
+func encrypt(secret, passphrase []byte, e int, id uint16) []byte {
+	return feistel(secret, passphrase, e, salt(id, false), []int{0, 1, 2, 3})
+}
+
+func decrypt(ems, passphrase []byte, e int, id uint16, extendable bool) []byte {
+	return feistel(ems, passphrase, e, salt(id, extendable), []int{3, 2, 1, 0})
+}
+
+func salt(id uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	return []byte{'s', 'h', 'a', 'm', 'i', 'r', byte(id >> 8), byte(id)}
+}
+
+func feistel(in, passphrase []byte, e int, salt []byte, rounds []int) []byte {
+	n := len(in) / 2
+	l := append([]byte{}, in[:n]...)
+	r := append([]byte{}, in[n:]...)
+	iterations := (baseIterations << e) / roundCount
+	for _, i := range rounds {
+		f := pbkdf2.Key(append([]byte{byte(i)}, passphrase...),
+			append(append([]byte{}, salt...), r...), iterations, n, sha256.New)
+		for k := range f {
+			f[k] ^= l[k]
+		}
+		l, r = r, f
+	}
+	return append(r, l...)
+}
+
+func customization(extendable bool) string {
+	if extendable {
+		return "shamir_extendable"
+	}
+	return "shamir"
+}
+
+// rs1024 computes the checksum polynomial over the customization string and
+// the mnemonic words
+func rs1024(cs string, values []int) int {
+	gen := [10]int{
+		0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+		0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+	}
+	chk := 1
+	step := func(v int) {
+		b := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ v
+		for i := range gen {
+			if (b>>i)&1 != 0 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	for i := 0; i < len(cs); i++ {
+		step(int(cs[i]))
+	}
+	for _, v := range values {
+		step(v)
+	}
+	return chk
+}
+
+func encode(s *share) string {
+	ext := 0
+	if s.extendable {
+		ext = 1
+	}
+	idExp := int(s.id)<<5 | ext<<4 | s.exponent
+	params := (((s.groupIndex<<4|(s.groupThreshold-1))<<4|(s.groupCount-1))<<4|s.index)<<4 | (s.threshold - 1)
+	valueWords := (len(s.value)*8 + radixBits - 1) / radixBits
+
+	words := make([]int, 0, metadataWords+valueWords)
+	words = appendIndices(words, big.NewInt(int64(idExp)), idExpWords)
+	words = appendIndices(words, big.NewInt(int64(params)), paramsWords)
+	words = appendIndices(words, new(big.Int).SetBytes(s.value), valueWords)
+
+	chk := rs1024(customization(s.extendable), append(words, 0, 0, 0)) ^ 1
+	words = appendIndices(words, big.NewInt(int64(chk)), checksumWords)
+
+	res := make([]string, len(words))
+	for i, w := range words {
+		res[i] = wordlist[w]
+	}
+	return strings.Join(res, " ")
+}
+
+// appendIndices appends the n 10-bit words of v, most significant first
+func appendIndices(dst []int, v *big.Int, n int) []int {
+	mask := big.NewInt(1<<radixBits - 1)
+	w := new(big.Int)
+	for i := n - 1; i >= 0; i-- {
+		w.Rsh(v, uint(i*radixBits))
+		dst = append(dst, int(w.And(w, mask).Int64()))
+	}
+	return dst
+}
+
+var wordIndex = func() map[string]int {
+	m := make(map[string]int, len(wordlist))
+	for i, w := range wordlist {
+		m[w] = i
+	}
+	return m
+}()
+
+func decode(mnemonic string) (*share, error) {
+	fields := strings.Fields(strings.ToLower(mnemonic))
+	if len(fields) < minWords {
+		return nil, ErrInvalidMnemonic
+	}
+	words := make([]int, len(fields))
+	for i, f := range fields {
+		w, ok := wordIndex[f]
+		if !ok {
+			return nil, ErrInvalidMnemonic
+		}
+		words[i] = w
+	}
+
+	idExp := words[0]<<radixBits | words[1]
+	s := share{
+		id:         uint16(idExp >> 5),
+		extendable: (idExp>>4)&1 == 1,
+		exponent:   idExp & 0xf,
+	}
+	if rs1024(customization(s.extendable), words) != 1 {
+		return nil, ErrInvalidChecksum
+	}
+
+	params := words[2]<<radixBits | words[3]
+	s.groupIndex = params >> 16
+	s.groupThreshold = (params>>12)&0xf + 1
+	s.groupCount = (params>>8)&0xf + 1
+	s.index = (params >> 4) & 0xf
+	s.threshold = params&0xf + 1
+	if s.groupThreshold > s.groupCount {
+		return nil, ErrInvalidMnemonic
+	}
+
+	// The value is left padded with at most 8 zero bits
+	valueWords := words[idExpWords+paramsWords : len(words)-checksumWords]
+	padding := (radixBits * len(valueWords)) % 16
+	if padding > 8 {
+		return nil, ErrInvalidMnemonic
+	}
+	v := new(big.Int)
+	for _, w := range valueWords {
+		v.Lsh(v, radixBits)
+		v.Or(v, big.NewInt(int64(w)))
+	}
+	n := (radixBits*len(valueWords) - padding) / 8
+	if v.BitLen() > 8*n {
+		return nil, ErrInvalidMnemonic
+	}
+	s.value = v.FillBytes(make([]byte, n))
+
+	return &s, nil
+}
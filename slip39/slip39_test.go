@@ -0,0 +1,115 @@
+package slip39
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+// testdata/vectors.json holds cases of the reference implementation's
+// vectors.json (https://github.com/trezor/python-shamir-mnemonic), without
+// the BIP-32 master keys, which this package does not derive. Every case
+// uses the passphrase TREZOR, and an empty secret marks invalid mnemonics
+type vector struct {
+	description string
+	mnemonics   []string
+	secret      string
+}
+
+func (v *vector) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &[]interface{}{&v.description, &v.mnemonics, &v.secret})
+}
+
+func loadVectors(t *testing.T) []vector {
+	b, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []vector
+	if err := json.Unmarshal(b, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range loadVectors(t) {
+		secret, err := Combine(v.mnemonics, []byte("TREZOR"))
+		if v.secret == "" {
+			if err == nil {
+				t.Errorf("%s: accepted", v.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", v.description, err)
+			continue
+		}
+		if got := hex.EncodeToString(secret); got != v.secret {
+			t.Errorf("%s: got %s, want %s", v.description, got, v.secret)
+		}
+	}
+}
+
+// The invalid vectors must fail for the reason they were written for
+func TestInvalidVectors(t *testing.T) {
+	vectors := loadVectors(t)
+	for _, c := range []struct {
+		vector int
+		err    error
+	}{
+		{1, ErrInvalidChecksum},
+		{2, ErrInvalidMnemonic},
+		{4, ErrInsufficientShare},
+		{5, ErrMismatch},
+		{12, ErrInvalidDigest},
+	} {
+		v := vectors[c.vector]
+		if _, err := Combine(v.mnemonics, []byte("TREZOR")); !errors.Is(err, c.err) {
+			t.Errorf("%s: got %v, want %v", v.description, err, c.err)
+		}
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := bytes.Repeat([]byte{0xab}, 32)
+	mnemonics, err := Split(secret, 3, 5, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Combine([]string{mnemonics[4], mnemonics[1], mnemonics[2]}, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Fatal("secret differs")
+	}
+	if _, err := Combine(mnemonics[:2], []byte("passphrase")); !errors.Is(err, ErrInsufficientShare) {
+		t.Fatalf("got %v, want %v", err, ErrInsufficientShare)
+	}
+}
+
+func TestIterationExponentLimit(t *testing.T) {
+	// Split uses the default exponent, re-encode its share with another
+	mnemonics, err := Split(bytes.Repeat([]byte{1}, 16), 1, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := decode(mnemonics[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.exponent = 15
+	m := encode(s)
+
+	if _, err := Combine([]string{m}, nil); !errors.Is(err, ErrIterationExponent) {
+		t.Fatalf("got %v, want %v", err, ErrIterationExponent)
+	}
+	s.exponent = MaxIterationExponent + 1
+	if _, err := CombineMaxExponent([]string{encode(s)}, nil, MaxIterationExponent+1); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,255 @@
+// Package testvectors generates and loads known-answer test vectors for the
+// BLS12-381, BN254 and BLS12-377 instantiations, so other implementations can be
+// validated against this one.
+//
+// Every value is derived from a seed: the signer and adjudicator keys, and the
+// randomness r, are expand_message_xmd(seed, label) reduced modulo the group
+// order. Vectors are stored as JSON, with all byte strings hex encoded, using
+// the uncompressed encodings of the curve packages
+package testvectors
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	gnarkbls377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	gnarkbls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	gnarkbn "github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"github.com/poupas/bls-vess/bls12377"
+	"github.com/poupas/bls-vess/bn254"
+	"github.com/poupas/bls-vess/internal/scheme"
+	"github.com/poupas/bls-vess/vess"
+)
+
+var (
+	ErrDSTMismatch   = errors.New("testvectors: DST mismatch")
+	ErrCurveMismatch = errors.New("testvectors: curve mismatch")
+)
+
+// Labels used to derive values from a seed
+const (
+	labelSignerKey      = "VESS-KAT-SIGNER-KEY"
+	labelAdjudicatorKey = "VESS-KAT-ADJUDICATOR-KEY"
+	labelRandomness     = "VESS-KAT-R"
+)
+
+// Vector is a single known-answer test
+type Vector struct {
+	Seed                 string `json:"seed"`
+	Message              string `json:"message"`
+	SecretKey            string `json:"secret_key"`
+	PublicKey            string `json:"public_key"`
+	AdjudicatorSecretKey string `json:"adjudicator_secret_key"`
+	AdjudicatorPublicKey string `json:"adjudicator_public_key"`
+	R                    string `json:"r"`
+	VESig                string `json:"vesig"`
+	Signature            string `json:"signature"`
+}
+
+// File is a set of vectors sharing a curve and a domain separation tag
+type File struct {
+	Curve   string   `json:"curve"`
+	DST     string   `json:"dst"`
+	Vectors []Vector `json:"vectors"`
+}
+
+// Instance is an instantiation of the scheme vectors are computed with, see
+// BLS12381, BN254 and BLS12377
+type Instance interface {
+	Params() scheme.Params
+	escrow(seed, msg []byte) (*Vector, error)
+	verify(vec *Vector, msg []byte) error
+}
+
+type instance[G1, G2 any, P1 scheme.Point[G1], P2 scheme.Point[G2]] struct {
+	*scheme.Scheme[G1, G2, P1, P2]
+	params scheme.Params
+}
+
+// BLS12381 returns the instance for v
+func BLS12381(v *vess.VESS) Instance {
+	return &instance[gnarkbls.G1Affine, gnarkbls.G2Affine, *gnarkbls.G1Affine, *gnarkbls.G2Affine]{v.Scheme, v.Params()}
+}
+
+// BN254 returns the instance for v
+func BN254(v *bn254.VESS) Instance {
+	return &instance[gnarkbn.G1Affine, gnarkbn.G2Affine, *gnarkbn.G1Affine, *gnarkbn.G2Affine]{v.Scheme, v.Params()}
+}
+
+// BLS12377 returns the instance for v
+func BLS12377(v *bls12377.VESS) Instance {
+	return &instance[gnarkbls377.G1Affine, gnarkbls377.G2Affine, *gnarkbls377.G1Affine, *gnarkbls377.G2Affine]{v.Scheme, v.Params()}
+}
+
+func (in *instance[G1, G2, P1, P2]) Params() scheme.Params {
+	return in.params
+}
+
+// derive maps a seed to a non-zero scalar
+func derive(seed []byte, label string, order *big.Int) (*big.Int, error) {
+	b, err := vess.ExpandMsgXMD(seed, []byte(label), 48)
+	if err != nil {
+		return nil, err
+	}
+	x := new(big.Int).SetBytes(b)
+	x.Mod(x, order)
+	if x.Sign() == 0 {
+		return nil, errors.New("seed derives a zero scalar")
+	}
+	return x, nil
+}
+
+func (in *instance[G1, G2, P1, P2]) deriveKey(seed []byte, label string) (*scheme.SecretKey, error) {
+	x, err := derive(seed, label, in.params.Order)
+	if err != nil {
+		return nil, err
+	}
+	return in.SecretKeyFromBytes(x.FillBytes(make([]byte, scheme.ScalarSize)))
+}
+
+func (in *instance[G1, G2, P1, P2]) escrow(seed, msg []byte) (*Vector, error) {
+	sk, err := in.deriveKey(seed, labelSignerKey)
+	if err != nil {
+		return nil, err
+	}
+	adjSK, err := in.deriveKey(seed, labelAdjudicatorKey)
+	if err != nil {
+		return nil, err
+	}
+	r, err := derive(seed, labelRandomness, in.params.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	adjPK := in.AdjudicatorPublicKey(adjSK)
+	sig, err := in.SignWithRandomness(sk, adjPK, msg, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Vector{
+		Seed:                 hex.EncodeToString(seed),
+		Message:              hex.EncodeToString(msg),
+		SecretKey:            hex.EncodeToString(sk.Marshal()),
+		PublicKey:            hex.EncodeToString(in.PublicKey(sk).Marshal()),
+		AdjudicatorSecretKey: hex.EncodeToString(adjSK.Marshal()),
+		AdjudicatorPublicKey: hex.EncodeToString(adjPK.Marshal()),
+		R:                    hex.EncodeToString(r.FillBytes(make([]byte, scheme.ScalarSize))),
+		VESig:                hex.EncodeToString(sig.Marshal()),
+		Signature:            hex.EncodeToString(in.Adjudicate(adjSK, sig).Marshal()),
+	}, nil
+}
+
+// verify checks the escrow of vec, decoded from its stored encodings
+func (in *instance[G1, G2, P1, P2]) verify(vec *Vector, msg []byte) error {
+	pk := scheme.PublicKey[G1, P1]{}
+	if err := unmarshalHex(vec.PublicKey, &pk); err != nil {
+		return err
+	}
+	adj := scheme.AdjudicatorPublicKey[G1, G2, P1, P2]{}
+	if err := unmarshalHex(vec.AdjudicatorPublicKey, &adj); err != nil {
+		return err
+	}
+	sig := scheme.VESig[G2, P2]{}
+	if err := unmarshalHex(vec.VESig, &sig); err != nil {
+		return err
+	}
+	ok, err := in.Verify(&pk, &adj, msg, &sig)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid vesig for seed %s", vec.Seed)
+	}
+	return nil
+}
+
+// NewVector computes the vector for seed and msg
+func NewVector(in Instance, seed, msg []byte) (*Vector, error) {
+	return in.escrow(seed, msg)
+}
+
+// Generate computes one vector per (seed, message) pair, with the curve and
+// DST of in
+func Generate(in Instance, seeds, msgs [][]byte) (*File, error) {
+	if len(seeds) != len(msgs) {
+		return nil, errors.New("seeds and messages differ in length")
+	}
+	f := File{Curve: in.Params().Curve, DST: in.Params().DST}
+	for i := range seeds {
+		vec, err := NewVector(in, seeds[i], msgs[i])
+		if err != nil {
+			return nil, err
+		}
+		f.Vectors = append(f.Vectors, *vec)
+	}
+	return &f, nil
+}
+
+// Write stores f as indented JSON
+func (f *File) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// Load reads a vector file
+func Load(r io.Reader) (*File, error) {
+	f := File{}
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Check recomputes every vector of f from its seed and message, and verifies
+// the stored signatures. in must use the curve and DST the file was
+// generated with
+func Check(in Instance, f *File) error {
+	if f.Curve != in.Params().Curve {
+		return fmt.Errorf("%w: file uses %s, instance uses %s", ErrCurveMismatch, f.Curve, in.Params().Curve)
+	}
+	if f.DST != in.Params().DST {
+		return fmt.Errorf("%w: file uses %q, instance uses %q", ErrDSTMismatch, f.DST, in.Params().DST)
+	}
+	for i := range f.Vectors {
+		if err := checkVector(in, &f.Vectors[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkVector(in Instance, vec *Vector) error {
+	seed, err := hex.DecodeString(vec.Seed)
+	if err != nil {
+		return err
+	}
+	msg, err := hex.DecodeString(vec.Message)
+	if err != nil {
+		return err
+	}
+	want, err := NewVector(in, seed, msg)
+	if err != nil {
+		return err
+	}
+	if *want != *vec {
+		return fmt.Errorf("vector mismatch for seed %s", vec.Seed)
+	}
+
+	// The escrow must also pass verification
+	return in.verify(vec, msg)
+}
+
+func unmarshalHex(s string, u interface{ Unmarshal([]byte) error }) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return u.Unmarshal(b)
+}
@@ -0,0 +1,115 @@
+package testvectors
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/poupas/bls-vess/bls12377"
+	"github.com/poupas/bls-vess/bn254"
+	"github.com/poupas/bls-vess/vess"
+)
+
+var update = flag.Bool("update", false, "regenerate the vector files in testdata")
+
+// inputs are the seeds and messages of the committed vectors
+func inputs() (seeds, msgs [][]byte) {
+	for i, m := range []string{"", "Hello, World", "\x00\xff", "VESS known-answer test"} {
+		seeds = append(seeds, []byte(fmt.Sprintf("vess kat seed %d", i)))
+		msgs = append(msgs, []byte(m))
+	}
+	return seeds, msgs
+}
+
+// checkFile checks the vectors at path, after regenerating them with -update
+func checkFile(t *testing.T, in Instance, path string) {
+	if *update {
+		seeds, msgs := inputs()
+		f, err := Generate(in, seeds, msgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := bytes.Buffer{}
+		if err := f.Write(&b); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	f, err := Load(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Vectors) == 0 {
+		t.Fatal("no vectors")
+	}
+	if err := Check(in, f); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBLS12381(t *testing.T) {
+	v, err := vess.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFile(t, BLS12381(v), "testdata/bls12381.json")
+}
+
+func TestBN254(t *testing.T) {
+	v, err := bn254.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFile(t, BN254(v), "testdata/bn254.json")
+}
+
+func TestBLS12377(t *testing.T) {
+	v, err := bls12377.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFile(t, BLS12377(v), "testdata/bls12377.json")
+}
+
+func TestCheckRejectsMismatch(t *testing.T) {
+	v, err := vess.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seeds, msgs := inputs()
+	f, err := Generate(BLS12381(v), seeds[:1], msgs[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := vess.New(vess.WithDST([]byte("VESS-KAT-OTHER-DST")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(BLS12381(other), f); !errors.Is(err, ErrDSTMismatch) {
+		t.Fatalf("got %v, want %v", err, ErrDSTMismatch)
+	}
+	b, err := bn254.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(BN254(b), f); !errors.Is(err, ErrCurveMismatch) {
+		t.Fatalf("got %v, want %v", err, ErrCurveMismatch)
+	}
+
+	f.Vectors[0].Message = "00"
+	if err := Check(BLS12381(v), f); err == nil {
+		t.Fatal("tampered vector accepted")
+	}
+}
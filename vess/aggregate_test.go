@@ -0,0 +1,188 @@
+package vess
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// Batch and aggregate verification reuse pooled buffers: a call failing
+// half-way must not leak messages or points into the next one
+func TestAggregateReusesBuffers(t *testing.T) {
+	v := newVESS(t)
+	adjSK, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adj := v.AdjudicatorPublicKey(adjSK)
+
+	pks := make([]*PublicKey, 3)
+	msgs := make([][]byte, 3)
+	sigs := make([]*VESig, 3)
+	for i := range msgs {
+		sk, err := GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks[i], msgs[i] = v.PublicKey(sk), []byte(fmt.Sprintf("message %d", i))
+		if sigs[i], err = v.Sign(sk, adj, msgs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	agg := v.Aggregate(sigs...)
+
+	for i := 0; i < 3; i++ {
+		dup := [][]byte{msgs[0], msgs[1], msgs[0]}
+		if _, err := v.VerifyAggregate(context.Background(), pks, adj, dup, agg); !errors.Is(err, scheme.ErrDuplicateMessage) {
+			t.Fatalf("got %v, want %v", err, scheme.ErrDuplicateMessage)
+		}
+		if ok, err := v.VerifyAggregate(context.Background(), pks, adj, msgs, agg); err != nil || !ok {
+			t.Fatalf("valid aggregate rejected: %v", err)
+		}
+		if _, err := v.VerifyBatch(context.Background(), pks[0], adj, dup, sigs[0]); !errors.Is(err, scheme.ErrDuplicateMessage) {
+			t.Fatalf("got %v, want %v", err, scheme.ErrDuplicateMessage)
+		}
+	}
+}
+
+func TestBatchCancelled(t *testing.T) {
+	v := newVESS(t)
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adj := v.AdjudicatorPublicKey(sk)
+	msgs := [][]byte{[]byte("a"), []byte("b")}
+	sig, err := v.SignBatch(context.Background(), sk, adj, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := v.SignBatch(ctx, sk, adj, msgs); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SignBatch: got %v, want %v", err, context.Canceled)
+	}
+	if _, err := v.VerifyBatch(ctx, v.PublicKey(sk), adj, msgs, sig); !errors.Is(err, context.Canceled) {
+		t.Fatalf("VerifyBatch: got %v, want %v", err, context.Canceled)
+	}
+	agg := v.Aggregate(sig)
+	if _, err := v.VerifyAggregate(ctx, []*PublicKey{v.PublicKey(sk)}, adj, msgs[:1], agg); !errors.Is(err, context.Canceled) {
+		t.Fatalf("VerifyAggregate: got %v, want %v", err, context.Canceled)
+	}
+}
+
+// Parallel Miller loops give the same answers as a single one, whatever the
+// number of pairs per goroutine
+func TestParallelPairingCheck(t *testing.T) {
+	v := newVESS(t)
+	par, err := New(WithParallelism(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(WithParallelism(0)); err == nil {
+		t.Fatal("zero parallelism accepted")
+	}
+	adjSK, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adj := v.AdjudicatorPublicKey(adjSK)
+	ctx := context.Background()
+
+	var pks []*PublicKey
+	var msgs [][]byte
+	var sigs []*VESig
+	for n := 1; n <= 40; n++ {
+		sk, err := GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := []byte(fmt.Sprintf("message %d", n))
+		sig, err := v.Sign(sk, adj, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks, msgs, sigs = append(pks, v.PublicKey(sk)), append(msgs, msg), append(sigs, sig)
+		if n%7 != 1 && n != 40 {
+			continue
+		}
+
+		agg := v.Aggregate(sigs...)
+		if ok, err := par.VerifyAggregate(ctx, pks, adj, msgs, agg); err != nil || !ok {
+			t.Fatalf("valid aggregate of %d rejected: %v", n, err)
+		}
+		// Swapping two signers breaks the aggregate unless there is one
+		wrong := append([]*PublicKey{}, pks...)
+		wrong[0], wrong[n-1] = wrong[n-1], wrong[0]
+		want, err := v.VerifyAggregate(ctx, wrong, adj, msgs, agg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := par.VerifyAggregate(ctx, wrong, adj, msgs, agg); err != nil || got != want {
+			t.Fatalf("aggregate of %d: got %v, want %v (%v)", n, got, want, err)
+		}
+	}
+}
+
+func TestEncodeAggregateVESig(t *testing.T) {
+	v := newVESS(t)
+	adjSK, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adj := v.AdjudicatorPublicKey(adjSK)
+	pks := make([]*PublicKey, 3)
+	msgs := make([][]byte, 3)
+	sigs := make([]*VESig, 3)
+	for i := range msgs {
+		sk, err := GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks[i], msgs[i] = v.PublicKey(sk), []byte(fmt.Sprintf("message %d", i))
+		if sigs[i], err = v.Sign(sk, adj, msgs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := v.EncodeAggregateVESig(v.Aggregate(sigs...))
+	agg, err := v.DecodeAggregateVESig(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agg.Len() != len(sigs) {
+		t.Fatalf("decoded %d escrows, want %d", agg.Len(), len(sigs))
+	}
+	if ok, err := v.VerifyAggregate(context.Background(), pks, adj, msgs, agg); err != nil || !ok {
+		t.Fatalf("decoded aggregate rejected: %v", err)
+	}
+	if m, err := v.Migrate(b, Strict); err != nil || !bytes.Equal(m, b) {
+		t.Fatalf("migration changed the aggregate: %v", err)
+	}
+
+	if _, err := v.DecodeVESig(b); !errors.Is(err, scheme.ErrKindMismatch) {
+		t.Fatalf("aggregate decoded as a VESig: %v", err)
+	}
+	if _, err := v.DecodeAggregateVESig(v.EncodeVESig(sigs[0])); err == nil {
+		t.Fatal("VESig decoded as an aggregate")
+	}
+	if _, err := v.DecodeAggregateVESig(b[:len(b)-1]); err == nil {
+		t.Fatal("truncated aggregate accepted")
+	}
+}
+
+func TestAggregateRemoveEmpty(t *testing.T) {
+	v, _, sig := fixture(t)
+	agg := v.Aggregate()
+	if err := v.AggregateRemove(agg, sig); !errors.Is(err, scheme.ErrEmptyAggregate) {
+		t.Fatalf("got %v, want %v", err, scheme.ErrEmptyAggregate)
+	}
+	if agg.Len() != 0 {
+		t.Fatal("empty aggregate changed")
+	}
+}
@@ -0,0 +1,286 @@
+package vess
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+type benchEscrow struct {
+	v     *VESS
+	sk    *SecretKey
+	adjSK *SecretKey
+	pk    *PublicKey
+	adj   *AdjudicatorPublicKey
+	msg   []byte
+	sig   *VESig
+}
+
+func newBenchEscrow(b *testing.B) *benchEscrow {
+	v, err := New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	e := benchEscrow{v: v, msg: []byte("Hello, World")}
+	if e.sk, err = GenerateKey(); err != nil {
+		b.Fatal(err)
+	}
+	if e.adjSK, err = GenerateKey(); err != nil {
+		b.Fatal(err)
+	}
+	e.pk, e.adj = v.PublicKey(e.sk), v.AdjudicatorPublicKey(e.adjSK)
+	if e.sig, err = v.Sign(e.sk, e.adj, e.msg); err != nil {
+		b.Fatal(err)
+	}
+	return &e
+}
+
+// BenchmarkVerifyThenAdjudicate is the baseline for BenchmarkVerifyAndAdjudicate
+func BenchmarkVerifyThenAdjudicate(b *testing.B) {
+	e := newBenchEscrow(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ok, err := e.v.Verify(e.pk, e.adj, e.msg, e.sig); err != nil || !ok {
+			b.Fatal("invalid escrow")
+		}
+		if _, err := e.v.AdjudicateAndVerify(e.adjSK, e.pk, e.msg, e.sig); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyAndAdjudicate(b *testing.B) {
+	e := newBenchEscrow(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.v.VerifyAndAdjudicate(e.adjSK, e.pk, e.msg, e.sig); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Sizes of the batches and aggregates, and t-of-n committee sizes, the
+// benchmarks are run with. They match the bench command
+var (
+	benchBatchSizes = []int{1, 10, 100}
+	benchThresholds = [][2]int{{3, 5}, {7, 10}, {34, 50}}
+)
+
+func benchMessages(n int) [][]byte {
+	msgs := make([][]byte, n)
+	for i := range msgs {
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+	}
+	return msgs
+}
+
+func BenchmarkSign(b *testing.B) {
+	e := newBenchEscrow(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.v.Sign(e.sk, e.adj, e.msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	e := newBenchEscrow(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ok, err := e.v.Verify(e.pk, e.adj, e.msg, e.sig); err != nil || !ok {
+			b.Fatal("invalid escrow")
+		}
+	}
+}
+
+// Accepted, rejected and undecodable escrows should take the same time
+func BenchmarkVerifyConstantWork(b *testing.B) {
+	e := newBenchEscrow(b)
+	pk, adj, sig := e.pk.Marshal(), e.adj.Marshal(), e.sig.Marshal()
+	for _, c := range []struct {
+		name     string
+		msg, sig []byte
+	}{
+		{"accepted", e.msg, sig},
+		{"rejected", []byte("Goodbye, World"), sig},
+		{"undecodable", e.msg, make([]byte, len(sig))},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := e.v.VerifyConstantWork(pk, adj, c.msg, c.sig); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	e := newBenchEscrow(b)
+	for _, n := range benchBatchSizes {
+		msgs := benchMessages(n)
+		sig, err := e.v.SignBatch(context.Background(), e.sk, e.adj, msgs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if ok, err := e.v.VerifyBatch(context.Background(), e.pk, e.adj, msgs, sig); err != nil || !ok {
+					b.Fatal("invalid escrow")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyAggregate(b *testing.B) {
+	e := newBenchEscrow(b)
+	for _, n := range benchBatchSizes {
+		msgs := benchMessages(n)
+		pks := make([]*PublicKey, n)
+		sigs := make([]*VESig, n)
+		for i := range msgs {
+			sk, err := GenerateKey()
+			if err != nil {
+				b.Fatal(err)
+			}
+			pks[i] = e.v.PublicKey(sk)
+			if sigs[i], err = e.v.Sign(sk, e.adj, msgs[i]); err != nil {
+				b.Fatal(err)
+			}
+		}
+		agg := e.v.Aggregate(sigs...)
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if ok, err := e.v.VerifyAggregate(context.Background(), pks, e.adj, msgs, agg); err != nil || !ok {
+					b.Fatal("invalid aggregate")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAdjudicate(b *testing.B) {
+	e := newBenchEscrow(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.v.Adjudicate(e.adjSK, e.sig)
+	}
+}
+
+func BenchmarkCombine(b *testing.B) {
+	e := newBenchEscrow(b)
+	for _, tn := range benchThresholds {
+		t, n := tn[0], tn[1]
+		shares, err := e.v.SplitKey(e.adjSK, t, n)
+		if err != nil {
+			b.Fatal(err)
+		}
+		indices := make([]int, t)
+		partials := make([]*Partial, t)
+		for i := range partials {
+			indices[i] = i + 1
+			partials[i] = e.v.PartialAdjudicate(shares[i], e.sig)
+		}
+		b.Run(fmt.Sprintf("%d-of-%d", t, n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := e.v.Combine(e.sig, indices, partials); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Encoding benchmarks compare the uncompressed legacy encoding with the
+// compressed versioned one
+
+func BenchmarkMarshalVESig(b *testing.B) {
+	e := newBenchEscrow(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.sig.Marshal()
+	}
+}
+
+func BenchmarkUnmarshalVESig(b *testing.B) {
+	e := newBenchEscrow(b)
+	raw := e.sig.Marshal()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := new(VESig).Unmarshal(raw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeVESig(b *testing.B) {
+	e := newBenchEscrow(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.v.EncodeVESig(e.sig)
+	}
+}
+
+func BenchmarkDecodeVESig(b *testing.B) {
+	e := newBenchEscrow(b)
+	encoded := e.v.EncodeVESig(e.sig)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.v.DecodeVESig(encoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeCompressedVESig(b *testing.B) {
+	e := newBenchEscrow(b)
+	c := CompressVESig(e.sig)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.VESig(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkVerifyAggregateParallel splits the Miller loops across every CPU
+func BenchmarkVerifyAggregateParallel(b *testing.B) {
+	v, err := New(WithParallelism(runtime.NumCPU()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	e := newBenchEscrow(b)
+	for _, n := range benchBatchSizes {
+		msgs := benchMessages(n)
+		pks := make([]*PublicKey, n)
+		sigs := make([]*VESig, n)
+		for i := range msgs {
+			sk, err := GenerateKey()
+			if err != nil {
+				b.Fatal(err)
+			}
+			pks[i] = v.PublicKey(sk)
+			if sigs[i], err = v.Sign(sk, e.adj, msgs[i]); err != nil {
+				b.Fatal(err)
+			}
+		}
+		agg := v.Aggregate(sigs...)
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if ok, err := v.VerifyAggregate(context.Background(), pks, e.adj, msgs, agg); err != nil || !ok {
+					b.Fatal("invalid aggregate")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package vess
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"errors"
+	"sync"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// hashCache is a size-bounded LRU cache of hash-to-G2 results, safe for
+// concurrent use. Entries are keyed by a digest of the DST and message, so
+// messages are not retained
+type hashCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[[sha256.Size]byte]*list.Element
+}
+
+type hashCacheEntry struct {
+	key [sha256.Size]byte
+	h   gnark.G2Affine
+}
+
+// WithHashCache caches up to size hashed messages, which saves the
+// hash-to-curve cost when many signatures cover the same message
+func WithHashCache(size int) Option {
+	return func(v *VESS) error {
+		if size <= 0 {
+			return errors.New("invalid hash cache size")
+		}
+		v.cache = &hashCache{
+			size:  size,
+			ll:    list.New(),
+			items: make(map[[sha256.Size]byte]*list.Element),
+		}
+		return nil
+	}
+}
+
+func hashCacheKey(dst, msg []byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte{byte(len(dst))})
+	h.Write(dst)
+	h.Write(msg)
+	key := [sha256.Size]byte{}
+	h.Sum(key[:0])
+	return key
+}
+
+func (c *hashCache) get(key [sha256.Size]byte) (gnark.G2Affine, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return gnark.G2Affine{}, false
+	}
+	c.ll.MoveToFront(e)
+	return e.Value.(*hashCacheEntry).h, true
+}
+
+func (c *hashCache) add(key [sha256.Size]byte, h gnark.G2Affine) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&hashCacheEntry{key: key, h: h})
+	if c.ll.Len() > c.size {
+		e := c.ll.Back()
+		c.ll.Remove(e)
+		delete(c.items, e.Value.(*hashCacheEntry).key)
+	}
+}
@@ -0,0 +1,65 @@
+package vess
+
+import "runtime"
+
+// OpCost is the cost of an operation, in expensive primitive operations
+type OpCost struct {
+	HashToG2    int
+	G1Mul       int
+	G2Mul       int
+	MillerLoops int
+	FinalExps   int
+	// G2MSM is set for operations running one multi-scalar multiplication
+	// per committee member
+	G2MSM bool
+}
+
+// Capabilities describes the crypto configuration of a VESS instance, to be
+// logged at startup or audited
+type Capabilities struct {
+	Curve       string
+	Convention  string
+	Ciphersuite Ciphersuite
+	DST         string
+	// Backends for hashing to G2 and for group operations and pairings
+	HashBackend    string
+	PairingBackend string
+	// Cgo is always set: herumi's library is a cgo dependency
+	Cgo bool
+	// Assembly reports whether gnark's field arithmetic has assembly
+	// implementations on this architecture
+	Assembly bool
+	GOOS     string
+	GOARCH   string
+	Costs    map[string]OpCost
+}
+
+// Capabilities returns the configuration of v. It does not depend on runtime
+// measurements, so it is the same across runs on the same build
+func (v *VESS) Capabilities() Capabilities {
+	hash := "herumi (expand_message_xmd, SSWU)"
+	if v.expand != nil {
+		hash = "vess expand_message, herumi SSWU map"
+	}
+	return Capabilities{
+		Curve:          "BLS12-381",
+		Convention:     "minimal-pubkey-size (public keys in G1, signatures in G2)",
+		Ciphersuite:    v.Suite,
+		DST:            string(v.dst),
+		HashBackend:    hash,
+		PairingBackend: "gnark-crypto",
+		Cgo:            true,
+		Assembly:       runtime.GOARCH == "amd64",
+		GOOS:           runtime.GOOS,
+		GOARCH:         runtime.GOARCH,
+		Costs: map[string]OpCost{
+			"Sign":                {HashToG2: 1, G2Mul: 3},
+			"Verify":              {HashToG2: 1, MillerLoops: 3, FinalExps: 1},
+			"Adjudicate":          {G2Mul: 1},
+			"VerifyAndAdjudicate": {HashToG2: 1, G1Mul: 1, G2Mul: 1, MillerLoops: 3, FinalExps: 1},
+			"VerifyRecovered":     {HashToG2: 1, MillerLoops: 2, FinalExps: 1},
+			"PartialAdjudicate":   {G2Mul: 1},
+			"Combine":             {G2MSM: true},
+		},
+	}
+}
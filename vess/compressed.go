@@ -0,0 +1,117 @@
+package vess
+
+import (
+	"bytes"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// Fixed-size compressed encodings, for fixed-width storage and network frames
+type (
+	CompressedPubKeyBytes            [gnark.SizeOfG1AffineCompressed]byte
+	CompressedAdjudicatorPubKeyBytes [gnark.SizeOfG1AffineCompressed + gnark.SizeOfG2AffineCompressed]byte
+	CompressedSignatureBytes         [gnark.SizeOfG2AffineCompressed]byte
+	CompressedVESigBytes             [2 * gnark.SizeOfG2AffineCompressed]byte
+)
+
+// CompressPublicKey returns the compressed encoding of pk
+func CompressPublicKey(pk *PublicKey) CompressedPubKeyBytes {
+	p := pk.Point()
+	return p.Bytes()
+}
+
+// PublicKey decodes the public key
+func (b *CompressedPubKeyBytes) PublicKey() (*PublicKey, error) {
+	p := gnark.G1Affine{}
+	if err := setG1(&p, b[:]); err != nil {
+		return nil, err
+	}
+	return scheme.NewPublicKey[gnark.G1Affine, *gnark.G1Affine](p)
+}
+
+// CompressAdjudicatorPublicKey returns the compressed encoding of apk
+func CompressAdjudicatorPublicKey(apk *AdjudicatorPublicKey) CompressedAdjudicatorPubKeyBytes {
+	g1, g2 := apk.G1(), apk.G2()
+	b1, b2 := g1.Bytes(), g2.Bytes()
+	b := CompressedAdjudicatorPubKeyBytes{}
+	copy(b[:], b1[:])
+	copy(b[len(b1):], b2[:])
+	return b
+}
+
+// AdjudicatorPublicKey decodes the adjudicator public key, checking its
+// halves against each other on v's curve
+func (b *CompressedAdjudicatorPubKeyBytes) AdjudicatorPublicKey(v *VESS) (*AdjudicatorPublicKey, error) {
+	g1 := gnark.G1Affine{}
+	if err := setG1(&g1, b[:gnark.SizeOfG1AffineCompressed]); err != nil {
+		return nil, err
+	}
+	g2 := gnark.G2Affine{}
+	if err := setG2(&g2, b[gnark.SizeOfG1AffineCompressed:]); err != nil {
+		return nil, err
+	}
+	return v.NewAdjudicatorPublicKey(g1, g2)
+}
+
+// CompressSignature returns the compressed encoding of sig
+func CompressSignature(sig *Signature) CompressedSignatureBytes {
+	p := sig.Point()
+	return p.Bytes()
+}
+
+// Signature decodes the signature
+func (b *CompressedSignatureBytes) Signature() (*Signature, error) {
+	p := gnark.G2Affine{}
+	if err := setG2(&p, b[:]); err != nil {
+		return nil, err
+	}
+	return scheme.NewSignature[gnark.G2Affine, *gnark.G2Affine](p)
+}
+
+// CompressVESig returns the compressed encoding of sig
+func CompressVESig(sig *VESig) CompressedVESigBytes {
+	omega, mu := sig.Omega(), sig.Mu()
+	bo, bm := omega.Bytes(), mu.Bytes()
+	b := CompressedVESigBytes{}
+	copy(b[:], bo[:])
+	copy(b[len(bo):], bm[:])
+	return b
+}
+
+// VESig decodes the verifiably encrypted signature
+func (b *CompressedVESigBytes) VESig() (*VESig, error) {
+	omega := gnark.G2Affine{}
+	if err := setG2(&omega, b[:gnark.SizeOfG2AffineCompressed]); err != nil {
+		return nil, err
+	}
+	mu := gnark.G2Affine{}
+	if err := setG2(&mu, b[gnark.SizeOfG2AffineCompressed:]); err != nil {
+		return nil, err
+	}
+	return scheme.NewVESig[gnark.G2Affine, *gnark.G2Affine](omega, mu)
+}
+
+// setG1 and setG2 decode compressed points, rejecting every encoding but the
+// one Bytes returns. gnark reduces coordinates modulo p and ignores stray bits
+// of infinity, which would make encodings malleable
+func setG1(p *gnark.G1Affine, b []byte) error {
+	if _, err := p.SetBytes(b); err != nil {
+		return err
+	}
+	if c := p.Bytes(); !bytes.Equal(c[:], b) {
+		return scheme.ErrNonCanonical
+	}
+	return nil
+}
+
+func setG2(p *gnark.G2Affine, b []byte) error {
+	if _, err := p.SetBytes(b); err != nil {
+		return err
+	}
+	if c := p.Bytes(); !bytes.Equal(c[:], b) {
+		return scheme.ErrNonCanonical
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package vess
+
+import (
+	"testing"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+func TestVerifyConstantWork(t *testing.T) {
+	v := newVESS(t)
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adjSK, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adj := v.AdjudicatorPublicKey(adjSK)
+	msg := []byte("constant work")
+	sig, err := v.Sign(sk, adj, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkRaw, adjRaw, raw := v.PublicKey(sk).Marshal(), adj.Marshal(), sig.Marshal()
+
+	// omega on the curve, outside the subgroup
+	p := outsideSubgroup(t)
+	omega := p.RawBytes()
+	outside := append(omega[:], raw[len(raw)/2:]...)
+
+	// Identity keys, which Unmarshal rejects
+	g1 := infinity(gnark.SizeOfG1AffineUncompressed, gnark.SizeOfG1AffineUncompressed, 0x40)
+	g2 := infinity(gnark.SizeOfG2AffineUncompressed, gnark.SizeOfG2AffineUncompressed, 0x40)
+	n1 := len(pkRaw)
+
+	before := v.WorkTimings()
+	for _, c := range []struct {
+		name         string
+		pk, adj, sig []byte
+		msg          []byte
+		want         bool
+	}{
+		{"valid", pkRaw, adjRaw, raw, msg, true},
+		{"wrong message", pkRaw, adjRaw, raw, []byte("other"), false},
+		{"outside subgroup", pkRaw, adjRaw, outside, msg, false},
+		{"not on curve", pkRaw, adjRaw, make([]byte, len(raw)), msg, false},
+		{"truncated", pkRaw, adjRaw, raw[:len(raw)-1], msg, false},
+		{"compressed", pkRaw, adjRaw, v.EncodeVESig(sig), msg, false},
+		{"no adjudicator", pkRaw, nil, raw, msg, false},
+		{"identity public key", g1, adjRaw, raw, msg, false},
+		{"identity adjudicator G1", pkRaw, append(g1, adjRaw[n1:]...), raw, msg, false},
+		{"identity adjudicator G2", pkRaw, append(adjRaw[:n1:n1], g2...), raw, msg, false},
+		// All-identity keys and omega would pass the pairing check
+		{"identity forgery", g1, append(g1, adjRaw[n1:]...), append(g2, raw[len(raw)/2:]...), msg, false},
+	} {
+		ok, err := v.VerifyConstantWork(c.pk, c.adj, c.msg, c.sig)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if ok != c.want {
+			t.Fatalf("%s: got %v, want %v", c.name, ok, c.want)
+		}
+	}
+
+	after := v.WorkTimings()
+	if after.Calls-before.Calls != 11 {
+		t.Fatalf("%d calls timed, want 11", after.Calls-before.Calls)
+	}
+	if after.Pairing <= before.Pairing {
+		t.Fatal("pairing time not recorded")
+	}
+}
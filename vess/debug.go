@@ -0,0 +1,14 @@
+//go:build vessdebug
+
+package vess
+
+import "github.com/poupas/bls-vess/internal/scheme"
+
+type VerifyReport = scheme.VerifyReport
+
+// VerifyDebug is Scheme.VerifyDebug, with the DST in use in the report
+func (v *VESS) VerifyDebug(pk *PublicKey, adj *AdjudicatorPublicKey, msg []byte, sig *VESig) *VerifyReport {
+	r := v.Scheme.VerifyDebug(pk, adj, msg, sig)
+	r.DST = v.Params().DST
+	return r
+}
@@ -0,0 +1,36 @@
+package vess
+
+import (
+	"errors"
+
+	"github.com/herumi/bls-eth-go-binary/bls"
+
+	"github.com/poupas/bls-vess/convert"
+)
+
+var ErrNotEthereumSuite = errors.New("not using the Ethereum ciphersuite")
+
+// VerifyETH checks a recovered signature with herumi's Ethereum BLS
+// verification (draft07 ciphersuite, proof of possession), independently of
+// this package's pairing code. A signature accepted here is consumable by
+// Ethereum consensus clients. The instance must use the Ethereum
+// ciphersuite, without context, or recovered signatures would be on framed
+// messages
+func (v *VESS) VerifyETH(pk *PublicKey, msg []byte, sig *Signature) (bool, error) {
+	if v.expand != nil || v.context != nil {
+		return false, ErrNotEthereumSuite
+	}
+
+	hpk := bls.PublicKey{}
+	p := pk.Point()
+	if err := convert.G1FromGnark(bls.CastFromPublicKey(&hpk), &p); err != nil {
+		return false, err
+	}
+	hsig := bls.Sign{}
+	s := sig.Point()
+	if err := convert.G2FromGnark(bls.CastFromSign(&hsig), &s); err != nil {
+		return false, err
+	}
+
+	return hsig.VerifyByte(&hpk, msg), nil
+}
@@ -0,0 +1,71 @@
+package vess
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/herumi/bls-eth-go-binary/bls"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// Recovered signatures are the signatures herumi computes with the same key,
+// and verify as Ethereum signatures
+func TestRecoveredSignatureIsEthereumSignature(t *testing.T) {
+	v := newVESS(t)
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adjSK, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := v.PublicKey(sk)
+	msg := []byte("attestation data")
+	sig, err := v.Sign(sk, v.AdjudicatorPublicKey(adjSK), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigma := v.Adjudicate(adjSK, sig)
+
+	ok, err := v.VerifyETH(pk, msg, sigma)
+	if err != nil || !ok {
+		t.Fatalf("recovered signature rejected: %v", err)
+	}
+	if ok, _ := v.VerifyETH(pk, []byte("other data"), sigma); ok {
+		t.Fatal("recovered signature verifies for another message")
+	}
+
+	hsk := bls.SecretKey{}
+	if err := hsk.Deserialize(sk.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hsk.GetPublicKey().Serialize(), v.EncodePublicKey(pk)[scheme.HeaderSize:]; string(got) != string(want) {
+		t.Fatal("public key differs from herumi's")
+	}
+	if got, want := hsk.SignByte(msg).Serialize(), v.EncodeSignature(sigma)[scheme.HeaderSize:]; string(got) != string(want) {
+		t.Fatal("recovered signature differs from herumi's")
+	}
+}
+
+func TestVerifyETHRejectsOtherSuites(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"context": WithContext(Context{AppID: "app"}),
+		"xof":     WithHashSuite(SuiteXOFSHAKE256),
+		"dst":     WithDST([]byte("OTHER-DST")),
+	} {
+		v, err := New(opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sk, err := GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigma := v.Adjudicate(sk, &VESig{})
+		if _, err := v.VerifyETH(v.PublicKey(sk), nil, sigma); !errors.Is(err, ErrNotEthereumSuite) {
+			t.Errorf("%s: got %v, want %v", name, err, ErrNotEthereumSuite)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package vess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// The fault-injection tests run threshold adjudication with members that
+// misbehave. Adjudication must either recover the escrowed signature or fail
+// naming exactly the members whose partials were invalid: it must never
+// return a wrong signature, nor blame an honest member
+
+// fault is how a member misbehaves
+type fault int
+
+const (
+	honest fault = iota
+	// drop loses the member's message
+	drop
+	// corrupt answers with a partial computed from a wrong share
+	corrupt
+	// malformed answers with bytes that are not a point
+	malformed
+	// equivocate answers honestly the first time, then with a corrupt
+	// partial, so that combiners disagree
+	equivocate
+	// offline never answers, until the test ends
+	offline
+)
+
+var errDropped = errors.New("message dropped")
+
+// member is a share device that misbehaves as told
+type member struct {
+	share *SecretKey
+	fault fault
+	calls int32
+	// Closed when the test ends, to let offline members return
+	done chan struct{}
+}
+
+func (m *member) PartialAdjudicate(mu []byte) ([]byte, error) {
+	call := atomic.AddInt32(&m.calls, 1)
+	switch m.fault {
+	case drop:
+		return nil, errDropped
+	case malformed:
+		return []byte{1, 2, 3}, nil
+	case offline:
+		<-m.done
+		return nil, errDropped
+	}
+	p := gnark.G2Affine{}
+	if err := p.Unmarshal(mu); err != nil {
+		return nil, err
+	}
+	x := m.share.Scalar()
+	if m.fault == corrupt || (m.fault == equivocate && call > 1) {
+		x.Add(x, big.NewInt(1))
+	}
+	p.ScalarMultiplication(&p, x)
+	return p.Marshal(), nil
+}
+
+// faultyCommittee is a committee holding an adjudicator key, and an escrow
+// to adjudicate
+type faultyCommittee struct {
+	v      *VESS
+	c      *Committee
+	shares []*KeyShare
+	sig    *VESig
+	want   *Signature
+}
+
+// newFaultyCommittee returns a threshold-of-n committee
+func newFaultyCommittee(t *testing.T, threshold, n int) *faultyCommittee {
+	v := newVESS(t)
+	adjSK, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, vector, err := v.SplitKeyShares(adjSK, threshold, n, [32]byte{1}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("member %d", i+1)
+	}
+	c, err := v.NewCommittee([32]byte{1}, 1, ids, vector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := v.Sign(sk, v.AdjudicatorPublicKey(adjSK), []byte("escrow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &faultyCommittee{v: v, c: c, shares: shares, sig: sig, want: v.Adjudicate(adjSK, sig)}
+}
+
+// devices returns the members, by index, with faults[i] for the member at
+// index i+1. Missing faults are honest
+func (fc *faultyCommittee) devices(t *testing.T, faults ...fault) map[int]scheme.ShareDevice {
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	devices := map[int]scheme.ShareDevice{}
+	for i, ks := range fc.shares {
+		m := &member{share: ks.Secret, done: done}
+		if i < len(faults) {
+			m.fault = faults[i]
+		}
+		devices[ks.Index] = m
+	}
+	return devices
+}
+
+// adjudicate collects partials from devices and combines them
+func (fc *faultyCommittee) adjudicate(devices map[int]scheme.ShareDevice, timeout time.Duration) (*Signature, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	indices, partials, err := fc.v.CollectPartials(ctx, fc.c, fc.sig, devices)
+	if err != nil {
+		return nil, err
+	}
+	return fc.v.CombineCommittee(fc.c, fc.sig, indices, partials)
+}
+
+// check fails unless the outcome is the escrowed signature, or an error
+// matching want and blaming exactly blame
+func (fc *faultyCommittee) check(t *testing.T, name string, got *Signature, err, want error, blame []int) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if !sameSignature(got, fc.want) {
+			t.Errorf("%s: wrong signature", name)
+		}
+		return
+	}
+	if got != nil {
+		t.Errorf("%s: signature returned with %v", name, err)
+	}
+	if !errors.Is(err, want) {
+		t.Errorf("%s: got %v, want %v", name, err, want)
+		return
+	}
+	msg := want.Error()
+	if len(blame) > 0 {
+		msg = fmt.Sprintf("%v: invalid partials from members %v", want, blame)
+	}
+	if err.Error() != msg {
+		t.Errorf("%s: got %q, want %q", name, err, msg)
+	}
+}
+
+func TestFaultInjection(t *testing.T) {
+	fc := newFaultyCommittee(t, 3, 5)
+	for _, c := range []struct {
+		name   string
+		faults []fault
+		err    error
+		blame  []int
+	}{
+		{"honest", nil, nil, nil},
+		{"two dropped", []fault{drop, drop}, nil, nil},
+		{"two corrupt", []fault{honest, corrupt, honest, corrupt}, nil, nil},
+		{"corrupt and malformed", []fault{malformed, honest, corrupt}, nil, nil},
+		{"two offline", []fault{offline, honest, offline}, nil, nil},
+		{"three corrupt", []fault{corrupt, honest, corrupt, honest, corrupt}, scheme.ErrNotEnoughPartials, []int{1, 3, 5}},
+		{"corrupt, dropped and malformed", []fault{corrupt, drop, malformed}, scheme.ErrNotEnoughPartials, []int{1}},
+		{"three dropped", []fault{drop, drop, drop}, scheme.ErrNotEnoughPartials, nil},
+		{"three offline", []fault{offline, offline, offline}, context.DeadlineExceeded, nil},
+		{"offline and corrupt", []fault{offline, offline, corrupt}, context.DeadlineExceeded, nil},
+	} {
+		timeout := time.Minute
+		if c.err == context.DeadlineExceeded {
+			timeout = 100 * time.Millisecond
+		}
+		sig, err := fc.adjudicate(fc.devices(t, c.faults...), timeout)
+		fc.check(t, c.name, sig, err, c.err, c.blame)
+	}
+}
+
+// An equivocating member gives each combiner a different partial. The
+// combiner that gets the valid one succeeds, the other blames the member
+func TestFaultEquivocation(t *testing.T) {
+	fc := newFaultyCommittee(t, 3, 5)
+	devices := fc.devices(t, honest, equivocate, honest, drop, drop)
+	sig, err := fc.adjudicate(devices, time.Minute)
+	fc.check(t, "first combiner", sig, err, nil, nil)
+	sig, err = fc.adjudicate(devices, time.Minute)
+	fc.check(t, "second combiner", sig, err, scheme.ErrNotEnoughPartials, []int{2})
+
+	// Partials relayed to a single combiner: the corrupt one is caught
+	// before combining, and blamed on its sender
+	indices, partials := []int{}, []*Partial{}
+	for _, i := range []int{1, 2, 3} {
+		pa, err := fc.v.PartialAdjudicateDevice(devices[i], fc.sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		indices, partials = append(indices, i), append(partials, pa)
+	}
+	_, err = fc.v.CombineCommittee(fc.c, fc.sig, indices, partials)
+	if !errors.Is(err, scheme.ErrInvalidPartial) || err.Error() != scheme.ErrInvalidPartial.Error()+": member 2" {
+		t.Fatalf("got %v, want %v for member 2", err, scheme.ErrInvalidPartial)
+	}
+}
@@ -0,0 +1,168 @@
+package vess
+
+import (
+	"bytes"
+	"testing"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/herumi/bls-eth-go-binary/bls"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// The fuzz targets cross-check decoding against herumi: an encoding must be
+// accepted by both libraries or by neither, and accepted encodings must
+// re-encode to the same bytes
+
+// fixture returns an instance and a valid escrow
+func fixture(tb testing.TB) (*VESS, *PublicKey, *VESig) {
+	v, err := New()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sk, err := GenerateKey()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	adjSK, err := GenerateKey()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sig, err := v.Sign(sk, v.AdjudicatorPublicKey(adjSK), []byte("fuzz"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return v, v.PublicKey(sk), sig
+}
+
+// infinity returns n bytes of encodings of the point at infinity, each size
+// bytes long and starting with flags
+func infinity(n, size int, flags byte) []byte {
+	b := make([]byte, n)
+	for i := 0; i < n; i += size {
+		b[i] = flags
+	}
+	return b
+}
+
+// herumiG2 reports whether herumi accepts b as a signature: on the curve, in
+// the subgroup and canonical
+func herumiG2(b []byte, compressed bool) bool {
+	sig := bls.Sign{}
+	if compressed {
+		return sig.Deserialize(b) == nil
+	}
+	return sig.DeserializeUncompressed(b) == nil
+}
+
+// herumiKey reports whether herumi accepts b as a public key, and b is not
+// the identity, which vess rejects
+func herumiKey(b []byte, compressed bool) bool {
+	pk := bls.PublicKey{}
+	if compressed {
+		return pk.Deserialize(b) == nil && !pk.IsZero()
+	}
+	return pk.DeserializeUncompressed(b) == nil && !pk.IsZero()
+}
+
+func FuzzUnmarshalVESig(f *testing.F) {
+	_, _, sig := fixture(f)
+	f.Add(sig.Marshal())
+	f.Add(make([]byte, 2*gnark.SizeOfG2AffineUncompressed))
+	f.Add(infinity(2*gnark.SizeOfG2AffineUncompressed, gnark.SizeOfG2AffineUncompressed, 0x40))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		const n = gnark.SizeOfG2AffineUncompressed
+		sig := VESig{}
+		err := sig.Unmarshal(b)
+		want := len(b) == 2*n && herumiG2(b[:n], false) && herumiG2(b[n:], false)
+		if (err == nil) != want {
+			t.Fatalf("vess error %v, herumi accepts %t", err, want)
+		}
+		if err == nil && !bytes.Equal(sig.Marshal(), b) {
+			t.Fatal("accepted encoding does not round trip")
+		}
+	})
+}
+
+func FuzzUnmarshalPublicKey(f *testing.F) {
+	_, pk, _ := fixture(f)
+	f.Add(pk.Marshal())
+	f.Add(make([]byte, gnark.SizeOfG1AffineUncompressed))
+	f.Add(infinity(gnark.SizeOfG1AffineUncompressed, gnark.SizeOfG1AffineUncompressed, 0x40))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		pk := PublicKey{}
+		err := pk.Unmarshal(b)
+		want := len(b) == gnark.SizeOfG1AffineUncompressed && herumiKey(b, false)
+		if (err == nil) != want {
+			t.Fatalf("vess error %v, herumi accepts %t", err, want)
+		}
+		if err == nil && !bytes.Equal(pk.Marshal(), b) {
+			t.Fatal("accepted encoding does not round trip")
+		}
+	})
+}
+
+// FuzzDecodeVESig fuzzes the body of versioned VESigs: compressed points
+func FuzzDecodeVESig(f *testing.F) {
+	v, _, sig := fixture(f)
+	enc := v.EncodeVESig(sig)
+	header := enc[:scheme.HeaderSize]
+	f.Add(enc[scheme.HeaderSize:])
+	f.Add(make([]byte, 2*gnark.SizeOfG2AffineCompressed))
+	f.Add(infinity(2*gnark.SizeOfG2AffineCompressed, gnark.SizeOfG2AffineCompressed, 0xc0))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		const n = gnark.SizeOfG2AffineCompressed
+		b := append(append([]byte{}, header...), body...)
+		sig, err := v.DecodeVESig(b)
+		want := len(body) == 2*n && herumiG2(body[:n], true) && herumiG2(body[n:], true)
+		if (err == nil) != want {
+			t.Fatalf("vess error %v, herumi accepts %t", err, want)
+		}
+		if err == nil && !bytes.Equal(v.EncodeVESig(sig), b) {
+			t.Fatal("accepted encoding does not round trip")
+		}
+	})
+}
+
+func FuzzDecodePublicKey(f *testing.F) {
+	v, pk, _ := fixture(f)
+	enc := v.EncodePublicKey(pk)
+	header := enc[:scheme.HeaderSize]
+	f.Add(enc[scheme.HeaderSize:])
+	f.Add(make([]byte, gnark.SizeOfG1AffineCompressed))
+	f.Add(infinity(gnark.SizeOfG1AffineCompressed, gnark.SizeOfG1AffineCompressed, 0xc0))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		b := append(append([]byte{}, header...), body...)
+		pk, err := v.DecodePublicKey(b)
+		want := len(body) == gnark.SizeOfG1AffineCompressed && herumiKey(body, true)
+		if (err == nil) != want {
+			t.Fatalf("vess error %v, herumi accepts %t", err, want)
+		}
+		if err == nil && !bytes.Equal(v.EncodePublicKey(pk), b) {
+			t.Fatal("accepted encoding does not round trip")
+		}
+	})
+}
+
+// FuzzUnmarshalVESigLenient checks that lenient decoding accepts everything
+// strict decoding does, with the same result, and only canonical encodings
+func FuzzUnmarshalVESigLenient(f *testing.F) {
+	v, _, sig := fixture(f)
+	f.Add(sig.Marshal())
+	f.Add(make([]byte, 2*gnark.SizeOfG2AffineUncompressed))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		lenient, lerr := v.UnmarshalVESig(b, Lenient)
+		strict, serr := v.UnmarshalVESig(b, Strict)
+		if serr == nil && (lerr != nil || !lenient.Equal(strict)) {
+			t.Fatalf("strict accepts, lenient returns %v", lerr)
+		}
+		if lerr == nil && !bytes.Equal(lenient.Marshal(), b) {
+			t.Fatal("accepted encoding does not round trip")
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package vess
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/poupas/bls-vess/convert"
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// HashSuite selects how messages are hashed to G2
+type HashSuite int
+
+const (
+	// expand_message_xmd with SHA-256 (RFC 9380). Used by Ethereum
+	SuiteXMDSHA256 HashSuite = iota
+	// expand_message_xof with SHAKE-256 (RFC 9380)
+	SuiteXOFSHAKE256
+
+	// A custom expand function
+	suiteCustom HashSuite = -1
+)
+
+// Default domain separation tags for each suite
+const (
+	DSTXMDSHA256   = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	DSTXOFSHAKE256 = "BLS_SIG_BLS12381G2_XOF:SHAKE-256_SSWU_RO_POP_"
+)
+
+// ExpandFunc expands msg into n uniformly random bytes, bound to the domain
+// separation tag dst (see RFC 9380, section 5.3)
+type ExpandFunc func(msg, dst []byte, n int) ([]byte, error)
+
+// Option configures a VESS instance
+type Option func(*VESS) error
+
+// WithHashSuite selects the hash-to-G2 suite, along with its default DST
+func WithHashSuite(s HashSuite) Option {
+	return func(v *VESS) error {
+		v.suite = s
+		switch s {
+		case SuiteXMDSHA256:
+			v.expand = nil
+			v.dst = []byte(DSTXMDSHA256)
+		case SuiteXOFSHAKE256:
+			v.expand = ExpandMsgXOF
+			v.dst = []byte(DSTXOFSHAKE256)
+		default:
+			return errors.New("unknown hash suite")
+		}
+		return nil
+	}
+}
+
+// WithDST overrides the domain separation tag
+func WithDST(dst []byte) Option {
+	return func(v *VESS) error {
+		if len(dst) == 0 || len(dst) > 255 {
+			return errors.New("invalid DST length")
+		}
+		v.dst = append([]byte{}, dst...)
+		return nil
+	}
+}
+
+// WithExpander sets a custom expand function
+func WithExpander(f ExpandFunc) Option {
+	return func(v *VESS) error {
+		if f == nil {
+			return errors.New("nil expand function")
+		}
+		v.expand = f
+		v.suite = suiteCustom
+		return nil
+	}
+}
+
+// WithContext binds every escrow to an application context, see Context
+func WithContext(c Context) Option {
+	return func(v *VESS) error {
+		if err := c.Check(); err != nil {
+			return err
+		}
+		v.context = &c
+		return nil
+	}
+}
+
+// WithParallelism runs the Miller loops of large pairing checks, such as
+// aggregate verification, on up to n goroutines. The default is 1
+func WithParallelism(n int) Option {
+	return func(v *VESS) error {
+		return v.parallelism.Set(n)
+	}
+}
+
+// ciphersuite identifies the hash suite in versioned encodings. Custom DSTs
+// and expand functions can't be told apart by a single byte
+func (v *VESS) ciphersuite() scheme.Ciphersuite {
+	switch {
+	case v.suite == SuiteXMDSHA256 && string(v.dst) == DSTXMDSHA256:
+		return scheme.CiphersuiteBLS12381G2XMDSHA256
+	case v.suite == SuiteXOFSHAKE256 && string(v.dst) == DSTXOFSHAKE256:
+		return scheme.CiphersuiteBLS12381G2XOFSHAKE256
+	}
+	return scheme.CiphersuiteCustom
+}
+
+// ExpandMsgXMD implements expand_message_xmd with SHA-256
+// (RFC 9380, section 5.3.1)
+func ExpandMsgXMD(msg, dst []byte, n int) ([]byte, error) {
+	ell := (n + sha256.Size - 1) / sha256.Size
+	if ell > 255 || n > 65535 || len(dst) > 255 {
+		return nil, errors.New("invalid expand_message_xmd parameters")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(n >> 8), byte(n), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	out := make([]byte, 0, ell*sha256.Size)
+	out = append(out, bi...)
+	tmp := make([]byte, sha256.Size)
+	for i := 2; i <= ell; i++ {
+		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
+		for j := range tmp {
+			tmp[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(tmp)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+
+	return out[:n], nil
+}
+
+// ExpandMsgXOF implements expand_message_xof with SHAKE-256
+// (RFC 9380, section 5.3.2)
+func ExpandMsgXOF(msg, dst []byte, n int) ([]byte, error) {
+	if n > 65535 || len(dst) > 255 {
+		return nil, errors.New("invalid expand_message_xof parameters")
+	}
+
+	// H(msg || I2OSP(len_in_bytes, 2) || DST_prime, len_in_bytes)
+	h := sha3.NewShake256()
+	h.Write(msg)
+	h.Write([]byte{byte(n >> 8), byte(n)})
+	h.Write(dst)
+	h.Write([]byte{byte(len(dst))})
+	out := make([]byte, n)
+	if _, err := h.Read(out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// HashToG2 hashes msg to a point on G2 using the configured suite
+func (v *VESS) HashToG2(msg []byte) (gnark.G2Affine, error) {
+	if v.context != nil {
+		msg = v.context.Frame(msg)
+	}
+	if v.cache == nil {
+		return v.hashToG2(msg)
+	}
+
+	key := hashCacheKey(v.dst, msg)
+	if h, ok := v.cache.get(key); ok {
+		return h, nil
+	}
+	h, err := v.hashToG2(msg)
+	if err != nil {
+		return h, err
+	}
+	v.cache.add(key, h)
+	return h, nil
+}
+
+func (v *VESS) hashToG2(msg []byte) (gnark.G2Affine, error) {
+	h := gnark.G2Affine{}
+
+	// Herumi's built-in hash is the Ethereum suite. Use it when possible
+	if v.expand == nil {
+		err := convert.G2ToGnark(&h, bls.CastFromSign(bls.HashAndMapToSignature(msg)))
+		return h, err
+	}
+
+	// hash_to_field: two Fp2 elements, L = 64 bytes per Fp element
+	const L = 64
+	uniform, err := v.expand(msg, v.dst, 4*L)
+	if err != nil {
+		return h, err
+	}
+	if len(uniform) != 4*L {
+		return h, errors.New("expand function returned an invalid length")
+	}
+
+	// map_to_curve and clear_cofactor
+	// Herumi's map applies the SSWU map, the 3-isogeny and clears the cofactor.
+	// The isogeny and cofactor clearing are group homomorphisms, so adding the
+	// two mapped points is the same as adding them right after SSWU
+	q := [2]bls.G2{}
+	for i := range q {
+		u := bls.Fp2{}
+		for j := range u.D {
+			off := (2*i + j) * L
+			e := new(big.Int).SetBytes(uniform[off : off+L])
+			e.Mod(e, fp.Modulus())
+			if err := u.D[j].SetString(e.Text(16), 16); err != nil {
+				return h, err
+			}
+		}
+		if err := bls.MapToG2(&q[i], &u); err != nil {
+			return h, err
+		}
+	}
+	bls.G2Add(&q[0], &q[0], &q[1])
+
+	err = convert.G2ToGnark(&h, &q[0])
+	return h, err
+}
@@ -0,0 +1,281 @@
+package vess
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// The malleability tests mutate the encodings of valid escrows, and check
+// that no mutation decodes to the same escrow. Together with re-encoding
+// every accepted mutation to its own bytes, this shows each VESig has exactly
+// one accepted encoding in each format. The legacy and version 1 formats
+// share the uncompressed point encoding but are told apart by their length
+
+// vesigFormat is a VESig wire format. coords are the offsets of the Fp
+// coordinates in the encoding; the flags are in the first byte of each point
+type vesigFormat struct {
+	name   string
+	encode func(*VESig) []byte
+	decode func([]byte) (*VESig, error)
+	points []int
+	coords []int
+}
+
+func vesigFormats(v *VESS) []vesigFormat {
+	const (
+		h  = scheme.HeaderSize
+		n  = fp.Bytes
+		c2 = 2 * n
+		u2 = 4 * n
+	)
+	uncompressed := func(off int) []int {
+		return []int{off, off + n, off + 2*n, off + 3*n, off + u2, off + u2 + n, off + u2 + 2*n, off + u2 + 3*n}
+	}
+	compressed := func(off int) []int {
+		return []int{off, off + n, off + c2, off + c2 + n}
+	}
+	return []vesigFormat{
+		{
+			name:   "legacy",
+			encode: func(sig *VESig) []byte { return sig.Marshal() },
+			decode: func(b []byte) (*VESig, error) {
+				sig := VESig{}
+				return &sig, sig.Unmarshal(b)
+			},
+			points: []int{0, u2},
+			coords: uncompressed(0),
+		},
+		{
+			name:   "legacy lenient",
+			encode: func(sig *VESig) []byte { return sig.Marshal() },
+			decode: func(b []byte) (*VESig, error) { return v.UnmarshalVESig(b, Lenient) },
+			points: []int{0, u2},
+			coords: uncompressed(0),
+		},
+		{
+			name: "version 1",
+			encode: func(sig *VESig) []byte {
+				return append([]byte{scheme.Version1, byte(v.Suite), byte(scheme.KindVESig)}, sig.Marshal()...)
+			},
+			decode: v.DecodeVESig,
+			points: []int{h, h + u2},
+			coords: uncompressed(h),
+		},
+		{
+			name:   "version 2",
+			encode: v.EncodeVESig,
+			decode: v.DecodeVESig,
+			points: []int{h, h + c2},
+			coords: compressed(h),
+		},
+		{
+			name: "fixed compressed",
+			encode: func(sig *VESig) []byte {
+				b := CompressVESig(sig)
+				return b[:]
+			},
+			decode: func(b []byte) (*VESig, error) {
+				c := CompressedVESigBytes{}
+				if len(b) != len(c) {
+					return nil, scheme.ErrInvalidLength
+				}
+				copy(c[:], b)
+				return c.VESig()
+			},
+			points: []int{0, c2},
+			coords: compressed(0),
+		},
+	}
+}
+
+// checkMutation fails if b, which differs from the canonical encoding of sig,
+// decodes to sig, or decodes to an escrow it is not the encoding of
+func checkMutation(t *testing.T, f *vesigFormat, sig *VESig, b []byte, what string) {
+	t.Helper()
+	if bytes.Equal(b, f.encode(sig)) {
+		return
+	}
+	got, err := f.decode(b)
+	if err != nil {
+		return
+	}
+	if got.Equal(sig) {
+		t.Fatalf("%s: %s is another accepted encoding of the escrow", f.name, what)
+	}
+	if !bytes.Equal(f.encode(got), b) {
+		t.Fatalf("%s: %s is a non-canonical encoding", f.name, what)
+	}
+}
+
+func mutate(b []byte, f func(b []byte)) []byte {
+	m := append([]byte{}, b...)
+	f(m)
+	return m
+}
+
+func TestMalleabilityBitFlips(t *testing.T) {
+	v, _, sig := fixture(t)
+	for _, f := range vesigFormats(v) {
+		b := f.encode(sig)
+		if _, err := f.decode(b); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		for i := range b {
+			for bit := 0; bit < 8; bit++ {
+				m := mutate(b, func(m []byte) { m[i] ^= 1 << bit })
+				checkMutation(t, &f, sig, m, "a bit flip")
+			}
+		}
+	}
+}
+
+func TestMalleabilityFlags(t *testing.T) {
+	v, _, sig := fixture(t)
+	for _, f := range vesigFormats(v) {
+		b := f.encode(sig)
+		for _, off := range f.points {
+			for flags := 0; flags < 8; flags++ {
+				m := mutate(b, func(m []byte) { m[off] = m[off]&^mMask | byte(flags)<<5 })
+				checkMutation(t, &f, sig, m, "a flag combination")
+			}
+			// The point at infinity, with and without stray bits
+			for _, flags := range []byte{mInfinity, 0b110 << 5} {
+				for _, stray := range []bool{false, true} {
+					m := mutate(b, func(m []byte) {
+						end := len(m)
+						if i := pointIndex(f.points, off); i+1 < len(f.points) {
+							end = f.points[i+1]
+						}
+						for j := off; j < end; j++ {
+							m[j] = 0
+						}
+						m[off] = flags
+						if stray {
+							m[end-1] = 1
+						}
+					})
+					checkMutation(t, &f, sig, m, "an infinity encoding")
+				}
+			}
+		}
+	}
+}
+
+func pointIndex(points []int, off int) int {
+	for i, p := range points {
+		if p == off {
+			return i
+		}
+	}
+	return -1
+}
+
+// Coordinates are accepted only when reduced modulo p. Adding p fits in the
+// coordinate, below the flags, for about a quarter of them, so several
+// escrows are tried
+func TestMalleabilityUnreducedCoordinates(t *testing.T) {
+	v, r := newVESS(t), propRand(t)
+	limit := new(big.Int).Lsh(big.NewInt(1), 8*fp.Bytes-3)
+	tried := 0
+	for i := 0; i < propRounds; i++ {
+		sk, adjSK := randKey(t, v, r), randKey(t, v, r)
+		sig, err := v.SignWithRandomness(sk, v.AdjudicatorPublicKey(adjSK), randMsg(r), randScalar(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range vesigFormats(v) {
+			b := f.encode(sig)
+			for _, off := range f.coords {
+				flags := byte(0)
+				if pointIndex(f.points, off) >= 0 {
+					flags = b[off] & mMask
+				}
+				x := new(big.Int).SetBytes(append([]byte{b[off] &^ flags}, b[off+1:off+fp.Bytes]...))
+				x.Add(x, fp.Modulus())
+				if x.Cmp(limit) >= 0 {
+					continue
+				}
+				tried++
+				m := mutate(b, func(m []byte) {
+					x.FillBytes(m[off : off+fp.Bytes])
+					m[off] |= flags
+				})
+				checkMutation(t, &f, sig, m, "an unreduced coordinate")
+			}
+		}
+	}
+	if tried == 0 {
+		t.Fatal("no coordinate could be unreduced")
+	}
+	t.Logf("%d unreduced coordinates", tried)
+}
+
+func TestMalleabilityLength(t *testing.T) {
+	v, _, sig := fixture(t)
+	for _, f := range vesigFormats(v) {
+		b := f.encode(sig)
+		checkMutation(t, &f, sig, append(append([]byte{}, b...), 0), "a trailing byte")
+		checkMutation(t, &f, sig, b[:len(b)-1], "a truncated encoding")
+	}
+}
+
+func TestMalleabilityHeader(t *testing.T) {
+	v, _, sig := fixture(t)
+	for _, f := range vesigFormats(v) {
+		if f.name != "version 1" && f.name != "version 2" {
+			continue
+		}
+		b := f.encode(sig)
+		for i := 0; i < scheme.HeaderSize; i++ {
+			for x := 0; x < 256; x++ {
+				m := mutate(b, func(m []byte) { m[i] = byte(x) })
+				checkMutation(t, &f, sig, m, "a header")
+			}
+		}
+	}
+}
+
+// The identity is a valid point but never a valid key or signature: it
+// would verify signatures of the identity under any message
+func TestIdentityRejected(t *testing.T) {
+	v := newVESS(t)
+	g1 := infinity(gnark.SizeOfG1AffineUncompressed, gnark.SizeOfG1AffineUncompressed, 0x40)
+	g2 := infinity(gnark.SizeOfG2AffineUncompressed, gnark.SizeOfG2AffineUncompressed, 0x40)
+	for name, unmarshal := range map[string]func() error{
+		"public key":              func() error { return new(PublicKey).Unmarshal(g1) },
+		"adjudicator public key":  func() error { return new(AdjudicatorPublicKey).Unmarshal(append(g1, g2...)) },
+		"signature":               func() error { return new(Signature).Unmarshal(g2) },
+		"strict public key":       func() error { _, err := v.UnmarshalPublicKey(g1, Strict); return err },
+		"lenient public key":      func() error { _, err := v.UnmarshalPublicKey(g1, Lenient); return err },
+		"strict adjudicator key":  func() error { _, err := v.UnmarshalAdjudicatorPublicKey(append(g1, g2...), Strict); return err },
+		"lenient adjudicator key": func() error { _, err := v.UnmarshalAdjudicatorPublicKey(append(g1, g2...), Lenient); return err },
+		"strict signature":        func() error { _, err := v.UnmarshalSignature(g2, Strict); return err },
+		"lenient signature":       func() error { _, err := v.UnmarshalSignature(g2, Lenient); return err },
+	} {
+		if err := unmarshal(); !errors.Is(err, scheme.ErrIdentity) {
+			t.Errorf("%s: got %v, want %v", name, err, scheme.ErrIdentity)
+		}
+	}
+
+	// All zeros with the compressed and infinity flags set
+	pk := CompressedPubKeyBytes{0xc0}
+	if _, err := pk.PublicKey(); !errors.Is(err, scheme.ErrIdentity) {
+		t.Errorf("compressed public key: got %v, want %v", err, scheme.ErrIdentity)
+	}
+	apk := CompressedAdjudicatorPubKeyBytes{0xc0}
+	apk[gnark.SizeOfG1AffineCompressed] = 0xc0
+	if _, err := apk.AdjudicatorPublicKey(v); !errors.Is(err, scheme.ErrIdentity) {
+		t.Errorf("compressed adjudicator key: got %v, want %v", err, scheme.ErrIdentity)
+	}
+	sig := CompressedSignatureBytes{0xc0}
+	if _, err := sig.Signature(); !errors.Is(err, scheme.ErrIdentity) {
+		t.Errorf("compressed signature: got %v, want %v", err, scheme.ErrIdentity)
+	}
+}
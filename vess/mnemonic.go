@@ -0,0 +1,20 @@
+package vess
+
+import "github.com/poupas/bls-vess/slip39"
+
+// Mnemonics exports sk as SLIP-0039 mnemonics, any t of n of which restore
+// it. Use 1-of-1 for a single word list. This works for key shares and
+// backup sub-shares too: their metadata is public and is not included
+func Mnemonics(sk *SecretKey, t, n int, passphrase []byte) ([]string, error) {
+	return slip39.Split(sk.Marshal(), t, n, passphrase)
+}
+
+// SecretKeyFromMnemonics restores a secret key exported by Mnemonics,
+// validating the mnemonic checksums
+func (v *VESS) SecretKeyFromMnemonics(mnemonics []string, passphrase []byte) (*SecretKey, error) {
+	b, err := slip39.Combine(mnemonics, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return v.SecretKeyFromBytes(b)
+}
@@ -0,0 +1,25 @@
+package vess
+
+import "testing"
+
+func TestMnemonics(t *testing.T) {
+	v := newVESS(t)
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mnemonics, err := Mnemonics(sk, 2, 3, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := v.SecretKeyFromMnemonics(mnemonics[1:], []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(sk) {
+		t.Fatal("restored key differs")
+	}
+	if _, err := v.SecretKeyFromMnemonics(mnemonics[:1], []byte("passphrase")); err == nil {
+		t.Fatal("restored a key below the threshold")
+	}
+}
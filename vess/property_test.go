@@ -0,0 +1,220 @@
+package vess
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The property tests check algebraic invariants on keys, messages and
+// randomness drawn from a seed, which is logged so failures can be replayed
+// with -prop.seed
+
+var propSeed = flag.Int64("prop.seed", 0, "seed of the property tests, random if zero")
+
+const propRounds = 8
+
+func propRand(t *testing.T) *rand.Rand {
+	seed := *propSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	t.Logf("seed %d", seed)
+	return rand.New(rand.NewSource(seed))
+}
+
+func randScalar(r *rand.Rand) *big.Int {
+	b := make([]byte, 48)
+	r.Read(b)
+	x := new(big.Int).SetBytes(b)
+	x.Mod(x, fr.Modulus())
+	if x.Sign() == 0 {
+		x.SetInt64(1)
+	}
+	return x
+}
+
+func randKey(t *testing.T, v *VESS, r *rand.Rand) *SecretKey {
+	sk, err := v.SecretKeyFromBytes(randScalar(r).FillBytes(make([]byte, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk
+}
+
+func randMsg(r *rand.Rand) []byte {
+	msg := make([]byte, r.Intn(64))
+	r.Read(msg)
+	return msg
+}
+
+func newVESS(t *testing.T) *VESS {
+	v, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func sameSignature(a, b *Signature) bool {
+	return bytes.Equal(a.Marshal(), b.Marshal())
+}
+
+// Escrows verify, and only for their message and adjudicator
+func TestPropertySignVerify(t *testing.T) {
+	v, r := newVESS(t), propRand(t)
+	for i := 0; i < propRounds; i++ {
+		sk, adjSK, otherSK := randKey(t, v, r), randKey(t, v, r), randKey(t, v, r)
+		pk, adj := v.PublicKey(sk), v.AdjudicatorPublicKey(adjSK)
+		msg := randMsg(r)
+		sig, err := v.SignWithRandomness(sk, adj, msg, randScalar(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ok, err := v.Verify(pk, adj, msg, sig); err != nil || !ok {
+			t.Fatalf("valid escrow rejected: %v", err)
+		}
+		if ok, _ := v.Verify(pk, adj, append(msg, 0), sig); ok {
+			t.Fatal("escrow verifies for another message")
+		}
+		if ok, _ := v.Verify(pk, v.AdjudicatorPublicKey(otherSK), msg, sig); ok {
+			t.Fatal("escrow verifies for another adjudicator")
+		}
+		if ok, _ := v.Verify(v.PublicKey(otherSK), adj, msg, sig); ok {
+			t.Fatal("escrow verifies for another signer")
+		}
+	}
+}
+
+// Adjudication recovers a valid BLS signature, which does not depend on the
+// randomness of the escrow
+func TestPropertyAdjudicate(t *testing.T) {
+	v, r := newVESS(t), propRand(t)
+	for i := 0; i < propRounds; i++ {
+		sk, adjSK := randKey(t, v, r), randKey(t, v, r)
+		pk, adj := v.PublicKey(sk), v.AdjudicatorPublicKey(adjSK)
+		msg := randMsg(r)
+		sig1, err := v.SignWithRandomness(sk, adj, msg, randScalar(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig2, err := v.SignWithRandomness(sk, adj, msg, randScalar(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig1.Equal(sig2) {
+			t.Fatal("escrows with different randomness are equal")
+		}
+
+		sigma1, sigma2 := v.Adjudicate(adjSK, sig1), v.Adjudicate(adjSK, sig2)
+		if ok, err := v.VerifyRecovered(pk, msg, sigma1); err != nil || !ok {
+			t.Fatalf("recovered signature rejected: %v", err)
+		}
+		if !sameSignature(sigma1, sigma2) {
+			t.Fatal("recovered signature depends on the randomness")
+		}
+		if ok, err := v.VerifyETH(pk, msg, sigma1); err != nil || !ok {
+			t.Fatalf("recovered signature rejected by herumi: %v", err)
+		}
+	}
+}
+
+// Aggregates verify, add and remove commute, and adjudicating an aggregate
+// sums the recovered signatures
+func TestPropertyAggregate(t *testing.T) {
+	v, r := newVESS(t), propRand(t)
+	adjSK := randKey(t, v, r)
+	adj := v.AdjudicatorPublicKey(adjSK)
+	for i := 0; i < propRounds; i++ {
+		n := 1 + r.Intn(5)
+		pks := make([]*PublicKey, n)
+		msgs := make([][]byte, n)
+		sigs := make([]*VESig, n)
+		for j := range sigs {
+			sk := randKey(t, v, r)
+			pks[j] = v.PublicKey(sk)
+			msgs[j] = append(randMsg(r), byte(j))
+			sig, err := v.SignWithRandomness(sk, adj, msgs[j], randScalar(r))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sigs[j] = sig
+		}
+
+		agg := v.Aggregate(sigs...)
+		if ok, err := v.VerifyAggregate(context.Background(), pks, adj, msgs, agg); err != nil || !ok {
+			t.Fatalf("valid aggregate rejected: %v", err)
+		}
+
+		// Adding and removing an escrow leaves the aggregate unchanged
+		extra := sigs[r.Intn(n)]
+		v.AggregateAdd(agg, extra)
+		if err := v.AggregateRemove(agg, extra); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := v.VerifyAggregate(context.Background(), pks, adj, msgs, agg); err != nil || !ok {
+			t.Fatalf("aggregate rejected after add and remove: %v", err)
+		}
+
+		sigma, err := v.AdjudicateAggregate(context.Background(), adjSK, pks, msgs, agg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := gnark.G2Affine{}
+		for _, sig := range sigs {
+			p := v.Adjudicate(adjSK, sig).Point()
+			sum.Add(&sum, &p)
+		}
+		if p := sigma.Point(); !p.Equal(&sum) {
+			t.Fatal("aggregate adjudication differs from the sum of adjudications")
+		}
+	}
+}
+
+// Any t of n partial adjudications recover the signature, and fewer do not
+func TestPropertyThresholdCombine(t *testing.T) {
+	v, r := newVESS(t), propRand(t)
+	for i := 0; i < propRounds; i++ {
+		n := 2 + r.Intn(6)
+		th := 2 + r.Intn(n-1)
+		sk, adjSK := randKey(t, v, r), randKey(t, v, r)
+		sig, err := v.SignWithRandomness(sk, v.AdjudicatorPublicKey(adjSK), randMsg(r), randScalar(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares, err := v.SplitKey(adjSK, th, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := v.Adjudicate(adjSK, sig)
+
+		// A random subset of t share indices, in random order
+		perm := r.Perm(n)[:th]
+		indices := make([]int, th)
+		partials := make([]*Partial, th)
+		for j, k := range perm {
+			indices[j] = k + 1
+			partials[j] = v.PartialAdjudicate(shares[k], sig)
+		}
+		got, err := v.Combine(sig, indices, partials)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameSignature(got, want) {
+			t.Fatalf("%d-of-%d combine with %v differs from adjudication", th, n, indices)
+		}
+
+		got, err = v.Combine(sig, indices[1:], partials[1:])
+		if err == nil && sameSignature(got, want) {
+			t.Fatalf("%d-of-%d combine with %d shares recovers the signature", th, n, th-1)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package vess
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// The concurrency tests share instances, keys, escrows and committees
+// between goroutines. Run them with -race
+
+const raceGoroutines = 8
+
+// parallel runs f from raceGoroutines goroutines and waits for them
+func parallel(f func(g int)) {
+	wg := sync.WaitGroup{}
+	for g := 0; g < raceGoroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			f(g)
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestConcurrentNew(t *testing.T) {
+	parallel(func(int) {
+		if _, err := New(WithHashSuite(SuiteXOFSHAKE256)); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestConcurrentSignVerifyAdjudicate(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithHashCache(4)}} {
+		v, err := New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sk, err := GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		adjSK, err := GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, adj := v.PublicKey(sk), v.AdjudicatorPublicKey(adjSK)
+		shared, err := v.Sign(sk, adj, []byte("shared"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parallel(func(g int) {
+			// Distinct messages, and one every goroutine uses, so the
+			// hash cache is both filled and hit concurrently
+			msg := []byte(fmt.Sprintf("message %d", g%3))
+			sig, err := v.Sign(sk, adj, msg)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, e := range []struct {
+				msg []byte
+				sig *VESig
+			}{{msg, sig}, {[]byte("shared"), shared}} {
+				if ok, err := v.Verify(pk, adj, e.msg, e.sig); err != nil || !ok {
+					t.Errorf("valid escrow rejected: %v", err)
+				}
+				sigma := v.Adjudicate(adjSK, e.sig)
+				if ok, err := v.VerifyRecovered(pk, e.msg, sigma); err != nil || !ok {
+					t.Errorf("recovered signature rejected: %v", err)
+				}
+				if _, err := v.VerifyAndAdjudicate(adjSK, pk, e.msg, e.sig); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestConcurrentAggregate(t *testing.T) {
+	v := newVESS(t)
+	adjSK, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adj := v.AdjudicatorPublicKey(adjSK)
+	pks := make([]*PublicKey, 4)
+	msgs := make([][]byte, 4)
+	sigs := make([]*VESig, 4)
+	for i := range msgs {
+		sk, err := GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks[i], msgs[i] = v.PublicKey(sk), []byte(fmt.Sprintf("message %d", i))
+		if sigs[i], err = v.Sign(sk, adj, msgs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	agg := v.Aggregate(sigs...)
+
+	// Verification pools its buffers: every goroutine must see its own
+	parallel(func(g int) {
+		n := 1 + g%len(msgs)
+		part := v.Aggregate(sigs[:n]...)
+		if ok, err := v.VerifyAggregate(context.Background(), pks[:n], adj, msgs[:n], part); err != nil || !ok {
+			t.Errorf("valid aggregate of %d rejected: %v", n, err)
+		}
+		if _, err := v.AdjudicateAggregate(context.Background(), adjSK, pks, msgs, agg); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestConcurrentCommittee(t *testing.T) {
+	v := newVESS(t)
+	adjSK, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, vector, err := v.SplitKeyShares(adjSK, 3, 5, [32]byte{1}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := v.NewCommittee([32]byte{1}, 1, []string{"a", "b", "c", "d", "e"}, vector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := v.Sign(sk, v.AdjudicatorPublicKey(adjSK), []byte("escrow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := v.Adjudicate(adjSK, sig)
+
+	parallel(func(g int) {
+		if err := v.CheckCommittee(c); err != nil {
+			t.Error(err)
+			return
+		}
+		// Each goroutine combines a different quorum
+		indices := make([]int, 3)
+		partials := make([]*Partial, 3)
+		for i := range indices {
+			ks := shares[(g+i)%len(shares)]
+			if err := v.CheckKeyShare(c, ks); err != nil {
+				t.Error(err)
+				return
+			}
+			indices[i], partials[i] = ks.Index, v.PartialAdjudicate(ks.Secret, sig)
+			if ok, err := v.VerifyPartial(c, ks.Index, sig, partials[i]); err != nil || !ok {
+				t.Errorf("valid partial rejected: %v", err)
+			}
+		}
+		got, err := v.CombineCommittee(c, sig, indices, partials)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !sameSignature(got, want) {
+			t.Error("combined signature differs")
+		}
+	})
+}
+
+func TestConcurrentDecode(t *testing.T) {
+	v, _, sig := fixture(t)
+	raw, encoded := sig.Marshal(), v.EncodeVESig(sig)
+	before := v.DecodeStats()
+	parallel(func(int) {
+		if _, err := v.UnmarshalVESig(raw, Lenient); err != nil {
+			t.Error(err)
+		}
+		if _, err := v.DecodeVESig(encoded); err != nil {
+			t.Error(err)
+		}
+	})
+	if got := v.DecodeStats().Lenient - before.Lenient; got != raceGoroutines {
+		t.Fatalf("%d lenient decodes counted, want %d", got, raceGoroutines)
+	}
+}
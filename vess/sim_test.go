@@ -0,0 +1,272 @@
+package vess
+
+import (
+	"container/heap"
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// The simulator runs threshold adjudications deterministically. N virtual
+// members, with faults drawn from a seeded RNG, answer a combiner through a
+// network that delays and drops messages, under a virtual clock. Every event
+// is written to a trace: a seed always gives the same trace, so a failing
+// run is replayed with
+//
+//	go test -run TestSimulator -sim.seed=N -v
+//
+// Key material comes from crypto/rand and is kept out of the trace
+
+var simSeed = flag.Int64("sim.seed", 0, "replay this simulator seed, with its trace")
+
+const (
+	simRuns     = 40
+	simMembers  = 7
+	simMaxDelay = 200 * time.Millisecond
+	simTimeout  = time.Second
+	// One message in simDropRate is lost
+	simDropRate = 10
+)
+
+type simKind int
+
+const (
+	simRequest simKind = iota
+	simPartial
+	simDeadline
+)
+
+// simEvent is a message delivery, or the combiner's deadline
+type simEvent struct {
+	at    time.Duration
+	seq   int
+	kind  simKind
+	index int
+	pa    *Partial
+}
+
+// simQueue orders events by time, then by scheduling order
+type simQueue []*simEvent
+
+func (q simQueue) Len() int { return len(q) }
+func (q simQueue) Less(i, j int) bool {
+	return q[i].at < q[j].at || (q[i].at == q[j].at && q[i].seq < q[j].seq)
+}
+func (q simQueue) Swap(i, j int)         { q[i], q[j] = q[j], q[i] }
+func (q *simQueue) Push(x interface{})   { *q = append(*q, x.(*simEvent)) }
+func (q *simQueue) Pop() (x interface{}) { x, *q = (*q)[len(*q)-1], (*q)[:len(*q)-1]; return x }
+
+type simulator struct {
+	fc      *faultyCommittee
+	rng     *rand.Rand
+	now     time.Duration
+	seq     int
+	queue   simQueue
+	trace   []string
+	devices map[int]*member
+}
+
+// simResult is the outcome of a run
+type simResult struct {
+	sig   *Signature
+	err   error
+	trace []string
+	// Faults drawn for each member, the members whose first partial reached
+	// the combiner, by fault, and the members blamed
+	faults    map[int]fault
+	delivered map[fault][]int
+	blamed    []int
+}
+
+func (s *simulator) logf(format string, args ...interface{}) {
+	s.trace = append(s.trace, fmt.Sprintf("%5dms ", s.now.Milliseconds())+fmt.Sprintf(format, args...))
+}
+
+// send schedules the delivery of a message, unless the network drops it
+func (s *simulator) send(e *simEvent, what string) {
+	if s.rng.Intn(simDropRate) == 0 {
+		s.logf("%s %d dropped", what, e.index)
+		return
+	}
+	e.at = s.now + time.Duration(s.rng.Int63n(int64(simMaxDelay)))
+	s.seq++
+	e.seq = s.seq
+	heap.Push(&s.queue, e)
+}
+
+// simulate runs one adjudication with the given seed
+func simulate(t *testing.T, fc *faultyCommittee, seed int64) *simResult {
+	s := &simulator{fc: fc, rng: rand.New(rand.NewSource(seed)), devices: map[int]*member{}}
+	res := &simResult{faults: map[int]fault{}, delivered: map[fault][]int{}}
+
+	// Members are drawn in index order, so that the draws do not depend on
+	// map iteration
+	for _, ks := range fc.shares {
+		f := honest
+		switch r := s.rng.Intn(10); {
+		case r == 0:
+			f = corrupt
+		case r == 1:
+			f = offline
+		case r == 2:
+			f = equivocate
+		}
+		s.devices[ks.Index] = &member{share: ks.Secret, fault: f}
+		res.faults[ks.Index] = f
+		s.logf("member %d is %s", ks.Index, f)
+	}
+
+	s.seq++
+	heap.Push(&s.queue, &simEvent{at: simTimeout, seq: s.seq, kind: simDeadline})
+	for _, ks := range fc.shares {
+		s.send(&simEvent{kind: simRequest, index: ks.Index}, "request to")
+	}
+
+	indices, partials, faulty := []int{}, []*Partial{}, []int{}
+	for s.queue.Len() > 0 {
+		e := heap.Pop(&s.queue).(*simEvent)
+		s.now = e.at
+		switch e.kind {
+		case simDeadline:
+			s.logf("deadline, %d of %d partials", len(indices), fc.c.Threshold)
+			res.err, res.blamed = scheme.ErrNotEnoughPartials, faulty
+			res.trace = s.trace
+			return res
+		case simRequest:
+			m := s.devices[e.index]
+			if m.fault == offline {
+				s.logf("member %d ignores the request", e.index)
+				continue
+			}
+			pa, err := fc.v.PartialAdjudicateDevice(m, fc.sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// A second answer from an equivocating member is corrupt
+			s.logf("member %d answers", e.index)
+			s.send(&simEvent{kind: simPartial, index: e.index, pa: pa}, "partial from")
+			if m.fault == equivocate {
+				pa, err := fc.v.PartialAdjudicateDevice(m, fc.sig)
+				if err != nil {
+					t.Fatal(err)
+				}
+				s.logf("member %d answers again", e.index)
+				s.send(&simEvent{kind: simPartial, index: e.index, pa: pa}, "partial from")
+			}
+		case simPartial:
+			if contains(indices, e.index) || contains(faulty, e.index) {
+				s.logf("partial from %d ignored, already received", e.index)
+				continue
+			}
+			res.delivered[res.faults[e.index]] = append(res.delivered[res.faults[e.index]], e.index)
+			ok, err := fc.v.VerifyPartial(fc.c, e.index, fc.sig, e.pa)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				s.logf("partial from %d rejected", e.index)
+				faulty = insertSorted(faulty, e.index)
+				continue
+			}
+			s.logf("partial from %d accepted", e.index)
+			indices, partials = append(indices, e.index), append(partials, e.pa)
+			if len(indices) == fc.c.Threshold {
+				res.sig, res.err = fc.v.CombineCommittee(fc.c, fc.sig, indices, partials)
+				s.logf("combined from %v", indices)
+				res.trace = s.trace
+				return res
+			}
+		}
+	}
+	t.Fatal("the deadline never fired")
+	return nil
+}
+
+func (f fault) String() string {
+	return [...]string{"honest", "dropping", "corrupt", "malformed", "equivocating", "offline"}[f]
+}
+
+func contains(s []int, x int) bool {
+	for _, y := range s {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
+
+func insertSorted(s []int, x int) []int {
+	i := 0
+	for i < len(s) && s[i] < x {
+		i++
+	}
+	return append(s[:i], append([]int{x}, s[i:]...)...)
+}
+
+// checkRun checks the invariants of a run: adjudication recovers the
+// escrowed signature, or fails blaming members whose partials were invalid,
+// and nobody else
+func checkRun(t *testing.T, fc *faultyCommittee, seed int64, res *simResult) {
+	fail := func(format string, args ...interface{}) {
+		t.Errorf("seed %d: %s\n%s", seed, fmt.Sprintf(format, args...), strings.Join(res.trace, "\n"))
+	}
+	if res.err == nil {
+		if !sameSignature(res.sig, fc.want) {
+			fail("wrong signature")
+		}
+		return
+	}
+	if res.sig != nil {
+		fail("signature returned with %v", res.err)
+	}
+	// Corrupt members are blamed once their partial arrives, and
+	// equivocating ones if their corrupt partial arrives first
+	for _, i := range res.blamed {
+		if f := res.faults[i]; f != corrupt && f != equivocate {
+			fail("%s member %d blamed", f, i)
+		}
+	}
+	for _, i := range res.delivered[corrupt] {
+		if !contains(res.blamed, i) {
+			fail("corrupt member %d not blamed", i)
+		}
+	}
+	if len(res.delivered[honest]) >= fc.c.Threshold {
+		fail("%d honest partials delivered, but no signature", len(res.delivered[honest]))
+	}
+}
+
+func TestSimulator(t *testing.T) {
+	fc := newFaultyCommittee(t, simMembers/2+1, simMembers)
+	seeds := []int64{}
+	for i := int64(1); i <= simRuns; i++ {
+		seeds = append(seeds, i)
+	}
+	if *simSeed != 0 {
+		seeds = []int64{*simSeed}
+	}
+
+	outcomes := map[bool]int{}
+	for _, seed := range seeds {
+		res := simulate(t, fc, seed)
+		checkRun(t, fc, seed, res)
+		outcomes[res.err == nil]++
+		if *simSeed != 0 {
+			t.Logf("seed %d:\n%s", seed, strings.Join(res.trace, "\n"))
+		}
+
+		// Replaying the seed gives the same trace
+		replay := simulate(t, fc, seed)
+		if strings.Join(replay.trace, "\n") != strings.Join(res.trace, "\n") {
+			t.Fatalf("seed %d: replay differs:\n%s\n\n%s", seed, strings.Join(res.trace, "\n"), strings.Join(replay.trace, "\n"))
+		}
+	}
+	if *simSeed == 0 && (outcomes[true] == 0 || outcomes[false] == 0) {
+		t.Fatalf("the seeds only exercise one outcome: %v", outcomes)
+	}
+}
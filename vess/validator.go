@@ -0,0 +1,49 @@
+package vess
+
+import (
+	"bytes"
+	"errors"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+	"github.com/poupas/bls-vess/keystore"
+)
+
+var ErrValidatorKeyMismatch = errors.New("secret key does not match the validator public key")
+
+// ImportValidatorKey returns the signer key of an Ethereum validator, from
+// its 32-byte big-endian secret key, after checking it against the
+// validator's 48-byte compressed public key, as deposited on chain. The
+// instance must use the Ethereum ciphersuite, without context, so that
+// recovered signatures verify as Ethereum signatures, see VerifyETH.
+//
+// Escrowed messages must never be signing roots of the validator's duties:
+// a conflicting attestation or block signed this way is slashable
+func (v *VESS) ImportValidatorKey(secret, validatorPubkey []byte) (*SecretKey, *PublicKey, error) {
+	if v.expand != nil || v.context != nil {
+		return nil, nil, ErrNotEthereumSuite
+	}
+	if len(validatorPubkey) != gnark.SizeOfG1AffineCompressed {
+		return nil, nil, scheme.ErrInvalidLength
+	}
+	sk, err := v.SecretKeyFromBytes(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	pk := v.PublicKey(sk)
+	if c := CompressPublicKey(pk); !bytes.Equal(c[:], validatorPubkey) {
+		return nil, nil, ErrValidatorKeyMismatch
+	}
+	return sk, pk, nil
+}
+
+// ImportValidatorKeystore is ImportValidatorKey for an EIP-2335 keystore,
+// checked against its own pubkey field
+func (v *VESS) ImportValidatorKeystore(ks *keystore.Keystore, password string) (*SecretKey, *PublicKey, error) {
+	secret, err := keystore.Decrypt(ks, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.ImportValidatorKey(secret, ks.Pubkey)
+}
@@ -0,0 +1,144 @@
+package vess
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+	"github.com/poupas/bls-vess/keystore"
+)
+
+// The keystores of the EIP-2335 test vectors, both holding this key
+const (
+	vectorPassword = "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+	vectorSecret   = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+)
+
+func loadKeystore(t *testing.T, name string) *keystore.Keystore {
+	b, err := os.ReadFile(filepath.Join("..", "keystore", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := &keystore.Keystore{}
+	if err := json.Unmarshal(b, ks); err != nil {
+		t.Fatal(err)
+	}
+	return ks
+}
+
+func TestImportValidatorKeystoreVectors(t *testing.T) {
+	v := newVESS(t)
+	for _, name := range []string{"scrypt.json", "pbkdf2.json"} {
+		ks := loadKeystore(t, name)
+		sk, pk, err := v.ImportValidatorKeystore(ks, vectorPassword)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if hex.EncodeToString(sk.Marshal()) != vectorSecret {
+			t.Fatalf("%s: got secret key %x", name, sk.Marshal())
+		}
+		if c := CompressPublicKey(pk); hex.EncodeToString(c[:]) != hex.EncodeToString(ks.Pubkey) {
+			t.Fatalf("%s: got public key %x", name, c)
+		}
+		if _, _, err := v.ImportValidatorKeystore(ks, "testpassword"); !errors.Is(err, keystore.ErrChecksum) {
+			t.Fatalf("%s: got %v, want %v", name, err, keystore.ErrChecksum)
+		}
+	}
+}
+
+// fastKDFs are the KDFs of the package, with costs low enough for tests
+func fastKDFs(t *testing.T) []keystore.KDF {
+	s, err := keystore.NewScrypt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.N = 1 << 10
+	p, err := keystore.NewPBKDF2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.C = 1 << 10
+	a, err := keystore.NewArgon2id(1<<10, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []keystore.KDF{s, p, a}
+}
+
+func TestImportValidatorKeystoreRoundTrip(t *testing.T) {
+	v := newVESS(t)
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := CompressPublicKey(v.PublicKey(sk))
+	for _, kdf := range fastKDFs(t) {
+		ks, err := keystore.Encrypt(sk.Marshal(), pk[:], "p\u00e4ssw\u00f6rd \U0001f511", kdf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Through JSON, as keystores are stored
+		b, err := json.Marshal(ks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ks = &keystore.Keystore{}
+		if err := json.Unmarshal(b, ks); err != nil {
+			t.Fatal(err)
+		}
+
+		// The password is normalized: its decomposed form decrypts too
+		got, gotPK, err := v.ImportValidatorKeystore(ks, "pa\u0308sswo\u0308rd \U0001f511")
+		if err != nil {
+			t.Fatalf("%s: %v", kdf.Function(), err)
+		}
+		if !sk.Equal(got) || CompressPublicKey(gotPK) != pk {
+			t.Fatalf("%s: imported another key", kdf.Function())
+		}
+		if _, _, err := v.ImportValidatorKeystore(ks, "password"); !errors.Is(err, keystore.ErrChecksum) {
+			t.Fatalf("%s: got %v, want %v", kdf.Function(), err, keystore.ErrChecksum)
+		}
+	}
+}
+
+func TestImportValidatorKeyErrors(t *testing.T) {
+	v := newVESS(t)
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := CompressPublicKey(v.PublicKey(sk))
+	otherPK := CompressPublicKey(v.PublicKey(other))
+
+	if _, _, err := v.ImportValidatorKey(sk.Marshal(), otherPK[:]); !errors.Is(err, ErrValidatorKeyMismatch) {
+		t.Errorf("got %v, want %v", err, ErrValidatorKeyMismatch)
+	}
+	if _, _, err := v.ImportValidatorKey(sk.Marshal(), pk[:47]); !errors.Is(err, scheme.ErrInvalidLength) {
+		t.Errorf("got %v, want %v", err, scheme.ErrInvalidLength)
+	}
+
+	// A keystore whose pubkey field is another key
+	ks, err := keystore.Encrypt(sk.Marshal(), otherPK[:], "password", fastKDFs(t)[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.ImportValidatorKeystore(ks, "password"); !errors.Is(err, ErrValidatorKeyMismatch) {
+		t.Errorf("got %v, want %v", err, ErrValidatorKeyMismatch)
+	}
+
+	ctx, err := New(WithContext(Context{AppID: "app"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ctx.ImportValidatorKey(sk.Marshal(), pk[:]); !errors.Is(err, ErrNotEthereumSuite) {
+		t.Errorf("got %v, want %v", err, ErrNotEthereumSuite)
+	}
+}
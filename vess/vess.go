@@ -9,42 +9,289 @@
 package vess
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	// TODO: remove dependency on gnark. Herumi's bls is enough
+	"github.com/consensys/gnark-crypto/ecc"
 	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 
 	"github.com/herumi/bls-eth-go-binary/bls"
+
+	"github.com/poupas/bls-vess/convert"
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+type (
+	SecretKey            = scheme.SecretKey
+	PublicKey            = scheme.PublicKey[gnark.G1Affine, *gnark.G1Affine]
+	MinSigPublicKey      = scheme.MinSigPublicKey[gnark.G2Affine, *gnark.G2Affine]
+	AdjudicatorPublicKey = scheme.AdjudicatorPublicKey[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	Signature            = scheme.Signature[gnark.G2Affine, *gnark.G2Affine]
+	VESig                = scheme.VESig[gnark.G2Affine, *gnark.G2Affine]
+	NestedVESig          = scheme.NestedVESig[gnark.G2Affine, *gnark.G2Affine]
+	AggregateVESig       = scheme.AggregateVESig[gnark.G2Affine, *gnark.G2Affine]
+	Partial              = scheme.Partial[gnark.G2Affine, *gnark.G2Affine]
+	PartialVESig         = scheme.PartialVESig[gnark.G2Affine, *gnark.G2Affine]
+	RNonce               = scheme.RNonce[gnark.G2Affine, *gnark.G2Affine]
+	RTranscript          = scheme.RTranscript[gnark.G2Affine, *gnark.G2Affine]
+	KeyShare             = scheme.KeyShare
+	BackupShare          = scheme.BackupShare
+	Member               = scheme.Member
+	Committee            = scheme.Committee[gnark.G1Affine, *gnark.G1Affine]
+	AdjudicationPreview  = scheme.AdjudicationPreview[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	Transition           = scheme.Transition[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	ReEscrowProof        = scheme.ReEscrowProof[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	Receipt              = scheme.Receipt[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	AdjudicationRequest  = scheme.AdjudicationRequest[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	AdjudicationReceipt  = scheme.AdjudicationReceipt[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	ConditionsBinding    = scheme.ConditionsBinding[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	HashLock             = scheme.HashLock[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	Swap                 = scheme.Swap[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	Bundle               = scheme.Bundle[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+	BlindedMu            = scheme.BlindedMu[gnark.G2Affine, *gnark.G2Affine]
+	BlindingFactor       = scheme.BlindingFactor
+	Context              = scheme.Context
+	Params               = scheme.Params
+	Fingerprint          = scheme.Fingerprint
+	Strictness           = scheme.Strictness
+	WorkTimings          = scheme.WorkTimings
+	Header               = scheme.Header
+	Ciphersuite          = scheme.Ciphersuite
+	Kind                 = scheme.Kind
+)
+
+const (
+	Strict  = scheme.Strict
+	Lenient = scheme.Lenient
+
+	KindVESig       = scheme.KindVESig
+	KindPartial     = scheme.KindPartial
+	KindKeyShare    = scheme.KindKeyShare
+	KindBackupShare = scheme.KindBackupShare
+
+	KindPublicKey            = scheme.KindPublicKey
+	KindAdjudicatorPublicKey = scheme.KindAdjudicatorPublicKey
+	KindSignature            = scheme.KindSignature
+	KindAggregateVESig       = scheme.KindAggregateVESig
 )
 
+// VESS is immutable once New returns, and safe for concurrent use
 type VESS struct {
-	g1 gnark.G1Affine
-	g2 gnark.G2Affine
+	*scheme.Scheme[gnark.G1Affine, gnark.G2Affine, *gnark.G1Affine, *gnark.G2Affine]
+
+	// Hash-to-G2 parameters. A nil expand function selects herumi's built-in
+	// Ethereum hash
+	dst    []byte
+	expand ExpandFunc
+	suite  HashSuite
+	// Optional application context, framing every hashed message
+	context *Context
+
+	// Optional cache of hashed messages
+	cache *hashCache
+	// Goroutines running Miller loops, see WithParallelism
+	parallelism scheme.Parallelism
+}
+
+// Herumi's configuration is global. It is set once, so that instances can be
+// created concurrently
+var (
+	herumiOnce sync.Once
+	herumiErr  error
+)
+
+func initHerumi() error {
+	herumiOnce.Do(func() {
+		if herumiErr = bls.Init(bls.BLS12_381); herumiErr != nil {
+			return
+		}
+		if herumiErr = bls.SetETHmode(bls.EthModeDraft07); herumiErr != nil {
+			return
+		}
+		bls.VerifyPublicKeyOrder(true)
+		bls.VerifySignatureOrder(true)
+	})
+	return herumiErr
 }
 
-func New() (*VESS, error) {
-	if err := bls.Init(bls.BLS12_381); err != nil {
+func New(opts ...Option) (*VESS, error) {
+	if err := initHerumi(); err != nil {
 		return nil, err
 	}
-	if err := bls.SetETHmode(bls.EthModeDraft07); err != nil {
-		return nil, err
+
+	v := &VESS{dst: []byte(DSTXMDSHA256)}
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+	// Herumi's built-in hash only supports the Ethereum DST
+	if v.expand == nil && string(v.dst) != DSTXMDSHA256 {
+		v.expand = ExpandMsgXMD
 	}
-	bls.VerifyPublicKeyOrder(true)
-	bls.VerifySignatureOrder(true)
 
 	// Fetch G1 and G2 generators (affine coordinates)
 	_, _, g1, g2 := gnark.Generators()
 
-	return &VESS{g1: g1, g2: g2}, nil
+	v.Scheme = scheme.New(scheme.Curve[gnark.G1Affine, gnark.G2Affine]{
+		Suite:        v.ciphersuite(),
+		Order:        fr.Modulus(),
+		G1Gen:        g1,
+		G2Gen:        g2,
+		Hash:         v.HashToG2,
+		PairingCheck: gnark.PairingCheck,
+		AppendG1:     appendG1,
+		AppendG2:     appendG2,
+
+		AppendG1Compressed: appendG1Compressed,
+		AppendG2Compressed: appendG2Compressed,
+		DecodeG1Compressed: setG1,
+		DecodeG2Compressed: setG2,
+
+		DecodeG1Lenient: decodeG1Lenient,
+		DecodeG2Lenient: decodeG2Lenient,
+		MultiExpG2:      multiExpG2,
+
+		ParallelPairingCheck: scheme.ParallelPairingCheck[gnark.G1Affine, gnark.G2Affine, gnark.GT](v.parallelism, gnark.MillerLoop, gnark.FinalExponentiation),
+	})
+
+	return v, nil
+}
+
+// Params returns the curve parameters and the DST in use
+func (v *VESS) Params() Params {
+	return Params{
+		Curve:          "BLS12-381",
+		Order:          fr.Modulus(),
+		FieldModulus:   fp.Modulus(),
+		G1Compressed:   gnark.SizeOfG1AffineCompressed,
+		G1Uncompressed: gnark.SizeOfG1AffineUncompressed,
+		G2Compressed:   gnark.SizeOfG2AffineCompressed,
+		G2Uncompressed: gnark.SizeOfG2AffineUncompressed,
+		ScalarSize:     scheme.ScalarSize,
+		DST:            string(v.dst),
+	}
+}
+
+// ParseFingerprint decodes a fingerprint, validating its checksum
+func ParseFingerprint(s string) (Fingerprint, error) {
+	return scheme.ParseFingerprint(s)
+}
+
+// NewRTranscript starts a commit-reveal session for the signing set indices
+func NewRTranscript(session [32]byte, indices []int) *RTranscript {
+	return scheme.NewRTranscript[gnark.G2Affine, *gnark.G2Affine](session, indices)
 }
 
-func (v *VESS) Sign() {}
+// GenerateKey returns a random secret key
+func GenerateKey() (*SecretKey, error) {
+	return scheme.GenerateKey(fr.Modulus())
+}
 
-func (v *VESS) Verify() {}
+func appendG1(dst []byte, p *gnark.G1Affine) []byte {
+	b := p.RawBytes()
+	return append(dst, b[:]...)
+}
 
-func (v *VESS) Adjudicate() {}
+func appendG2(dst []byte, p *gnark.G2Affine) []byte {
+	b := p.RawBytes()
+	return append(dst, b[:]...)
+}
+
+func appendG1Compressed(dst []byte, p *gnark.G1Affine) []byte {
+	b := p.Bytes()
+	return append(dst, b[:]...)
+}
+
+func appendG2Compressed(dst []byte, p *gnark.G2Affine) []byte {
+	b := p.Bytes()
+	return append(dst, b[:]...)
+}
+
+func multiExpG2(points []gnark.G2Affine, scalars []*big.Int) (gnark.G2Affine, error) {
+	res := gnark.G2Affine{}
+	_, err := res.MultiExp(points, toFr(scalars), ecc.MultiExpConfig{ScalarsMont: true})
+	return res, err
+}
+
+// toFr converts scalars to field elements, in Montgomery form
+func toFr(scalars []*big.Int) []fr.Element {
+	res := make([]fr.Element, len(scalars))
+	for i, s := range scalars {
+		res[i].SetBigInt(s)
+	}
+	return res
+}
+
+// Flags in the most significant bits of uncompressed encodings
+const (
+	mMask     byte = 0b111 << 5
+	mInfinity byte = 0b010 << 5
+)
+
+var (
+	errInvalidEncoding = errors.New("invalid point encoding")
+	errNotOnCurve      = errors.New("point not on the curve")
+)
+
+// decodeG1Lenient decodes an uncompressed G1 point, without the subgroup
+// check gnark performs
+func decodeG1Lenient(p *gnark.G1Affine, b []byte) error {
+	if len(b) != gnark.SizeOfG1AffineUncompressed {
+		return scheme.ErrInvalidLength
+	}
+	x := [fp.Bytes]byte{}
+	copy(x[:], b)
+	switch x[0] & mMask {
+	case 0:
+	case mInfinity:
+		*p = gnark.G1Affine{}
+		return nil
+	default:
+		return errInvalidEncoding
+	}
+	x[0] &^= mMask
+
+	p.X.SetBytes(x[:])
+	p.Y.SetBytes(b[fp.Bytes:])
+	if !p.IsOnCurve() {
+		return errNotOnCurve
+	}
+	return nil
+}
+
+// decodeG2Lenient decodes an uncompressed G2 point, without the subgroup
+// check gnark performs. Coordinates are encoded as X.A1 | X.A0 | Y.A1 | Y.A0
+func decodeG2Lenient(p *gnark.G2Affine, b []byte) error {
+	if len(b) != gnark.SizeOfG2AffineUncompressed {
+		return scheme.ErrInvalidLength
+	}
+	x := [fp.Bytes]byte{}
+	copy(x[:], b)
+	switch x[0] & mMask {
+	case 0:
+	case mInfinity:
+		*p = gnark.G2Affine{}
+		return nil
+	default:
+		return errInvalidEncoding
+	}
+	x[0] &^= mMask
+
+	p.X.A1.SetBytes(x[:])
+	p.X.A0.SetBytes(b[fp.Bytes : 2*fp.Bytes])
+	p.Y.A1.SetBytes(b[2*fp.Bytes : 3*fp.Bytes])
+	p.Y.A0.SetBytes(b[3*fp.Bytes:])
+	if !p.IsOnCurve() {
+		return errNotOnCurve
+	}
+	return nil
+}
 
 func Test() error {
 	v, err := New()
@@ -53,172 +300,109 @@ func Test() error {
 	}
 
 	// Alice's keys
-	// Secret (private) key
-	aSKey := fr.Element{}
-	aSKey.SetRandom()
-	// Public key
-	aPKey := gnark.G1Affine{}
-	x := big.Int{}
-	aPKey.ScalarMultiplication(&v.g1, aSKey.ToBigInt(&x))
+	aSKey, err := GenerateKey()
+	if err != nil {
+		return err
+	}
+	aPKey := v.PublicKey(aSKey)
 
-	fmt.Printf("Alice's pubkey (G1): %x\n", aPKey.Marshal())
+	aPKeyPoint := aPKey.Point()
+	fmt.Printf("Alice's pubkey (G1): %x\n", aPKeyPoint.Marshal())
 
 	// Adjudicator's keys
-	// Secret (private) key
-	adjSKey := fr.Element{}
-	adjSKey.SetRandom()
-	adjSKeyInt := big.Int{}
-	adjSKey.ToBigIntRegular(&adjSKeyInt)
-	adjPKeyG1 := gnark.G1Affine{}
-	// Regular public key on G1
-	adjPKeyG1.ScalarMultiplication(&v.g1, &adjSKeyInt)
-	// Public key on G2. Required for Type 3 pairings
-	adjPKeyG2 := gnark.G2Affine{}
-	adjPKeyG2.ScalarMultiplication(&v.g2, &adjSKeyInt)
+	adjSKey, err := GenerateKey()
+	if err != nil {
+		return err
+	}
+	adjPKey := v.AdjudicatorPublicKey(adjSKey)
 
+	adjPKeyG1, adjPKeyG2 := adjPKey.G1(), adjPKey.G2()
 	fmt.Printf("Adjudicator pubkey (G1): %x\nAdjudicator pubkey (G2): %x\n",
 		adjPKeyG1.Marshal(), adjPKeyG2.Marshal())
 
-	// Compute h = H(M), sigma = h^x
 	// Just do a regular BLS signature with Alice's key (on G1)
 	// Use Herumi's library to make sure the signature is properly formatted
 	msg := "Hello, World"
 	ask := bls.SecretKey{}
-	ask.SetDecString(x.String())
+	ask.SetDecString(aSKey.Scalar().String())
 	asig := ask.Sign(msg)
 	sigma := gnark.G2Affine{}
-	sigma.Unmarshal(asig.SerializeUncompressed())
+	if err := convert.G2ToGnark(&sigma, bls.CastFromSign(asig)); err != nil {
+		return err
+	}
 	fmt.Printf("Message: %s\nOriginal signature: %x\n", msg, sigma.Marshal())
 
-	// Select r at random from Zp
-	rel := fr.Element{}
-	rel.SetRandom()
-	r := big.Int{}
-	rel.ToBigInt(&r)
-
-	// Set mu = phi(g2)^r
-	// Note the ETH2 spec swaps the G1 and G2 groups to get smaller public keys
-	// Also note that phi(g2) == g1
-	mu := gnark.G2Affine{}
-	mu.ScalarMultiplication(&v.g2, &r)
-
-	// Set sigma_2 = phi(v')^r
-	// Reminder: phi(v') is is the adjudicator pubkey on G2
-	sigma_2 := gnark.G2Affine{}
-	sigma_2.ScalarMultiplication(&adjPKeyG2, &r)
-
-	// Aggregate sigma and sigma_2 as omega = sigma * sigma_2
-	// In the original paper, G1 is a multiplicative group. Here G1 is additive
-	omega := gnark.G2Affine{}
-	omega.Add(&sigma, &sigma_2)
-
+	vesig, err := v.Sign(aSKey, adjPKey, []byte(msg))
+	if err != nil {
+		return err
+	}
+	omega, mu := vesig.Omega(), vesig.Mu()
 	fmt.Printf("VESig: (%x, %x)\n", omega.Marshal(), mu.Marshal())
-	origOmega := bls.G2{}
-	origOmega.DeserializeUncompressed(omega.Marshal())
-	origMu := bls.G2{}
-	origMu.DeserializeUncompressed(mu.Marshal())
 
 	// Verify
-	// TODO: there are probably more efficient ways to do this check.
-	// Look into how BLS libs do it internally.
-	// Give a public key v, a message M, an a adjudicator's public key v', and
-	// a verifiably encrypted signature (omega, mu); accept if
-	// e(omega, g2) == e(h, v) . e(mu, v')
-	// Friendly reminder that ETH2 swaps G1 and G2
-
-	// e(omega, g2)
-	pair0, _ := gnark.Pair([]gnark.G1Affine{v.g1}, []gnark.G2Affine{omega})
-
-	// h = H(M)
-	h0 := bls.HashAndMapToSignature([]byte(msg))
-	h := gnark.G2Affine{}
-	h.Unmarshal(h0.SerializeUncompressed())
-
-	// e(h, v)
-	pair1, _ := gnark.Pair([]gnark.G1Affine{aPKey}, []gnark.G2Affine{h})
-
-	// e(mu, v')
-	pair2, _ := gnark.Pair([]gnark.G1Affine{adjPKeyG1}, []gnark.G2Affine{mu})
-
-	// e(h, v) * e(mu, v')
-	pair1.Mul(&pair1, &pair2)
-
-	// e(omega, g2) == e(h, v) . e(mu, v') ?
-	if pair0 != pair1 {
+	ok, err := v.Verify(aPKey, adjPKey, []byte(msg), vesig)
+	if err != nil {
+		return err
+	}
+	if !ok {
 		panic("Invalid signature")
 	}
 
 	// Adjudicate
-	// sigma = omega / mu^adjSKey
-	mu.ScalarMultiplication(&mu, &adjSKeyInt)
-	omega.Sub(&omega, &mu)
-	fmt.Printf("Recovered signature: %x\n", omega.Marshal())
+	adjudicated := v.Adjudicate(adjSKey, vesig)
+	recovered := adjudicated.Point()
+	fmt.Printf("Recovered signature: %x\n", recovered.Marshal())
 
-	// TODO: and this one
-	if sigma != omega {
+	if sigma != recovered {
 		panic("Recovered signature does not match original signature")
 	}
 	fmt.Println("Recovered signature matches!")
 
-	// Try do recover the original signature, but with split keys (n-of-m scheme)
-	minShares := 3
-	totalShares := 10
-
-	// Create a polynomial for the "threshold adjudicator" private key with degree n-1
-	// This will require n shares out of m to reconstruct the key (n-of-m)
-	adjKeyPoly := make([]bls.Fr, minShares)
-	adjsk := bls.Fr{}
-	adjsk.Deserialize(adjSKey.Marshal())
-
-	// The free coefficient is the original private key
-	adjKeyPoly[0] = adjsk
+	ok, err = v.VerifyRecovered(aPKey, []byte(msg), adjudicated)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		panic("Recovered signature is not valid")
+	}
 
-	// Random secret key coefficients
-	for i := 1; i < minShares; i++ {
-		fr := bls.Fr{}
-		fr.SetByCSPRNG()
-		adjKeyPoly[i] = fr
+	// The recovered signature must be a valid Ethereum BLS signature
+	ok, err = v.VerifyETH(aPKey, []byte(msg), adjudicated)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		panic("Recovered signature is not a valid Ethereum signature")
 	}
 
-	// Evaluate the polynomial at m points (the key shares)
-	// Note that we're creating the shares from a single private key
-	// In a real setting, a DKG protocol would probably be used
-	shares := make([]bls.Fr, totalShares)
-	for i := 0; i < len(shares); i++ {
-		x := bls.Fr{}
-		x.SetInt64(int64(i + 1))
-		y := bls.Fr{}
-		bls.FrEvaluatePolynomial(&y, adjKeyPoly, &x)
-		shares[i] = y
+	// Try do recover the original signature, but with split keys (n-of-m scheme)
+	minShares := 3
+	totalShares := 10
+	shares, err := v.SplitKey(adjSKey, minShares, totalShares)
+	if err != nil {
+		return err
 	}
 
 	// At this point, each member would already have its key share
 
 	// Try to calculate mu^adjKey, using n shares
 	// We're just using the first n, but it can be any n valid shares
-	mub := bls.G2{}
-	mub.Deserialize(origMu.Serialize())
-	res := make([]bls.G2, minShares)
-	for i := 0; i < len(res); i++ {
-		bls.G2Mul(&res[i], &mub, &shares[i])
+	indices := make([]int, minShares)
+	partials := make([]*Partial, minShares)
+	for i := range partials {
+		indices[i] = i + 1
+		partials[i] = v.PartialAdjudicate(shares[i], vesig)
 	}
-
-	// Lagrange interpolation with n points to recover mu^adjKey
-	xs := make([]bls.Fr, minShares)
-	for i := 0; i < len(xs); i++ {
-		xs[i] = bls.Fr{}
-		xs[i].SetInt64(int64(i + 1))
-	}
-	err = bls.G2LagrangeInterpolation(&mub, xs, res)
+	combined, err := v.Combine(vesig, indices, partials)
 	if err != nil {
 		return err
 	}
+	recovered = combined.Point()
+	fmt.Printf("Recovered signature (n-of-m): %x\n", recovered.Marshal())
 
-	// Final adjudication step
-	omegab := bls.G2{}
-	omegab.DeserializeUncompressed(origOmega.SerializeUncompressed())
-	bls.G2Sub(&omegab, &omegab, &mub)
-	fmt.Printf("Recovered signature (n-of-m): %x\n", omegab.SerializeUncompressed())
+	if sigma != recovered {
+		panic("Recovered signature (n-of-m) does not match original signature")
+	}
 
 	return nil
 }
@@ -0,0 +1,113 @@
+package vess
+
+import (
+	"errors"
+	"testing"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// outsideSubgroup returns a point on the G2 curve, y² = x³ + 4(1+i), that is
+// not in the prime order subgroup
+func outsideSubgroup(t *testing.T) gnark.G2Affine {
+	p := gnark.G2Affine{}
+	for i := uint64(1); i < 1000; i++ {
+		p.X.A0.SetUint64(i)
+		p.X.A1.SetUint64(1)
+		b := p.X
+		b.A0.SetUint64(4)
+		b.A1.SetUint64(4)
+		rhs := p.X
+		rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, &b)
+		if rhs.Legendre() != 1 {
+			continue
+		}
+		p.Y.Sqrt(&rhs)
+		if p.IsOnCurve() && !p.IsInSubGroup() {
+			return p
+		}
+	}
+	t.Fatal("no point found")
+	return p
+}
+
+func TestMigrate(t *testing.T) {
+	v, _, sig := fixture(t)
+
+	b, err := v.Migrate(sig.Marshal(), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := v.DecodeVESig(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(sig) {
+		t.Fatal("migrated escrow differs")
+	}
+}
+
+func TestMigrateRejectsPointsOutsideSubgroup(t *testing.T) {
+	v, _, sig := fixture(t)
+	p := outsideSubgroup(t)
+	omega := p.RawBytes()
+	mu := sig.Mu()
+	legacy := append(omega[:], mu.Marshal()...)
+
+	if _, err := v.UnmarshalVESig(legacy, Lenient); err != nil {
+		t.Fatalf("lenient decoding failed: %v", err)
+	}
+	if _, err := v.Migrate(legacy, Lenient); !errors.Is(err, scheme.ErrInvalidPoint) {
+		t.Fatalf("got %v, want %v", err, scheme.ErrInvalidPoint)
+	}
+	if _, err := v.Migrate(legacy, Strict); err == nil {
+		t.Fatal("strict migration accepted a point outside the subgroup")
+	}
+}
+
+func TestDecodeRejectsInvalidKeys(t *testing.T) {
+	v := newVESS(t)
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Versioned encodings of the identity: flags, then zeros
+	pk := v.EncodePublicKey(v.PublicKey(sk))
+	copy(pk[scheme.HeaderSize:], infinity(gnark.SizeOfG1AffineCompressed, gnark.SizeOfG1AffineCompressed, 0xc0))
+	if _, err := v.DecodePublicKey(pk); !errors.Is(err, scheme.ErrIdentity) {
+		t.Errorf("public key: got %v, want %v", err, scheme.ErrIdentity)
+	}
+	sig := v.EncodeSignature(v.Adjudicate(sk, mustSign(t, v, sk)))
+	copy(sig[scheme.HeaderSize:], infinity(gnark.SizeOfG2AffineCompressed, gnark.SizeOfG2AffineCompressed, 0xc0))
+	if _, err := v.DecodeSignature(sig); !errors.Is(err, scheme.ErrIdentity) {
+		t.Errorf("signature: got %v, want %v", err, scheme.ErrIdentity)
+	}
+	apk := v.EncodeAdjudicatorPublicKey(v.AdjudicatorPublicKey(sk))
+	identity := append([]byte{}, apk...)
+	copy(identity[scheme.HeaderSize:], infinity(gnark.SizeOfG1AffineCompressed, gnark.SizeOfG1AffineCompressed, 0xc0))
+	if _, err := v.DecodeAdjudicatorPublicKey(identity); !errors.Is(err, scheme.ErrIdentity) {
+		t.Errorf("adjudicator key: got %v, want %v", err, scheme.ErrIdentity)
+	}
+
+	// G1 half of sk, G2 half of other
+	n := scheme.HeaderSize + gnark.SizeOfG1AffineCompressed
+	copy(apk[n:], v.EncodeAdjudicatorPublicKey(v.AdjudicatorPublicKey(other))[n:])
+	if _, err := v.DecodeAdjudicatorPublicKey(apk); !errors.Is(err, scheme.ErrKeyMismatch) {
+		t.Errorf("mismatched adjudicator key: got %v, want %v", err, scheme.ErrKeyMismatch)
+	}
+}
+
+func mustSign(t *testing.T, v *VESS, sk *SecretKey) *VESig {
+	sig, err := v.Sign(sk, v.AdjudicatorPublicKey(sk), []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
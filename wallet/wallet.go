@@ -0,0 +1,244 @@
+// Package wallet keeps a signer-local record of the escrows a signer has
+// made: which message, to which adjudicator, until when, and what became of
+// it. It holds no keys nor signatures, only what is needed to answer "what
+// have I escrowed and to whom"
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+var (
+	ErrDuplicate     = errors.New("wallet: escrow already recorded")
+	ErrNotFound      = errors.New("wallet: no such escrow")
+	ErrInvalidStatus = errors.New("wallet: invalid status")
+	ErrFormat        = errors.New("wallet: unknown export format")
+)
+
+// Status is the state of an escrow
+type Status string
+
+const (
+	Pending Status = "pending"
+	// Released escrows were settled by handing over the plain signature
+	Released    Status = "released"
+	Adjudicated Status = "adjudicated"
+	// Expired is reported for pending escrows past their expiry, and never
+	// stored
+	Expired Status = "expired"
+)
+
+// Entry records one escrow
+type Entry struct {
+	// Digest is the hex SHA-256 of the escrowed message
+	Digest      string `json:"digest"`
+	Adjudicator string `json:"adjudicator"`
+	Created     int64  `json:"created"`
+	// Expiry is a Unix time, or zero if the escrow does not expire
+	Expiry int64  `json:"expiry,omitempty"`
+	Status Status `json:"status"`
+	Label  string `json:"label,omitempty"`
+}
+
+// NewEntry returns a pending entry for an escrow of msg to adjudicator
+func NewEntry(msg []byte, adjudicator scheme.Fingerprint, expiry, now time.Time, label string) Entry {
+	d := sha256.Sum256(msg)
+	e := Entry{
+		Digest:      hex.EncodeToString(d[:]),
+		Adjudicator: adjudicator.String(),
+		Created:     now.Unix(),
+		Status:      Pending,
+		Label:       label,
+	}
+	if !expiry.IsZero() {
+		e.Expiry = expiry.Unix()
+	}
+	return e
+}
+
+// StatusAt returns the status of e at time now
+func (e *Entry) StatusAt(now time.Time) Status {
+	if e.Status == Pending && e.Expiry != 0 && now.Unix() >= e.Expiry {
+		return Expired
+	}
+	return e.Status
+}
+
+// Store is a file of entries. It is safe for concurrent use within a
+// process
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	entries []Entry
+}
+
+// Open loads the store at path, which need not exist yet
+func Open(path string) (*Store, error) {
+	s := Store{path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.entries); err != nil {
+		return nil, fmt.Errorf("wallet: %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func (s *Store) find(digest, adjudicator string) int {
+	for i := range s.entries {
+		if s.entries[i].Digest == digest && s.entries[i].Adjudicator == adjudicator {
+			return i
+		}
+	}
+	return -1
+}
+
+// Add records a new escrow. The same message may be escrowed to several
+// adjudicators, but only once to each
+func (s *Store) Add(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.Status != Pending && e.Status != Released && e.Status != Adjudicated {
+		return ErrInvalidStatus
+	}
+	if s.find(e.Digest, e.Adjudicator) >= 0 {
+		return ErrDuplicate
+	}
+	entries := make([]Entry, len(s.entries), len(s.entries)+1)
+	copy(entries, s.entries)
+	return s.commit(append(entries, e))
+}
+
+// SetStatus records that the escrow of digest to adjudicator was released
+// or adjudicated
+func (s *Store) SetStatus(digest, adjudicator string, status Status) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status != Released && status != Adjudicated {
+		return ErrInvalidStatus
+	}
+	i := s.find(digest, adjudicator)
+	if i < 0 {
+		return ErrNotFound
+	}
+	entries := append([]Entry{}, s.entries...)
+	entries[i].Status = status
+	return s.commit(entries)
+}
+
+// List returns the entries, oldest first, with their status at time now
+func (s *Store) List(now time.Time) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]Entry, len(s.entries))
+	copy(res, s.entries)
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Created < res[j].Created })
+	for i := range res {
+		res[i].Status = res[i].StatusAt(now)
+	}
+	return res
+}
+
+// commit saves entries and makes them the entries of the store. If saving
+// fails, the store is left as it was, in memory as on disk
+func (s *Store) commit(entries []Entry) error {
+	if err := s.save(entries); err != nil {
+		return err
+	}
+	s.entries = entries
+	return nil
+}
+
+// save writes entries to a temporary file, renamed over the store
+func (s *Store) save(entries []Entry) error {
+	b, err := json.MarshalIndent(entries, "", "\t")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), ".wallet-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
+
+// Export writes entries as format json (one object per line) or csv
+func Export(w io.Writer, entries []Entry, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		for i := range entries {
+			if err := enc.Encode(&entries[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"digest", "adjudicator", "created", "expiry", "status", "label"})
+		for _, e := range entries {
+			cw.Write([]string{
+				e.Digest, e.Adjudicator,
+				formatTime(e.Created), formatTime(e.Expiry),
+				string(e.Status), e.Label,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return ErrFormat
+}
+
+// Print lists entries in a table, for the CLI
+func Print(w io.Writer, entries []Entry) {
+	for _, e := range entries {
+		expiry := "-"
+		if e.Expiry != 0 {
+			expiry = formatTime(e.Expiry)
+		}
+		fmt.Fprintf(w, "%-11s %s %s %s %s %s\n",
+			e.Status, e.Digest, e.Adjudicator, formatTime(e.Created), expiry, e.Label)
+	}
+}
+
+func formatTime(t int64) string {
+	if t == 0 {
+		return ""
+	}
+	return time.Unix(t, 0).UTC().Format(time.RFC3339)
+}
+
+// ParseStatus parses a status given on the command line
+func ParseStatus(s string) (Status, error) {
+	switch st := Status(s); st {
+	case Released, Adjudicated:
+		return st, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidStatus, strconv.Quote(s))
+}
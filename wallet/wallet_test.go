@@ -0,0 +1,42 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/poupas/bls-vess/internal/scheme"
+)
+
+// A store whose directory is gone fails to save
+func TestStoreUnchangedWhenSaveFails(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "wallet")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(filepath.Join(dir, "wallet.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1000, 0)
+	e := NewEntry([]byte("escrow"), scheme.Fingerprint{1}, time.Time{}, now, "")
+	if err := s.Add(e); err != nil {
+		t.Fatal(err)
+	}
+	want := s.List(now)
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Add(NewEntry([]byte("other"), scheme.Fingerprint{1}, time.Time{}, now, "")); err == nil {
+		t.Fatal("save did not fail")
+	}
+	if err := s.SetStatus(e.Digest, e.Adjudicator, Released); err == nil {
+		t.Fatal("save did not fail")
+	}
+	if got := s.List(now); !reflect.DeepEqual(got, want) {
+		t.Fatalf("store changed by failed saves: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+//go:build js && wasm
+
+// JavaScript bindings for the BN254 instantiation of the scheme
+//
+// Build with GOOS=js GOARCH=wasm. All functions are registered under the
+// global "vess" object and take and return Uint8Arrays. Errors are returned
+// as JavaScript Error values, with the code and retryable properties
+// of package errcode.
+// BLS12-381 is not available: package vess relies on herumi (cgo) to hash
+// messages with the Ethereum ciphersuite
+
+package main
+
+import (
+	"errors"
+	"syscall/js"
+
+	"github.com/poupas/bls-vess/bn254"
+	"github.com/poupas/bls-vess/errcode"
+)
+
+var v *bn254.VESS
+
+func bytesFromJS(val js.Value) []byte {
+	b := make([]byte, val.Get("length").Int())
+	js.CopyBytesToGo(b, val)
+	return b
+}
+
+func bytesToJS(b []byte) js.Value {
+	val := js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(val, b)
+	return val
+}
+
+// jsError returns an Error carrying the stable code of err, see
+// package errcode
+func jsError(err error) js.Value {
+	c := errcode.Of(err)
+	e := js.Global().Get("Error").New(err.Error())
+	e.Set("code", int(c))
+	e.Set("retryable", c.Retryable())
+	return e
+}
+
+// wrap registers f, checking the number of arguments
+func wrap(nargs int, f func(args []js.Value) (js.Value, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) != nargs {
+			return jsError(errors.New("invalid number of arguments"))
+		}
+		res, err := f(args)
+		if err != nil {
+			return jsError(err)
+		}
+		return res
+	})
+}
+
+// generateKey() -> secret key
+func generateKey(args []js.Value) (js.Value, error) {
+	sk, err := bn254.GenerateKey()
+	if err != nil {
+		return js.Undefined(), err
+	}
+	return bytesToJS(sk.Marshal()), nil
+}
+
+// publicKey(sk) -> signer public key
+func publicKey(args []js.Value) (js.Value, error) {
+	sk, err := v.SecretKeyFromBytes(bytesFromJS(args[0]))
+	if err != nil {
+		return js.Undefined(), err
+	}
+	return bytesToJS(v.PublicKey(sk).Marshal()), nil
+}
+
+// adjudicatorPublicKey(sk) -> adjudicator public key
+func adjudicatorPublicKey(args []js.Value) (js.Value, error) {
+	sk, err := v.SecretKeyFromBytes(bytesFromJS(args[0]))
+	if err != nil {
+		return js.Undefined(), err
+	}
+	return bytesToJS(v.AdjudicatorPublicKey(sk).Marshal()), nil
+}
+
+// sign(sk, adjPK, msg) -> VESig
+func sign(args []js.Value) (js.Value, error) {
+	sk, err := v.SecretKeyFromBytes(bytesFromJS(args[0]))
+	if err != nil {
+		return js.Undefined(), err
+	}
+	adj := bn254.AdjudicatorPublicKey{}
+	if err := adj.Unmarshal(bytesFromJS(args[1])); err != nil {
+		return js.Undefined(), err
+	}
+	sig, err := v.Sign(sk, &adj, bytesFromJS(args[2]))
+	if err != nil {
+		return js.Undefined(), err
+	}
+	return bytesToJS(sig.Marshal()), nil
+}
+
+// verify(pk, adjPK, msg, sig) -> bool
+func verify(args []js.Value) (js.Value, error) {
+	pk := bn254.PublicKey{}
+	if err := pk.Unmarshal(bytesFromJS(args[0])); err != nil {
+		return js.Undefined(), err
+	}
+	adj := bn254.AdjudicatorPublicKey{}
+	if err := adj.Unmarshal(bytesFromJS(args[1])); err != nil {
+		return js.Undefined(), err
+	}
+	sig := bn254.VESig{}
+	if err := sig.Unmarshal(bytesFromJS(args[3])); err != nil {
+		return js.Undefined(), err
+	}
+	ok, err := v.Verify(&pk, &adj, bytesFromJS(args[2]), &sig)
+	if err != nil {
+		return js.Undefined(), err
+	}
+	return js.ValueOf(ok), nil
+}
+
+// adjudicate(adjSK, sig) -> signature
+func adjudicate(args []js.Value) (js.Value, error) {
+	sk, err := v.SecretKeyFromBytes(bytesFromJS(args[0]))
+	if err != nil {
+		return js.Undefined(), err
+	}
+	sig := bn254.VESig{}
+	if err := sig.Unmarshal(bytesFromJS(args[1])); err != nil {
+		return js.Undefined(), err
+	}
+	return bytesToJS(v.Adjudicate(sk, &sig).Marshal()), nil
+}
+
+func main() {
+	var err error
+	if v, err = bn254.New(); err != nil {
+		panic(err)
+	}
+
+	js.Global().Set("vess", js.ValueOf(map[string]interface{}{
+		"generateKey":          wrap(0, generateKey),
+		"publicKey":            wrap(1, publicKey),
+		"adjudicatorPublicKey": wrap(1, adjudicatorPublicKey),
+		"sign":                 wrap(3, sign),
+		"verify":               wrap(4, verify),
+		"adjudicate":           wrap(2, adjudicate),
+	}))
+
+	// Keep the exported functions alive
+	select {}
+}